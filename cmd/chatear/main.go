@@ -0,0 +1,60 @@
+// Command chatear runs the Chatear API, its background workers and its database migrations.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+)
+
+// command is a subcommand of chatear, named by one or more words.
+type command struct {
+	name        string
+	description string
+	run         func(cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{"serve", "Run the HTTP and GraphQL API", serve},
+	{"worker notifications", "Send the emails requested on email.send", workerNotifications},
+	{"worker deletions", "Run the user-deletions job", workerDeletions},
+	{"worker hard-delete", "Run the user-hard-delete, expired-token-cleanup and log-cleanup jobs", workerHardDelete},
+	{"worker data-exports", "Assemble the personal data exports requested by users", workerDataExports},
+	{"migrate", "Apply (up, the default) or revert (down [N]) the PostgreSQL migrations, or show (version) or set (force VERSION) the schema version", migrate},
+}
+
+func main() {
+	cmd, args, ok := findCommand(os.Args[1:])
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	if err := cmd.run(cfg, args); err != nil {
+		log.Fatalf("Error: %s: %v", cmd.name, err)
+	}
+}
+
+// findCommand returns the command named by the first words of args and the remaining arguments.
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: chatear <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", cmd.name, cmd.description)
+	}
+}
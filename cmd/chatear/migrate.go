@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/migrations"
+)
+
+// migrate applies or reverts the PostgreSQL migrations embedded in the binary.
+func migrate(cfg *config.Config, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	loaded, err := infrastructure.LoadMigrations(migrations.Postgres, "postgres")
+	if err != nil {
+		return err
+	}
+
+	db, err := infrastructure.NewPostgresDB(cfg.SupabaseConnectionString)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := notifyShutdown()
+	defer stop()
+	migrator := infrastructure.NewMigrator(db.Pool, loaded)
+
+	switch {
+	case action == "up" && len(args) == 0:
+		applied, err := migrator.Up(ctx)
+		for _, version := range applied {
+			log.Printf("Applied migration %d", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("No migration to apply")
+		}
+
+	case action == "down" && len(args) <= 1:
+		steps := 1
+		if len(args) == 1 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, version := range reverted {
+			log.Printf("Reverted migration %d", version)
+		}
+		if err != nil {
+			return err
+		}
+
+	case action == "version" && len(args) == 0:
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+
+	case action == "force" && len(args) == 1:
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			return err
+		}
+		log.Printf("Schema version set to %d", version)
+
+	default:
+		return fmt.Errorf("unknown arguments %q, expected up, down [N], version or force VERSION", append([]string{action}, args...))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/pkg/api"
+)
+
+// serve runs the API until SIGINT or SIGTERM, then stops accepting connections, waits for the
+// requests in progress and flushes the events they published.
+func serve(cfg *config.Config, args []string) error {
+	infra, err := infrastructure.NewInfrastructure(cfg.SupabaseConnectionString, cfg.RedisURL, cfg.NatsURL)
+	if err != nil {
+		return fmt.Errorf("error initializing infrastructure: %w", err)
+	}
+	defer infra.Close()
+
+	r, err := api.SetupServer(cfg, infra)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: r}
+
+	ctx, stop := notifyShutdown()
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	log.Printf("🚀 Chatear Backend running on :%d", cfg.Port)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down, draining HTTP connections...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain HTTP connections: %w", err))
+	}
+	if err := drainNATS(shutdownCtx, infra.NatsConn); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	log.Println("Chatear Backend stopped")
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// notifyShutdown returns a context that is done on SIGINT or SIGTERM.
+func notifyShutdown() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// workContext returns a context for the work in progress, which keeps going when ctx is done and
+// is cancelled timeout later, so that work started before a shutdown can finish within a deadline.
+func workContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	workCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-workCtx.Done():
+			return
+		case <-ctx.Done():
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-workCtx.Done():
+		case <-timer.C:
+			cancel()
+		}
+	}()
+	return workCtx, cancel
+}
+
+// drainNATS lets the subscriptions of conn handle the messages already received, flushes the
+// messages published and closes conn, waiting at most until ctx is done.
+func drainNATS(ctx context.Context, conn *nats.Conn) error {
+	if conn == nil || conn.IsClosed() {
+		return nil
+	}
+
+	closed := make(chan struct{})
+	conn.SetClosedHandler(func(*nats.Conn) { close(closed) })
+	if err := conn.Drain(); err != nil {
+		return fmt.Errorf("failed to drain NATS: %w", err)
+	}
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to drain NATS: %w", ctx.Err())
+	}
+}
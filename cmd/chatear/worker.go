@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	schedulerApp "github.com/jefersonprimer/chatear-backend/internal/scheduler/application"
+	schedulerInfra "github.com/jefersonprimer/chatear-backend/internal/scheduler/infrastructure"
+)
+
+// connectWorker initializes the infrastructure of a worker, which needs PostgreSQL and NATS, and
+// Redis if needsRedis is set.
+func connectWorker(cfg *config.Config, worker string, needsRedis bool) (*infrastructure.Infrastructure, error) {
+	infra, err := infrastructure.NewInfrastructure(cfg.SupabaseConnectionString, cfg.RedisURL, cfg.NatsURL)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing infrastructure: %w", err)
+	}
+
+	switch {
+	case infra.Postgres == nil:
+		err = fmt.Errorf("PostgreSQL is required by the %s worker", worker)
+	case infra.NatsConn == nil:
+		err = fmt.Errorf("NATS is required by the %s worker", worker)
+	case needsRedis && infra.Redis == nil:
+		err = fmt.Errorf("Redis is required by the %s worker", worker)
+	}
+	if err != nil {
+		infra.Close()
+		return nil, err
+	}
+	return infra, nil
+}
+
+// newScheduler creates a scheduler recording its runs in PostgreSQL and electing the replica
+// running each job through Redis.
+func newScheduler(cfg *config.Config, infra *infrastructure.Infrastructure) *schedulerApp.Scheduler {
+	scheduler := schedulerApp.NewScheduler(
+		schedulerInfra.NewPostgresJobDefinitionRepository(infra.Postgres.Pool),
+		schedulerInfra.NewPostgresJobRunRepository(infra.Postgres.Pool),
+		schedulerInfra.NewRedisJobLocker(infra.Redis, cfg.WorkerLeaseTTL),
+	)
+	scheduler.ShutdownTimeout = cfg.ShutdownTimeout
+	return scheduler
+}
+
+// runScheduler runs the jobs of scheduler and their manual triggers until SIGINT or SIGTERM, then
+// waits for the runs in progress and flushes the events they published.
+func runScheduler(cfg *config.Config, infra *infrastructure.Infrastructure, scheduler *schedulerApp.Scheduler, worker string) error {
+	ctx, stop := notifyShutdown()
+	defer stop()
+
+	if _, err := schedulerInfra.SubscribeJobTriggers(ctx, infra.NatsConn, scheduler); err != nil {
+		return err
+	}
+
+	log.Printf("%s worker started", worker)
+	if err := scheduler.Run(ctx); err != nil {
+		return err
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := drainNATS(drainCtx, infra.NatsConn); err != nil {
+		return err
+	}
+	log.Printf("%s worker stopped", worker)
+	return nil
+}
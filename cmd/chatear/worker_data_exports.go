@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	userApp "github.com/jefersonprimer/chatear-backend/internal/user/application"
+	userInfra "github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/nats-io/nats.go"
+)
+
+// dataExportTimeout bounds the time spent assembling and storing a single export.
+const dataExportTimeout = 5 * time.Minute
+
+// workerDataExports assembles the personal data exports requested on user.data_export.requested.
+func workerDataExports(cfg *config.Config, args []string) error {
+	if cfg.BlobSigningKey == "" {
+		return errors.New("BLOB_SIGNING_KEY is required by the data export worker")
+	}
+
+	infra, err := connectWorker(cfg, "data export", false)
+	if err != nil {
+		return err
+	}
+	defer infra.Close()
+
+	blobStore, err := userInfra.NewLocalBlobStore(cfg.BlobStoragePath, cfg.BlobPublicURL, []byte(cfg.BlobSigningKey))
+	if err != nil {
+		return fmt.Errorf("error initializing blob store: %w", err)
+	}
+
+	exportUserData := userApp.NewExportUserData(
+		userInfra.NewPostgresUserDataExportRepository(infra.Postgres.Pool),
+		blobStore,
+		userInfra.NewPostgresActionLogRepository(infra.Postgres.Pool),
+		userInfra.NewNATSEventBus(infra.NatsConn),
+		cfg.DataExportLinkTTL,
+	)
+
+	ctx, stop := notifyShutdown()
+	defer stop()
+	// Exports in progress are not cancelled on shutdown, so that the requesting user still gets their link
+	workCtx, cancelWork := workContext(ctx, cfg.ShutdownTimeout)
+	defer cancelWork()
+
+	// A queue group delivers each request to a single worker instance
+	_, err = infra.NatsConn.QueueSubscribe("user.data_export.requested", "data-export-worker", func(msg *nats.Msg) {
+		var event events.DataExportRequestedEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Printf("Error decoding data export request: %v", err)
+			return
+		}
+
+		exportCtx, cancel := context.WithTimeout(workCtx, dataExportTimeout)
+		defer cancel()
+		if err := exportUserData.Execute(exportCtx, event); err != nil {
+			log.Printf("Error exporting data of user %s: %v", event.UserID, err)
+			return
+		}
+		log.Printf("Exported data of user %s", event.UserID)
+	})
+	if err != nil {
+		return fmt.Errorf("error subscribing to data export requests: %w", err)
+	}
+
+	log.Println("Data export worker started")
+	<-ctx.Done()
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := drainNATS(drainCtx, infra.NatsConn); err != nil {
+		return err
+	}
+	log.Println("Data export worker stopped")
+	return nil
+}
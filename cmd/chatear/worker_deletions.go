@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	schedulerDomain "github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	userApp "github.com/jefersonprimer/chatear-backend/internal/user/application"
+	userInfra "github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+)
+
+// workerDeletions runs the user-deletions job, which advances account deletion requests.
+func workerDeletions(cfg *config.Config, args []string) error {
+	infra, err := connectWorker(cfg, "user deletion", true)
+	if err != nil {
+		return err
+	}
+	defer infra.Close()
+
+	processUserDeletions := userApp.NewProcessUserDeletions(
+		userInfra.NewPostgresUserDeletionRepository(infra.Postgres.Pool),
+		userInfra.NewNATSEventBus(infra.NatsConn),
+		cfg.DeletionPolicy(),
+	)
+
+	scheduler := newScheduler(cfg, infra)
+	job, err := schedulerDomain.NewJob("user-deletions", cfg.UserDeletionJobSchedule, time.Hour, schedulerDomain.ConcurrencyForbid, func(ctx context.Context) error {
+		return processUserDeletions.Execute(ctx, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	if err := scheduler.Register(job); err != nil {
+		return err
+	}
+
+	return runScheduler(cfg, infra, scheduler, "User deletion")
+}
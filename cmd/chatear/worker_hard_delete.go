@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	schedulerDomain "github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	userApp "github.com/jefersonprimer/chatear-backend/internal/user/application"
+	userInfra "github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+)
+
+// workerHardDelete runs the user-hard-delete, expired-token-cleanup and log-cleanup jobs.
+func workerHardDelete(cfg *config.Config, args []string) error {
+	if cfg.AnonymizationKey == "" {
+		return errors.New("ANONYMIZATION_KEY is required by the user hard delete worker")
+	}
+
+	infra, err := connectWorker(cfg, "user hard delete", true)
+	if err != nil {
+		return err
+	}
+	defer infra.Close()
+
+	blobStore, err := userInfra.NewLocalBlobStore(cfg.BlobStoragePath, cfg.BlobPublicURL, []byte(cfg.BlobSigningKey))
+	if err != nil {
+		return fmt.Errorf("error initializing blob store: %w", err)
+	}
+
+	hardDeleteUsers := userApp.NewHardDeleteUsers(
+		userInfra.NewPostgresUserRepository(infra.Postgres.Pool),
+		blobStore,
+		userInfra.NewNATSEventBus(infra.NatsConn),
+		[]byte(cfg.AnonymizationKey),
+	)
+	dataRetentionRepository := userInfra.NewPostgresDataRetentionRepository(infra.Postgres.Pool)
+	purgeExpiredTokens := userApp.NewPurgeExpiredTokens(dataRetentionRepository)
+	deleteOldLogs := userApp.NewDeleteOldLogs(dataRetentionRepository, cfg.LogRetentionPeriod)
+
+	scheduler := newScheduler(cfg, infra)
+	jobs := []struct {
+		name     string
+		schedule string
+		run      schedulerDomain.JobFunc
+	}{
+		{"user-hard-delete", cfg.HardDeleteJobSchedule, func(ctx context.Context) error {
+			return hardDeleteUsers.Execute(ctx, time.Now())
+		}},
+		{"expired-token-cleanup", cfg.TokenCleanupJobSchedule, func(ctx context.Context) error {
+			deleted, err := purgeExpiredTokens.Execute(ctx, time.Now())
+			log.Printf("Deleted %d expired refresh tokens and magic links", deleted)
+			return err
+		}},
+		{"log-cleanup", cfg.LogCleanupJobSchedule, func(ctx context.Context) error {
+			deleted, err := deleteOldLogs.Execute(ctx, time.Now())
+			log.Printf("Deleted %d login history entries and action logs past retention", deleted)
+			return err
+		}},
+	}
+	for _, j := range jobs {
+		job, err := schedulerDomain.NewJob(j.name, j.schedule, time.Hour, schedulerDomain.ConcurrencyForbid, j.run)
+		if err != nil {
+			return err
+		}
+		if err := scheduler.Register(job); err != nil {
+			return err
+		}
+	}
+
+	return runScheduler(cfg, infra, scheduler, "User hard delete")
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	notification_app "github.com/jefersonprimer/chatear-backend/internal/notification/application"
+	notification_domain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	notification_infra "github.com/jefersonprimer/chatear-backend/internal/notification/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/worker"
+	schedulerApp "github.com/jefersonprimer/chatear-backend/internal/scheduler/application"
+	schedulerDomain "github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	schedulerInfra "github.com/jefersonprimer/chatear-backend/internal/scheduler/infrastructure"
+	presentation_http "github.com/jefersonprimer/chatear-backend/presentation/http"
+)
+
+// workerNotifications sends the emails requested on email.send and cancels those cancelled on
+// email.cancel.
+func workerNotifications(cfg *config.Config, args []string) error {
+	if cfg.RedisURL == "" {
+		return errors.New("REDIS_URL environment variable not set")
+	}
+	if cfg.NatsURL == "" {
+		return errors.New("NATS_URL environment variable not set")
+	}
+	if cfg.UnsubscribeSigningKey == "" {
+		return errors.New("UNSUBSCRIBE_SIGNING_KEY is required by the notification worker")
+	}
+
+	infra, err := connectWorker(cfg, "notification", true)
+	if err != nil {
+		return err
+	}
+	defer infra.Close()
+
+	notificationRepository := notification_infra.NewPostgresRepository(infra.Postgres.Pool)
+	sender, err := notification_infra.NewSender(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating %s email sender: %w", cfg.EmailTransport, err)
+	}
+	if closer, ok := sender.(io.Closer); ok {
+		defer closer.Close()
+	}
+	renderer, err := notification_infra.NewTemplateRenderer()
+	if err != nil {
+		return fmt.Errorf("error loading email templates: %w", err)
+	}
+	preferences := notification_app.NewPreferences(
+		notification_infra.NewPostgresPreferenceRepository(infra.Postgres.Pool),
+		notification_domain.NewUnsubscribeTokens([]byte(cfg.UnsubscribeSigningKey)),
+		cfg.UnsubscribeURL,
+	)
+	suppressions := notification_app.NewSuppressions(
+		notification_infra.NewPostgresSuppressionRepository(infra.Postgres.Pool),
+		cfg.SuppressionPolicy(),
+	)
+	deferredQueue := notification_infra.NewRedisDeferredQueue(infra.Redis)
+	emailSender := notification_app.NewEmailSender(
+		notificationRepository,
+		notification_infra.NewRedisDeliveryClaims(infra.Redis),
+		notification_infra.NewRedisRateLimiter(infra.Redis),
+		deferredQueue,
+		renderer,
+		sender,
+		preferences,
+		suppressions,
+		cfg.EmailDedupWindow,
+		cfg.EmailRateLimitPolicy(),
+	)
+
+	natsConsumer, err := worker.NewNatsEmailConsumer(infra.NatsConn, emailSender)
+	if err != nil {
+		return fmt.Errorf("error creating NATS consumer: %w", err)
+	}
+
+	// The bounce mailbox, when there is one, is read by the bounce-mailbox job
+	var scheduler *schedulerApp.Scheduler
+	if cfg.BounceMailboxDir != "" {
+		mailbox, err := notification_infra.NewMaildirBounceMailbox(cfg.BounceMailboxDir)
+		if err != nil {
+			return err
+		}
+		job, err := schedulerDomain.NewJob("bounce-mailbox", cfg.BounceMailboxJobSchedule, 10*time.Minute, schedulerDomain.ConcurrencyForbid, func(ctx context.Context) error {
+			processed, err := suppressions.ProcessMailbox(ctx, mailbox)
+			if processed > 0 {
+				log.Printf("Processed %d bounce reports", processed)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		scheduler = newScheduler(cfg, infra)
+		if err := scheduler.Register(job); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := notifyShutdown()
+	defer stop()
+	// Emails being sent when the worker is asked to stop are still sent
+	workCtx, cancelWork := workContext(ctx, cfg.ShutdownTimeout)
+	defer cancelWork()
+
+	var mailboxServer *http.Server
+	if mailbox, ok := sender.(notification_domain.Mailbox); ok {
+		mailboxServer = serveMailbox(cfg.EmailCaptureAddr, mailbox)
+	}
+
+	log.Printf("Starting notification worker with the %s email transport...", cfg.EmailTransport)
+	if err := natsConsumer.Start(workCtx); err != nil {
+		return err
+	}
+	// Deferred emails are published again until the worker is asked to stop, before NATS is drained
+	dispatched := make(chan struct{})
+	go func() {
+		defer close(dispatched)
+		worker.NewDeferredEmailDispatcher(infra.NatsConn, deferredQueue).Run(ctx)
+	}()
+	// The scheduler runs until the worker is asked to stop, then waits for the run in progress
+	if scheduler != nil {
+		if _, err := schedulerInfra.SubscribeJobTriggers(ctx, infra.NatsConn, scheduler); err != nil {
+			return err
+		}
+		if err := scheduler.Run(ctx); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	<-dispatched
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if mailboxServer != nil {
+		mailboxServer.Shutdown(drainCtx)
+	}
+	if err := drainNATS(drainCtx, infra.NatsConn); err != nil {
+		return err
+	}
+	log.Println("Notification worker stopped")
+	return nil
+}
+
+// serveMailbox serves the inspection endpoints of the emails captured in mailbox on addr, for
+// development and end-to-end tests.
+func serveMailbox(addr string, mailbox notification_domain.Mailbox) *http.Server {
+	handlers := presentation_http.NewCapturedEmailHandlers(mailbox)
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/emails", handlers.ListCapturedEmails)
+	r.GET("/emails/:id", handlers.GetCapturedEmail)
+	r.GET("/emails/:id/raw", handlers.GetCapturedEmailRaw)
+	r.DELETE("/emails", handlers.ClearCapturedEmails)
+
+	server := &http.Server{Addr: addr, Handler: r}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Warning: captured email server failed: %v", err)
+		}
+	}()
+	log.Printf("Captured emails can be inspected on %s/emails", addr)
+	return server
+}
@@ -4,8 +4,11 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	userDomain "github.com/jefersonprimer/chatear-backend/internal/user/domain"
 	"github.com/joho/godotenv"
 )
 
@@ -24,11 +27,63 @@ type Config struct {
 	SMTPUser                string
 	SMTPPass                string
 	SMTPFrom                string
+	SMTPSecurity            string
+	EmailTransport          string
+	EmailTimeout            time.Duration
+	EmailHTTPURL            string
+	EmailHTTPAPIKey         string
+	EmailFileDir            string
+	EmailCaptureAddr        string
+	EmailDedupWindow        time.Duration
+	EmailRateLimitGlobal    notificationDomain.RateLimit
+	EmailRateLimitRecipient notificationDomain.RateLimit
+	EmailRateLimitCategories map[string]notificationDomain.RateLimit
+	EmailRateLimitMaxDelay  time.Duration
+	EmailWebhookSecret      string
+	BounceMailboxDir        string
+	SuppressionSoftBouncePeriod time.Duration
+	SuppressionSoftBounceLimit  int
+	SuppressionSoftBounceWindow time.Duration
+	DKIMDomain              string
+	DKIMSelector            string
+	DKIMPrivateKey          string
+	DKIMPrivateKeyPath      string
 	MagicLinkExpiry         time.Duration
 	RateLimitEnabled        bool
 	KeyRotationInterval     time.Duration
-	MaxEmailsPerDay         int
 	HardDeleteRetentionPeriod time.Duration
+	DeletionGracePeriod     time.Duration
+	DeletionWarningSchedule []time.Duration
+	DeletionDailyCapacity   int
+	DeletionMaxCycles       int
+	DeletionCycleWindow     time.Duration
+	DeletionRequestCooldown time.Duration
+	GeoIPDatabasePath       string
+	ImpossibleTravelMaxSpeedKmh int
+	ReauthenticationWindow  time.Duration
+	ElevatedTokenTTL        time.Duration
+	SessionCookieMode       bool
+	SessionCookieDomain     string
+	SessionCookieSecure     bool
+	SessionCookieSameSite   string
+	CORSAllowedOrigins      []string
+	AdminUserIDs            []string
+	BlobStoragePath         string
+	AnonymizationKey        string
+	BlobPublicURL           string
+	BlobSigningKey          string
+	UnsubscribeSigningKey   string
+	UnsubscribeURL          string
+	DataExportCooldown      time.Duration
+	DataExportLinkTTL       time.Duration
+	WorkerLeaseTTL          time.Duration
+	UserDeletionJobSchedule string
+	HardDeleteJobSchedule   string
+	TokenCleanupJobSchedule string
+	LogCleanupJobSchedule   string
+	BounceMailboxJobSchedule string
+	LogRetentionPeriod      time.Duration
+	ShutdownTimeout         time.Duration
 }
 
 // LoadConfig loads the configuration from the environment variables
@@ -52,14 +107,104 @@ func LoadConfig() *Config {
 		SMTPUser:                  getEnv("SMTP_USER", ""),
 		SMTPPass:                  getEnv("SMTP_PASS", ""),
 		SMTPFrom:                  getEnv("SMTP_FROM", ""),
+		SMTPSecurity:              getEnv("SMTP_SECURITY", "starttls"),
+		EmailTransport:            getEnv("EMAIL_TRANSPORT", "smtp"),
+		EmailTimeout:              getEnvAsDuration("EMAIL_TIMEOUT", 30*time.Second),
+		EmailHTTPURL:              getEnv("EMAIL_HTTP_URL", ""),
+		EmailHTTPAPIKey:           getEnv("EMAIL_HTTP_API_KEY", ""),
+		EmailFileDir:              getEnv("EMAIL_FILE_DIR", "data/mail"),
+		EmailCaptureAddr:          getEnv("EMAIL_CAPTURE_ADDR", ":8025"),
+		EmailDedupWindow:          getEnvAsDuration("EMAIL_DEDUP_WINDOW", 24*time.Hour),
+		EmailRateLimitGlobal:      getEnvAsRateLimit("EMAIL_RATE_LIMIT_GLOBAL", notificationDomain.RateLimit{Count: 50, Period: time.Second}),
+		EmailRateLimitRecipient:   getEnvAsRateLimit("EMAIL_RATE_LIMIT_RECIPIENT", notificationDomain.RateLimit{Count: 20, Period: time.Hour}),
+		EmailRateLimitCategories: getEnvAsRateLimitMap("EMAIL_RATE_LIMIT_CATEGORIES", map[string]notificationDomain.RateLimit{
+			notificationDomain.CategorySecurity: {Count: 5, Period: 10 * time.Minute},
+			notificationDomain.CategoryAccount:  {Count: 5, Period: time.Hour},
+			notificationDomain.CategoryProduct:  {Count: 3, Period: 24 * time.Hour},
+			notificationDomain.CategoryDigest:   {Count: 1, Period: 24 * time.Hour},
+		}),
+		EmailRateLimitMaxDelay:    getEnvAsDuration("EMAIL_RATE_LIMIT_MAX_DELAY", time.Hour),
+		EmailWebhookSecret:        getEnv("EMAIL_WEBHOOK_SECRET", ""),
+		BounceMailboxDir:          getEnv("BOUNCE_MAILBOX_DIR", ""),
+		SuppressionSoftBouncePeriod: getEnvAsDuration("SUPPRESSION_SOFT_BOUNCE_PERIOD", 24*time.Hour),
+		SuppressionSoftBounceLimit:  getEnvAsInt("SUPPRESSION_SOFT_BOUNCE_LIMIT", 3),
+		SuppressionSoftBounceWindow: getEnvAsDuration("SUPPRESSION_SOFT_BOUNCE_WINDOW", 7*24*time.Hour),
+		DKIMDomain:                getEnv("DKIM_DOMAIN", ""),
+		DKIMSelector:              getEnv("DKIM_SELECTOR", ""),
+		DKIMPrivateKey:            getEnv("DKIM_PRIVATE_KEY", ""),
+		DKIMPrivateKeyPath:        getEnv("DKIM_PRIVATE_KEY_PATH", ""),
 		MagicLinkExpiry:           getEnvAsDuration("MAGIC_LINK_EXPIRY", time.Hour),
 		RateLimitEnabled:          getEnvAsBool("RATE_LIMIT_ENABLED", false),
 		KeyRotationInterval:       getEnvAsDuration("KEY_ROTATION_INTERVAL", 24*time.Hour),
-		MaxEmailsPerDay:           getEnvAsInt("MAX_EMAILS_PER_DAY", 100),
 		HardDeleteRetentionPeriod: getEnvAsDuration("HARD_DELETE_RETENTION_PERIOD", 60*24*time.Hour),
+		DeletionGracePeriod:       getEnvAsDuration("DELETION_GRACE_PERIOD", 90*24*time.Hour),
+		DeletionWarningSchedule:   getEnvAsDurationSlice("DELETION_WARNING_SCHEDULE", []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour}),
+		DeletionDailyCapacity:     getEnvAsInt("DELETION_DAILY_CAPACITY", 10),
+		DeletionMaxCycles:         getEnvAsInt("DELETION_MAX_CYCLES", 3),
+		DeletionCycleWindow:       getEnvAsDuration("DELETION_CYCLE_WINDOW", 90*24*time.Hour),
+		DeletionRequestCooldown:   getEnvAsDuration("DELETION_REQUEST_COOLDOWN", 24*time.Hour),
+		GeoIPDatabasePath:         getEnv("GEOIP_DATABASE_PATH", ""),
+		ImpossibleTravelMaxSpeedKmh: getEnvAsInt("IMPOSSIBLE_TRAVEL_MAX_SPEED_KMH", 1000),
+		ReauthenticationWindow:    getEnvAsDuration("REAUTHENTICATION_WINDOW", 5*time.Minute),
+		ElevatedTokenTTL:          getEnvAsDuration("ELEVATED_TOKEN_TTL", 5*time.Minute),
+		SessionCookieMode:         getEnvAsBool("SESSION_COOKIE_MODE", false),
+		SessionCookieDomain:       getEnv("SESSION_COOKIE_DOMAIN", ""),
+		SessionCookieSecure:       getEnvAsBool("SESSION_COOKIE_SECURE", true),
+		SessionCookieSameSite:     getEnv("SESSION_COOKIE_SAMESITE", "strict"),
+		CORSAllowedOrigins:        getEnvAsSlice("CORS_ALLOWED_ORIGINS", nil),
+		AdminUserIDs:              getEnvAsSlice("ADMIN_USER_IDS", nil),
+		BlobStoragePath:           getEnv("BLOB_STORAGE_PATH", "data/blobs"),
+		AnonymizationKey:          getEnv("ANONYMIZATION_KEY", ""),
+		BlobPublicURL:             getEnv("BLOB_PUBLIC_URL", "http://localhost:8080/api/v1/blobs"),
+		BlobSigningKey:            getEnv("BLOB_SIGNING_KEY", ""),
+		UnsubscribeSigningKey:     getEnv("UNSUBSCRIBE_SIGNING_KEY", ""),
+		UnsubscribeURL:            getEnv("UNSUBSCRIBE_URL", "http://localhost:8080/api/v1/unsubscribe"),
+		DataExportCooldown:        getEnvAsDuration("DATA_EXPORT_COOLDOWN", 24*time.Hour),
+		DataExportLinkTTL:         getEnvAsDuration("DATA_EXPORT_LINK_TTL", 7*24*time.Hour),
+		WorkerLeaseTTL:            getEnvAsDuration("WORKER_LEASE_TTL", time.Minute),
+		UserDeletionJobSchedule:   getEnv("USER_DELETION_JOB_SCHEDULE", "@hourly"),
+		HardDeleteJobSchedule:     getEnv("HARD_DELETE_JOB_SCHEDULE", "@daily"),
+		TokenCleanupJobSchedule:   getEnv("TOKEN_CLEANUP_JOB_SCHEDULE", "@daily"),
+		LogCleanupJobSchedule:     getEnv("LOG_CLEANUP_JOB_SCHEDULE", "@weekly"),
+		BounceMailboxJobSchedule:  getEnv("BOUNCE_MAILBOX_JOB_SCHEDULE", "@every 5m"),
+		LogRetentionPeriod:        getEnvAsDuration("LOG_RETENTION_PERIOD", 365*24*time.Hour),
+		ShutdownTimeout:           getEnvAsDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 	}
 }
 
+// DeletionPolicy returns the account deletion policy shared by the API and the deletion workers.
+func (c *Config) DeletionPolicy() userDomain.DeletionPolicy {
+	return userDomain.DeletionPolicy{
+		GracePeriod:         c.DeletionGracePeriod,
+		WarningSchedule:     c.DeletionWarningSchedule,
+		DailyCapacity:       c.DeletionDailyCapacity,
+		HardDeleteRetention: c.HardDeleteRetentionPeriod,
+		Cycles: userDomain.DeletionCyclePolicy{
+			MaxCycles: c.DeletionMaxCycles,
+			Window:    c.DeletionCycleWindow,
+			Cooldown:  c.DeletionRequestCooldown,
+		},
+	}
+}
+
+// EmailRateLimitPolicy returns the rate limits of the notification worker.
+func (c *Config) EmailRateLimitPolicy() notificationDomain.RateLimitPolicy {
+	return notificationDomain.RateLimitPolicy{
+		Global:     c.EmailRateLimitGlobal,
+		Recipient:  c.EmailRateLimitRecipient,
+		Categories: c.EmailRateLimitCategories,
+		MaxDelay:   c.EmailRateLimitMaxDelay,
+	}
+}
+
+// SuppressionPolicy returns how bounces and complaints suppress email addresses.
+func (c *Config) SuppressionPolicy() notificationDomain.SuppressionPolicy {
+	return notificationDomain.SuppressionPolicy{
+		SoftBouncePeriod: c.SuppressionSoftBouncePeriod,
+		SoftBounceLimit:  c.SuppressionSoftBounceLimit,
+		SoftBounceWindow: c.SuppressionSoftBounceWindow,
+	}
+}
 
 // Helper functions to get environment variables
 
@@ -96,3 +241,65 @@ func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
 	}
 	return fallback
 }
+
+func getEnvAsSlice(key string, fallback []string) []string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return fallback
+}
+
+// getEnvAsDurationSlice parses a comma-separated list of durations, falling back if any is invalid.
+func getEnvAsDurationSlice(key string, fallback []time.Duration) []time.Duration {
+	values := getEnvAsSlice(key, nil)
+	if values == nil {
+		return fallback
+	}
+	durations := make([]time.Duration, 0, len(values))
+	for _, v := range values {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fallback
+		}
+		durations = append(durations, d)
+	}
+	return durations
+}
+
+// getEnvAsRateLimit parses a rate limit of the form <count>/<period>, falling back if invalid.
+func getEnvAsRateLimit(key string, fallback notificationDomain.RateLimit) notificationDomain.RateLimit {
+	if value, exists := os.LookupEnv(key); exists {
+		if limit, err := notificationDomain.ParseRateLimit(value); err == nil {
+			return limit
+		}
+	}
+	return fallback
+}
+
+// getEnvAsRateLimitMap parses a comma-separated list of <name>=<count>/<period> rate limits,
+// falling back if any is invalid.
+func getEnvAsRateLimitMap(key string, fallback map[string]notificationDomain.RateLimit) map[string]notificationDomain.RateLimit {
+	values := getEnvAsSlice(key, nil)
+	if values == nil {
+		return fallback
+	}
+	limits := make(map[string]notificationDomain.RateLimit, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return fallback
+		}
+		limit, err := notificationDomain.ParseRateLimit(value)
+		if err != nil {
+			return fallback
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits
+}
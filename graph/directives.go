@@ -0,0 +1,24 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jefersonprimer/chatear-backend/shared/auth"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+// RecentAuthDirective implements @recentAuth: the field only resolves when the caller
+// authenticated within the given window, e.g. with an elevated token from reauthenticate.
+func RecentAuthDirective(window time.Duration) func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+		if _, err := auth.GetUserIDFromContext(ctx); err != nil {
+			return nil, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+		}
+		if err := auth.RequireRecentAuthentication(ctx, window); err != nil {
+			return nil, err
+		}
+		return next(ctx)
+	}
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter maps resolver errors to the same codes and localized messages as the REST error
+// envelope.
+// The code, details and request ID are exposed in the error extensions.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		// Errors raised by gqlgen itself, such as input coercion, keep their message
+		setExtensions(ctx, gqlErr, apperrors.NewAppError(apperrors.CodeInvalidInput, gqlErr.Message, ""))
+		return gqlErr
+	}
+
+	appErr := apperrors.MapError(gqlErr.Err)
+	if appErr.Code == apperrors.CodeInternal {
+		fmt.Printf("Error: graphql %v: %v\n", gqlErr.Path, gqlErr.Err)
+	}
+	gqlErr.Message = apperrors.LocalizedMessage(ctx, appErr)
+	setExtensions(ctx, gqlErr, appErr)
+	return gqlErr
+}
+
+func setExtensions(ctx context.Context, gqlErr *gqlerror.Error, appErr *apperrors.AppError) {
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	gqlErr.Extensions["code"] = appErr.Code
+	if appErr.Details != "" {
+		gqlErr.Extensions["details"] = appErr.Details
+	}
+	if requestID := util.RequestIDFromContext(ctx); requestID != "" {
+		gqlErr.Extensions["request_id"] = requestID
+	}
+}
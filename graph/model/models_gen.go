@@ -12,6 +12,13 @@ type DeleteAccountInput struct {
 	UserID string `json:"userID"`
 }
 
+type LoginHistory struct {
+	Items      []*UserLogin `json:"items"`
+	TotalCount int          `json:"totalCount"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -20,9 +27,24 @@ type LoginInput struct {
 type Mutation struct {
 }
 
+type NotificationPreference struct {
+	Category string `json:"category"`
+	Channel  string `json:"channel"`
+	Enabled  bool   `json:"enabled"`
+	Optional bool   `json:"optional"`
+}
+
 type Query struct {
 }
 
+type ReauthenticateInput struct {
+	Password string `json:"password"`
+}
+
+type ReauthenticateResponse struct {
+	ElevatedToken string `json:"elevatedToken"`
+}
+
 type RecoverAccountInput struct {
 	Token       string `json:"token"`
 	NewPassword string `json:"newPassword"`
@@ -37,25 +59,53 @@ type RefreshTokenInput struct {
 }
 
 type RegisterUserInput struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string  `json:"name"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Locale   *string `json:"locale,omitempty"`
+}
+
+type RequestEmailChangeInput struct {
+	NewEmail string `json:"newEmail"`
+}
+
+type UpdateNotificationPreferenceInput struct {
+	Category string `json:"category"`
+	Channel  string `json:"channel"`
+	Enabled  bool   `json:"enabled"`
 }
 
 type User struct {
-	ID              string  `json:"id"`
-	Name            string  `json:"name"`
-	Email           string  `json:"email"`
-	CreatedAt       string  `json:"createdAt"`
-	UpdatedAt       string  `json:"updatedAt"`
-	IsEmailVerified bool    `json:"isEmailVerified"`
-	DeletedAt       *string `json:"deletedAt,omitempty"`
-	AvatarURL       *string `json:"avatarURL,omitempty"`
-	DeletionDueAt   *string `json:"deletionDueAt,omitempty"`
-	LastLoginAt     *string `json:"lastLoginAt,omitempty"`
-	IsDeleted       bool    `json:"isDeleted"`
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Email            string  `json:"email"`
+	CreatedAt        string  `json:"createdAt"`
+	UpdatedAt        string  `json:"updatedAt"`
+	IsEmailVerified  bool    `json:"isEmailVerified"`
+	DeletedAt        *string `json:"deletedAt,omitempty"`
+	AvatarURL        *string `json:"avatarURL,omitempty"`
+	DeletionDueAt    *string `json:"deletionDueAt,omitempty"`
+	LastLoginAt      *string `json:"lastLoginAt,omitempty"`
+	IsDeleted        bool    `json:"isDeleted"`
+	Locale           string  `json:"locale"`
+	EmailDeliverable bool    `json:"emailDeliverable"`
+}
+
+type UserLogin struct {
+	ID         string  `json:"id"`
+	IPAddress  string  `json:"ipAddress"`
+	UserAgent  string  `json:"userAgent"`
+	CreatedAt  string  `json:"createdAt"`
+	Success    bool    `json:"success"`
+	Country    *string `json:"country,omitempty"`
+	City       *string `json:"city,omitempty"`
+	Suspicious bool    `json:"suspicious"`
 }
 
 type VerifyEmailInput struct {
 	Token string `json:"token"`
 }
+
+type VerifyLoginInput struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,16 @@
+package graph
+
+import (
+	"github.com/jefersonprimer/chatear-backend/graph/model"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// toModelNotificationPreference converts a notification preference into its GraphQL representation.
+func toModelNotificationPreference(preference *domain.Preference) *model.NotificationPreference {
+	return &model.NotificationPreference{
+		Category: preference.Category,
+		Channel:  preference.Channel,
+		Enabled:  preference.Enabled,
+		Optional: preference.Optional(),
+	}
+}
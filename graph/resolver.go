@@ -1,6 +1,7 @@
 package graph
 
 import (
+	notificationApp "github.com/jefersonprimer/chatear-backend/internal/notification/application"
 	"github.com/jefersonprimer/chatear-backend/internal/user/application"
 	"github.com/jefersonprimer/chatear-backend/shared/auth"
 )
@@ -12,5 +13,7 @@ import (
 type Resolver struct{
 	UserAppService *application.UserApplicationService
 	TokenService *auth.TokenService
+	NotificationPreferences *notificationApp.Preferences
+	EmailSuppressions *notificationApp.Suppressions
 }
 
@@ -7,16 +7,27 @@ package graph
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jefersonprimer/chatear-backend/graph/model"
 	"github.com/jefersonprimer/chatear-backend/presentation/http"
 	"github.com/jefersonprimer/chatear-backend/shared/auth"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
 )
 
 // RegisterUser is the resolver for the registerUser field.
 func (r *mutationResolver) RegisterUser(ctx context.Context, input model.RegisterUserInput) (*model.AuthResponse, error) {
-	authTokens, user, err := r.Resolver.UserAppService.Register(ctx, input.Name, input.Email, input.Password)
+	locale := i18n.LocaleFromContext(ctx)
+	if input.Locale != nil {
+		var ok bool
+		if locale, ok = i18n.Match(*input.Locale); !ok {
+			return nil, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "locale must be one of "+strings.Join(i18n.Locales, ", "))
+		}
+	}
+
+	authTokens, user, err := r.Resolver.UserAppService.Register(ctx, input.Name, input.Email, input.Password, locale)
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +40,7 @@ func (r *mutationResolver) RegisterUser(ctx context.Context, input model.Registe
 		UpdatedAt:       user.UpdatedAt.String(),
 		IsEmailVerified: user.IsEmailVerified,
 		IsDeleted:       user.IsDeleted,
+		Locale:          user.Locale,
 	}
 
 	if user.DeletedAt != nil {
@@ -75,6 +87,7 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 		UpdatedAt:       user.UpdatedAt.String(),
 		IsEmailVerified: user.IsEmailVerified,
 		IsDeleted:       user.IsDeleted,
+		Locale:          user.Locale,
 	}
 
 	if user.DeletedAt != nil {
@@ -100,11 +113,11 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
 	accessToken, err := auth.GetAccessTokenFromContext(ctx) // Assuming a new helper function GetAccessTokenFromContext
 	if err != nil {
-		return false, fmt.Errorf("access token not found in context: %w", err)
+		return false, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
 	}
 	refreshToken, err := auth.GetRefreshTokenFromContext(ctx)
 	if err != nil {
-		return false, fmt.Errorf("refresh token not found in context: %w", err)
+		return false, apperrors.WrapLocalized(apperrors.CodeInvalidInput, "error.refresh_token_required", err)
 	}
 
 	err = r.Resolver.UserAppService.Logout(ctx, accessToken, refreshToken)
@@ -127,10 +140,18 @@ func (r *mutationResolver) RecoverPassword(ctx context.Context, input model.Reco
 func (r *mutationResolver) DeleteAccount(ctx context.Context, input model.DeleteAccountInput) (bool, error) {
 	userID, err := uuid.Parse(input.UserID)
 	if err != nil {
-		return false, fmt.Errorf("invalid user ID: %w", err)
+		return false, apperrors.WrapLocalized(apperrors.CodeInvalidInput, "error.invalid_user_id", err)
+	}
+
+	// @recentAuth guarantees an authenticated caller; it may only delete its own account
+	authenticatedUserID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return false, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+	if authenticatedUserID != userID {
+		return false, apperrors.NewLocalizedAppError(apperrors.CodeForbidden, "error.cannot_delete_other_account", "")
 	}
 
-	// Assuming password is not required for GraphQL mutation, or handled by separate auth
 	err = r.Resolver.UserAppService.DeleteAccount(ctx, userID)
 	if err != nil {
 		return false, err
@@ -172,6 +193,7 @@ func (r *mutationResolver) RefreshToken(ctx context.Context, input model.Refresh
 		UpdatedAt:       user.UpdatedAt.String(),
 		IsEmailVerified: user.IsEmailVerified,
 		IsDeleted:       user.IsDeleted,
+		Locale:          user.Locale,
 	}
 
 	if user.DeletedAt != nil {
@@ -193,16 +215,185 @@ func (r *mutationResolver) RefreshToken(ctx context.Context, input model.Refresh
 	return &model.AuthResponse{User: modelUser, AccessToken: authTokens.AccessToken, RefreshToken: authTokens.RefreshToken}, nil
 }
 
+// VerifyLogin is the resolver for the verifyLogin field.
+func (r *mutationResolver) VerifyLogin(ctx context.Context, input model.VerifyLoginInput) (*model.AuthResponse, error) {
+	authTokens, user, err := r.Resolver.UserAppService.VerifyLogin(ctx, input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthResponse{User: toModelUser(user), AccessToken: authTokens.AccessToken, RefreshToken: authTokens.RefreshToken}, nil
+}
+
+// Reauthenticate is the resolver for the reauthenticate field.
+func (r *mutationResolver) Reauthenticate(ctx context.Context, input model.ReauthenticateInput) (*model.ReauthenticateResponse, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	elevatedToken, err := r.Resolver.UserAppService.Reauthenticate(ctx, userID, input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ReauthenticateResponse{ElevatedToken: elevatedToken}, nil
+}
+
+// RevokeAllSessions is the resolver for the revokeAllSessions field.
+func (r *mutationResolver) RevokeAllSessions(ctx context.Context) (bool, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return false, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	if err := r.Resolver.UserAppService.RevokeAllSessions(ctx, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CancelAccountDeletion is the resolver for the cancelAccountDeletion field.
+func (r *mutationResolver) CancelAccountDeletion(ctx context.Context, token string) (bool, error) {
+	if err := r.Resolver.UserAppService.CancelAccountDeletion(ctx, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestDataExport is the resolver for the requestDataExport field.
+func (r *mutationResolver) RequestDataExport(ctx context.Context) (bool, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return false, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	if err := r.Resolver.UserAppService.RequestDataExport(ctx, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestEmailChange is the resolver for the requestEmailChange field.
+func (r *mutationResolver) RequestEmailChange(ctx context.Context, input model.RequestEmailChangeInput) (bool, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return false, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	if err := r.Resolver.UserAppService.RequestEmailChange(ctx, userID, input.NewEmail); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ConfirmEmailChange is the resolver for the confirmEmailChange field.
+func (r *mutationResolver) ConfirmEmailChange(ctx context.Context, token string) (bool, error) {
+	if err := r.Resolver.UserAppService.ConfirmEmailChange(ctx, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateNotificationPreference is the resolver for the updateNotificationPreference field.
+func (r *mutationResolver) UpdateNotificationPreference(ctx context.Context, input model.UpdateNotificationPreferenceInput) (*model.NotificationPreference, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	preference, err := r.Resolver.NotificationPreferences.Update(ctx, userID.String(), input.Category, input.Channel, input.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	return toModelNotificationPreference(preference), nil
+}
+
+// Unsubscribe is the resolver for the unsubscribe field.
+func (r *mutationResolver) Unsubscribe(ctx context.Context, token string) (*model.NotificationPreference, error) {
+	preference, err := r.Resolver.NotificationPreferences.Unsubscribe(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return toModelNotificationPreference(preference), nil
+}
+
 // Hello is the resolver for the hello field.
 func (r *queryResolver) Hello(ctx context.Context) (string, error) {
 	return "Hello from GraphQL!", nil
 }
 
+// LoginHistory is the resolver for the loginHistory field.
+func (r *queryResolver) LoginHistory(ctx context.Context, limit *int, offset *int) (*model.LoginHistory, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	pageLimit, pageOffset := 20, 0
+	if limit != nil {
+		pageLimit = *limit
+	}
+	if offset != nil {
+		pageOffset = *offset
+	}
+	if pageLimit <= 0 || pageLimit > 100 {
+		return nil, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "limit must be between 1 and 100")
+	}
+	if pageOffset < 0 {
+		return nil, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "offset must not be negative")
+	}
+
+	logins, total, err := r.Resolver.UserAppService.GetLoginHistory(ctx, userID, pageLimit, pageOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.UserLogin, 0, len(logins))
+	for _, login := range logins {
+		items = append(items, toModelUserLogin(login))
+	}
+
+	return &model.LoginHistory{Items: items, TotalCount: total, Limit: pageLimit, Offset: pageOffset}, nil
+}
+
+// NotificationPreferences is the resolver for the notificationPreferences field.
+func (r *queryResolver) NotificationPreferences(ctx context.Context) ([]*model.NotificationPreference, error) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.unauthorized", err)
+	}
+
+	preferences, err := r.Resolver.NotificationPreferences.List(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		items = append(items, toModelNotificationPreference(preference))
+	}
+	return items, nil
+}
+
+// EmailDeliverable is the resolver for the emailDeliverable field.
+func (r *userResolver) EmailDeliverable(ctx context.Context, obj *model.User) (bool, error) {
+	// Without the suppression list, which lives in PostgreSQL, no address is known to bounce
+	if r.Resolver.EmailSuppressions == nil {
+		return true, nil
+	}
+	return r.Resolver.EmailSuppressions.IsDeliverable(ctx, obj.Email)
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
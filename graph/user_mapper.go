@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"github.com/jefersonprimer/chatear-backend/graph/model"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// toModelUser converts a domain user into its GraphQL representation.
+func toModelUser(user *domain.User) *model.User {
+	modelUser := &model.User{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Email:           user.Email,
+		CreatedAt:       user.CreatedAt.String(),
+		UpdatedAt:       user.UpdatedAt.String(),
+		IsEmailVerified: user.IsEmailVerified,
+		IsDeleted:       user.IsDeleted,
+		AvatarURL:       user.AvatarURL,
+		Locale:          user.Locale,
+	}
+
+	if user.DeletedAt != nil {
+		deletedAtStr := user.DeletedAt.String()
+		modelUser.DeletedAt = &deletedAtStr
+	}
+	if user.DeletionDueAt != nil {
+		deletionDueAtStr := user.DeletionDueAt.String()
+		modelUser.DeletionDueAt = &deletionDueAtStr
+	}
+	if user.LastLoginAt != nil {
+		lastLoginAtStr := user.LastLoginAt.String()
+		modelUser.LastLoginAt = &lastLoginAtStr
+	}
+
+	return modelUser
+}
+
+// toModelUserLogin converts a login history entry into its GraphQL representation.
+func toModelUserLogin(login *domain.UserLogin) *model.UserLogin {
+	modelLogin := &model.UserLogin{
+		ID:         login.ID.String(),
+		IPAddress:  login.IPAddress,
+		UserAgent:  login.UserAgent,
+		CreatedAt:  login.CreatedAt.String(),
+		Success:    login.Success,
+		Suspicious: login.Suspicious,
+	}
+
+	if login.Country != "" {
+		country := login.Country
+		modelLogin.Country = &country
+	}
+	if login.City != "" {
+		city := login.City
+		modelLogin.City = &city
+	}
+
+	return modelLogin
+}
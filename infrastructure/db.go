@@ -10,6 +10,35 @@ import (
 	"github.com/nats-io/nats.go"
 )
 
+// DB wraps the PostgreSQL connection pool
+type DB struct {
+	Pool *pgxpool.Pool
+}
+
+// NewPostgresDB creates a new PostgreSQL connection pool
+func NewPostgresDB(connString string) (*DB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres connection string: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	return &DB{Pool: pool}, nil
+}
+
+// Health checks if the database is reachable
+func (db *DB) Health(ctx context.Context) error {
+	return db.Pool.Ping(ctx)
+}
+
+// Close closes the connection pool
+func (db *DB) Close() {
+	db.Pool.Close()
+}
 
 // NewRedisClient creates a new Redis client
 func NewRedisClient(redisURL string) (*redis.Client, error) {
@@ -38,22 +67,22 @@ func NewNatsClient(natsURL string) (*nats.Conn, error) {
 
 // Infrastructure holds all infrastructure components
 type Infrastructure struct {
-	Supabase *supabase.Client
+	Postgres *DB
 	Redis    *redis.Client
 	NatsConn *nats.Conn
 }
 
 // NewInfrastructure creates and initializes all infrastructure components
-func NewInfrastructure(supabaseURL, supabaseAnonKey, redisURL, natsURL string) (*Infrastructure, error) {
-	var supabaseClient *supabase.Client
+func NewInfrastructure(postgresConnString, redisURL, natsURL string) (*Infrastructure, error) {
+	var postgresDB *DB
 	var redisClient *redis.Client
 	var natsConn *nats.Conn
 	var err error
 
-	supabaseClient, err = supabase.NewClient(supabaseURL, supabaseAnonKey)
+	postgresDB, err = NewPostgresDB(postgresConnString)
 	if err != nil {
-		log.Printf("Failed to create Supabase client: %v", err)
-		supabaseClient = nil
+		log.Printf("Failed to create postgres pool: %v", err)
+		postgresDB = nil
 	}
 
 	redisClient, err = NewRedisClient(redisURL)
@@ -69,7 +98,7 @@ func NewInfrastructure(supabaseURL, supabaseAnonKey, redisURL, natsURL string) (
 	}
 
 	return &Infrastructure{
-		Supabase: supabaseClient,
+		Postgres: postgresDB,
 		Redis:    redisClient,
 		NatsConn: natsConn,
 	},
@@ -78,6 +107,9 @@ func NewInfrastructure(supabaseURL, supabaseAnonKey, redisURL, natsURL string) (
 
 // Close closes all infrastructure connections
 func (i *Infrastructure) Close() {
+	if i.Postgres != nil {
+		i.Postgres.Close()
+	}
 	if i.Redis != nil {
 		i.Redis.Close()
 	}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrStaleFencingToken is returned by CheckFence when a write was made under a lease that has
+// since been taken over by a holder with a greater fencing token.
+var ErrStaleFencingToken = errors.New("stale fencing token")
+
+// fenceKey is the context key of the fence set by WithFencingToken.
+type fenceKey struct{}
+
+// fence is the lock name and fencing token of the lease a context runs under.
+type fence struct {
+	name  string
+	token int64
+}
+
+// WithFencingToken returns a context carrying the fencing token of the lease held on the lock named
+// name. LeaseLock.TryRun passes such a context to the function it runs.
+func WithFencingToken(ctx context.Context, name string, token int64) context.Context {
+	return context.WithValue(ctx, fenceKey{}, fence{name: name, token: token})
+}
+
+// FencingTokenFromContext returns the lock name and fencing token carried by ctx, if any.
+func FencingTokenFromContext(ctx context.Context) (string, int64, bool) {
+	f, ok := ctx.Value(fenceKey{}).(fence)
+	return f.name, f.token, ok
+}
+
+// CheckFence records the fencing token carried by ctx in lock_fences within tx, and returns
+// ErrStaleFencingToken if a greater token was already recorded for the lock. The fence row stays
+// locked until tx ends, so a write guarded by CheckFence commits only if no later holder of the
+// lock wrote before it. Contexts without a fencing token are not checked.
+func CheckFence(ctx context.Context, tx pgx.Tx) error {
+	name, token, ok := FencingTokenFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var recorded int64
+	err := tx.QueryRow(ctx,
+		`INSERT INTO lock_fences (name, fencing_token, updated_at)
+		 VALUES ($1, $2, now())
+		 ON CONFLICT (name) DO UPDATE
+		 SET fencing_token = EXCLUDED.fencing_token, updated_at = now()
+		 WHERE lock_fences.fencing_token <= EXCLUDED.fencing_token
+		 RETURNING fencing_token`,
+		name, token,
+	).Scan(&recorded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %d for %s", ErrStaleFencingToken, token, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check fencing token of %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	// ErrLockHeld is returned when the lease is held by another instance.
+	ErrLockHeld = errors.New("lock is held by another instance")
+	// ErrLeaseLost is returned when a lease expired or was taken over before being renewed or released.
+	ErrLeaseLost = errors.New("lease lost")
+)
+
+// acquireScript sets the lock key if it is free and returns a new fencing token, or 0 if the lock is held.
+// The fencing counter lives in its own key, so tokens keep increasing across lease expirations.
+var acquireScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], ARGV[1] .. ":" .. token, "PX", ARGV[2])
+return token
+`)
+
+// renewScript extends the lease if it is still held by the caller.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lock key if it is still held by the caller.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// LeaseLock is a distributed lock held for a limited time (a lease), so that the lock of a crashed
+// holder expires and another instance can take over. Each acquisition gets a fencing token that
+// is greater than the tokens of every earlier acquisition.
+type LeaseLock struct {
+	client *redis.Client
+	key    string
+	ttl    time.Duration
+}
+
+// NewLeaseLock creates a lease lock named name. Leases expire ttl after being acquired or renewed.
+func NewLeaseLock(client *redis.Client, name string, ttl time.Duration) *LeaseLock {
+	return &LeaseLock{client: client, key: "lock:" + name, ttl: ttl}
+}
+
+// Lease is a held LeaseLock.
+type Lease struct {
+	lock  *LeaseLock
+	value string
+	// FencingToken identifies the lease. Writes made in a transaction guarded by CheckFence are
+	// rejected once a holder with a greater token has written, which protects them from a holder
+	// that paused past the expiry of its lease.
+	FencingToken int64
+}
+
+// Acquire takes the lock, returning ErrLockHeld if another instance holds it.
+func (l *LeaseLock) Acquire(ctx context.Context) (*Lease, error) {
+	owner := make([]byte, 16)
+	if _, err := rand.Read(owner); err != nil {
+		return nil, err
+	}
+	ownerID := hex.EncodeToString(owner)
+
+	token, err := acquireScript.Run(ctx, l.client, []string{l.key, l.key + ":fence"}, ownerID, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+	if token == 0 {
+		return nil, ErrLockHeld
+	}
+	return &Lease{lock: l, value: ownerID + ":" + strconv.FormatInt(token, 10), FencingToken: token}, nil
+}
+
+// Renew extends the lease by the lock TTL. It returns ErrLeaseLost if the lease already expired.
+func (lease *Lease) Renew(ctx context.Context) error {
+	renewed, err := renewScript.Run(ctx, lease.lock.client, []string{lease.lock.key}, lease.value, lease.lock.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to renew lock %s: %w", lease.lock.key, err)
+	}
+	if renewed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release frees the lock. It returns ErrLeaseLost if the lease already expired.
+func (lease *Lease) Release(ctx context.Context) error {
+	released, err := releaseScript.Run(ctx, lease.lock.client, []string{lease.lock.key}, lease.value).Int()
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", lease.lock.key, err)
+	}
+	if released == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// TryRun runs fn while holding the lock, returning ErrLockHeld without running it if another
+// instance holds the lock. The lease is renewed every third of the TTL while fn runs; if it cannot
+// be renewed, the context passed to fn is cancelled so that fn stops before another holder starts.
+// The context also carries the fencing token of the lease (see WithFencingToken), which CheckFence
+// compares against the writes of later holders.
+func (l *LeaseLock) TryRun(ctx context.Context, fn func(ctx context.Context, lease *Lease) error) error {
+	lease, err := l.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(WithFencingToken(ctx, l.key, lease.FencingToken))
+	defer cancel()
+
+	var renewErr error
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				// A lease that cannot be renewed may expire and be taken over, so fn is stopped
+				if err := lease.Renew(runCtx); err != nil && runCtx.Err() == nil {
+					renewErr = err
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(runCtx, lease)
+	cancel()
+	<-renewed
+
+	if renewErr != nil {
+		return errors.Join(err, renewErr)
+	}
+
+	// Release with a fresh context, so that the lock is freed even when ctx was cancelled on shutdown
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer releaseCancel()
+	if releaseErr := lease.Release(releaseCtx); releaseErr != nil && !errors.Is(releaseErr, ErrLeaseLost) {
+		return errors.Join(err, releaseErr)
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestLeaseLock_AcquireContention(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	first := NewLeaseLock(client, "job", time.Minute)
+	second := NewLeaseLock(client, "job", time.Minute)
+
+	lease, err := first.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), lease.FencingToken)
+
+	// Test case 1: The lock cannot be taken while the lease is held
+	_, err = second.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrLockHeld)
+
+	// Test case 2: Once released, the next holder gets a greater fencing token
+	require.NoError(t, lease.Release(ctx))
+	next, err := second.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), next.FencingToken)
+}
+
+func TestLeaseLock_RenewAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	lock := NewLeaseLock(client, "job", time.Minute)
+
+	lease, err := lock.Acquire(ctx)
+	require.NoError(t, err)
+
+	// Test case 1: A live lease is renewed for another TTL
+	mr.FastForward(40 * time.Second)
+	require.NoError(t, lease.Renew(ctx))
+	mr.FastForward(40 * time.Second)
+	assert.True(t, mr.Exists("lock:job"))
+
+	// Test case 2: An expired lease taken over by another holder cannot be renewed or released
+	mr.FastForward(time.Minute)
+	takeover, err := lock.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, takeover.FencingToken, lease.FencingToken)
+	assert.ErrorIs(t, lease.Renew(ctx), ErrLeaseLost)
+	assert.ErrorIs(t, lease.Release(ctx), ErrLeaseLost)
+	require.NoError(t, takeover.Renew(ctx))
+}
+
+func TestLeaseLock_ReleaseByNonOwner(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	lock := NewLeaseLock(client, "job", time.Minute)
+
+	lease, err := lock.Acquire(ctx)
+	require.NoError(t, err)
+
+	// A lease of another owner with the same fencing token does not free the lock
+	impostor := &Lease{lock: lock, value: "someone-else:1", FencingToken: lease.FencingToken}
+	assert.ErrorIs(t, impostor.Release(ctx), ErrLeaseLost)
+	assert.True(t, mr.Exists("lock:job"))
+
+	require.NoError(t, lease.Release(ctx))
+	assert.False(t, mr.Exists("lock:job"))
+}
+
+func TestLeaseLock_TryRunCarriesFencingToken(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	lock := NewLeaseLock(client, "job", time.Minute)
+
+	err := lock.TryRun(ctx, func(ctx context.Context, lease *Lease) error {
+		name, token, ok := FencingTokenFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "lock:job", name)
+		assert.Equal(t, lease.FencingToken, token)
+
+		// The lock is held while fn runs
+		return lock.TryRun(ctx, func(ctx context.Context, lease *Lease) error {
+			t.Fatal("nested run must not start")
+			return nil
+		})
+	})
+	assert.ErrorIs(t, err, ErrLockHeld)
+
+	_, _, ok := FencingTokenFromContext(ctx)
+	assert.False(t, ok)
+}
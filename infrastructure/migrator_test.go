@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/jefersonprimer/chatear-backend/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"postgres/000002_add_index.up.sql":      {Data: []byte("CREATE INDEX idx ON t (c);")},
+		"postgres/000002_add_index.down.sql":    {Data: []byte("DROP INDEX idx;")},
+		"postgres/000001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c int);")},
+		"postgres/000001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	loaded, err := LoadMigrations(fsys, "postgres")
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, &Migration{Version: 1, Name: "create_table", Up: "CREATE TABLE t (c int);", Down: "DROP TABLE t;"}, loaded[0])
+	assert.Equal(t, int64(2), loaded[1].Version)
+	assert.Equal(t, "add_index", loaded[1].Name)
+}
+
+func TestLoadMigrations_RejectsInvalidFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"unknown direction": {"postgres/000001_a.sideways.sql": {Data: []byte("SELECT 1;")}},
+		"invalid version":   {"postgres/first_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"missing up":        {"postgres/000001_a.down.sql": {Data: []byte("SELECT 1;")}},
+		"conflicting names": {
+			"postgres/000001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"postgres/000001_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadMigrations(fsys, "postgres")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.Postgres, "postgres")
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	for i, migration := range loaded {
+		assert.Equal(t, int64(i+1), migration.Version, "migrations must be numbered without gaps")
+		assert.NotEmpty(t, migration.Down, "migration %d_%s has no down script", migration.Version, migration.Name)
+	}
+}
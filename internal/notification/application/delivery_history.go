@@ -0,0 +1,27 @@
+package application
+
+import (
+	"context"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// DeliveryHistory exposes the email sends and their delivery status to administrators.
+type DeliveryHistory struct {
+	repository domain.Repository
+}
+
+// NewDeliveryHistory creates a new DeliveryHistory.
+func NewDeliveryHistory(repository domain.Repository) *DeliveryHistory {
+	return &DeliveryHistory{repository: repository}
+}
+
+// List returns the email sends matching filter, newest first.
+func (h *DeliveryHistory) List(ctx context.Context, filter domain.EmailSendFilter) ([]*domain.EmailSend, error) {
+	return h.repository.List(ctx, filter)
+}
+
+// Get returns an email send by its ID.
+func (h *DeliveryHistory) Get(ctx context.Context, id string) (*domain.EmailSend, error) {
+	return h.repository.GetByID(ctx, id)
+}
@@ -3,17 +3,14 @@ package application
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
-)
 
-// EmailSendRequest represents a request to send an email
-type EmailSendRequest struct {
-	Recipient    string `json:"recipient"`
-	Subject      string `json:"subject"`
-	Body         string `json:"body"`
-	TemplateName string `json:"template_name,omitempty"`
-}
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
 
 // EmailService provides a high-level interface for sending emails
 type EmailService struct {
@@ -27,40 +24,42 @@ func NewEmailService(natsConn *nats.Conn) *EmailService {
 	}
 }
 
-// SendWelcomeEmail sends a welcome email to a user
-func (s *EmailService) SendWelcomeEmail(ctx context.Context, recipient, userName string) error {
-	subject := "Welcome to Chatear!"
-	body := "Welcome to Chatear! We're excited to have you on board."
-	
-	return s.publishEmailEvent(ctx, recipient, subject, body, "welcome")
+// SendWelcomeEmail sends a welcome email to a user in their locale
+func (s *EmailService) SendWelcomeEmail(ctx context.Context, userID, recipient, locale string, data events.WelcomeEmailData) error {
+	return s.publishTemplatedEmail(ctx, userID, recipient, locale, events.EmailTemplateWelcome, data)
 }
 
-// SendMagicLinkEmail sends a magic link email for authentication
-func (s *EmailService) SendMagicLinkEmail(ctx context.Context, recipient, magicLink string) error {
-	subject := "Your Magic Link"
-	body := "Click the following link to sign in: " + magicLink
-	
-	return s.publishEmailEvent(ctx, recipient, subject, body, "magic_link")
+// SendMagicLinkEmail sends a magic link email for authentication in the user's locale
+func (s *EmailService) SendMagicLinkEmail(ctx context.Context, userID, recipient, locale string, data events.MagicLinkEmailData) error {
+	return s.publishTemplatedEmail(ctx, userID, recipient, locale, events.EmailTemplateMagicLink, data)
 }
 
-// SendCustomEmail sends a custom email with optional template
-func (s *EmailService) SendCustomEmail(ctx context.Context, recipient, subject, body, templateName string) error {
-	return s.publishEmailEvent(ctx, recipient, subject, body, templateName)
+// SendCustomEmail sends an email with the given subject and plain-text body
+func (s *EmailService) SendCustomEmail(ctx context.Context, recipient, subject, body string) error {
+	return s.publishEmailEvent(ctx, events.EmailSendRequest{
+		IdempotencyKey: uuid.NewString(),
+		CorrelationID:  util.RequestIDFromContext(ctx),
+		Recipient:      recipient,
+		Subject:        subject,
+		Body:           body,
+		RequestedAt:    time.Now(),
+	})
 }
 
-// publishEmailEvent publishes an email send event to NATS
-func (s *EmailService) publishEmailEvent(ctx context.Context, recipient, subject, body, templateName string) error {
-	request := EmailSendRequest{
-		Recipient:    recipient,
-		Subject:      subject,
-		Body:         body,
-		TemplateName: templateName,
+func (s *EmailService) publishTemplatedEmail(ctx context.Context, userID, recipient, locale, templateName string, data any) error {
+	request, err := events.NewTemplatedEmailSendRequest(ctx, userID, recipient, locale, templateName, data)
+	if err != nil {
+		return err
 	}
+	return s.publishEmailEvent(ctx, request)
+}
 
+// publishEmailEvent publishes an email send event to NATS
+func (s *EmailService) publishEmailEvent(ctx context.Context, request events.EmailSendRequest) error {
 	data, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
 
 	return s.natsConn.Publish("email.send", data)
-}
\ No newline at end of file
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// templateCategories is the notification category of each email template. Emails that let users
+// into, or out of, their account are security emails, which cannot be disabled.
+var templateCategories = map[string]string{
+	events.EmailTemplateVerification:      domain.CategorySecurity,
+	events.EmailTemplatePasswordReset:     domain.CategorySecurity,
+	events.EmailTemplateMagicLink:         domain.CategorySecurity,
+	events.EmailTemplateLoginVerification: domain.CategorySecurity,
+	events.EmailTemplateNewSignIn:         domain.CategorySecurity,
+	events.EmailTemplateDeletionWarning:   domain.CategorySecurity,
+	events.EmailTemplateDeletionScheduled: domain.CategorySecurity,
+	events.EmailTemplateDeletionCancelled: domain.CategorySecurity,
+	events.EmailTemplateDataExportReady:   domain.CategoryAccount,
+	events.EmailTemplateWelcome:           domain.CategoryProduct,
+}
+
+// EmailCategory returns the notification category of the email template. Emails without a
+// template, or with an unknown one, are security emails, so that they are always sent.
+func EmailCategory(templateName string) string {
+	if category, ok := templateCategories[templateName]; ok {
+		return category
+	}
+	return domain.CategorySecurity
+}
+
+// Preferences manages the notification preferences of users and their unsubscribe links.
+type Preferences struct {
+	repository     domain.PreferenceRepository
+	tokens         *domain.UnsubscribeTokens
+	unsubscribeURL string
+}
+
+// NewPreferences creates a new Preferences. Unsubscribe links point to unsubscribeURL with the
+// token signed by tokens.
+func NewPreferences(repository domain.PreferenceRepository, tokens *domain.UnsubscribeTokens, unsubscribeURL string) *Preferences {
+	return &Preferences{
+		repository:     repository,
+		tokens:         tokens,
+		unsubscribeURL: unsubscribeURL,
+	}
+}
+
+// List returns the preferences of a user for every category and channel, the ones they did not
+// change being enabled.
+func (p *Preferences) List(ctx context.Context, userID string) ([]*domain.Preference, error) {
+	stored, err := p.repository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*domain.Preference, len(stored))
+	for _, preference := range stored {
+		byKey[preference.Category+":"+preference.Channel] = preference
+	}
+
+	preferences := make([]*domain.Preference, 0, len(domain.Categories)*len(domain.Channels))
+	for _, category := range domain.Categories {
+		for _, channel := range domain.Channels {
+			preference, ok := byKey[category+":"+channel]
+			if !ok || !domain.IsOptionalCategory(category) {
+				preference = &domain.Preference{UserID: userID, Category: category, Channel: channel, Enabled: true}
+			}
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+// Update enables or disables the notifications of category on channel for a user. Security
+// notifications cannot be disabled.
+func (p *Preferences) Update(ctx context.Context, userID, category, channel string, enabled bool) (*domain.Preference, error) {
+	if !domain.IsValidCategory(category) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownCategory, category)
+	}
+	if !domain.IsValidChannel(channel) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownChannel, channel)
+	}
+	if !domain.IsOptionalCategory(category) {
+		if !enabled {
+			return nil, domain.ErrRequiredCategory
+		}
+		return &domain.Preference{UserID: userID, Category: category, Channel: channel, Enabled: true}, nil
+	}
+
+	now := time.Now()
+	preference := &domain.Preference{UserID: userID, Category: category, Channel: channel, Enabled: enabled, UpdatedAt: &now}
+	if err := p.repository.Save(ctx, preference); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// Unsubscribe disables the category and channel of an unsubscribe token for its user.
+func (p *Preferences) Unsubscribe(ctx context.Context, token string) (*domain.Preference, error) {
+	userID, category, channel, err := p.tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.IsOptionalCategory(category) {
+		return nil, domain.ErrInvalidUnsubscribeToken
+	}
+	return p.Update(ctx, userID, category, channel, false)
+}
+
+// IsEnabled reports whether the user receives the notifications of category on channel. Security
+// notifications are always enabled.
+func (p *Preferences) IsEnabled(ctx context.Context, userID, category, channel string) (bool, error) {
+	if !domain.IsOptionalCategory(category) {
+		return true, nil
+	}
+	return p.repository.IsEnabled(ctx, userID, category, channel)
+}
+
+// UnsubscribeURL returns the link disabling category on channel for the user.
+func (p *Preferences) UnsubscribeURL(userID, category, channel string) (string, error) {
+	token, err := p.tokens.Sign(userID, category, channel)
+	if err != nil {
+		return "", err
+	}
+	return p.unsubscribeURL + "?token=" + url.QueryEscape(token), nil
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferences_ListDefaultsToEnabled(t *testing.T) {
+	preferences := newTestPreferences(nil)
+	_, err := preferences.Update(context.Background(), "user-1", domain.CategoryDigest, domain.ChannelEmail, false)
+	require.NoError(t, err)
+
+	list, err := preferences.List(context.Background(), "user-1")
+	require.NoError(t, err)
+	require.Len(t, list, len(domain.Categories))
+	for _, preference := range list {
+		assert.Equal(t, preference.Category != domain.CategoryDigest, preference.Enabled, preference.Category)
+	}
+}
+
+func TestPreferences_SecurityCannotBeDisabled(t *testing.T) {
+	preferences := newTestPreferences(nil)
+
+	_, err := preferences.Update(context.Background(), "user-1", domain.CategorySecurity, domain.ChannelEmail, false)
+	require.ErrorIs(t, err, domain.ErrRequiredCategory)
+	_, err = preferences.Update(context.Background(), "user-1", "marketing", domain.ChannelEmail, false)
+	require.ErrorIs(t, err, domain.ErrUnknownCategory)
+
+	enabled, err := preferences.IsEnabled(context.Background(), "user-1", domain.CategorySecurity, domain.ChannelEmail)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestPreferences_Unsubscribe(t *testing.T) {
+	preferences := newTestPreferences(nil)
+	link, err := preferences.UnsubscribeURL("user-1", domain.CategoryProduct, domain.ChannelEmail)
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	token := parsed.Query().Get("token")
+
+	preference, err := preferences.Unsubscribe(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CategoryProduct, preference.Category)
+	assert.False(t, preference.Enabled)
+	enabled, err := preferences.IsEnabled(context.Background(), "user-1", domain.CategoryProduct, domain.ChannelEmail)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	_, err = preferences.Unsubscribe(context.Background(), "x"+token)
+	require.ErrorIs(t, err, domain.ErrInvalidUnsubscribeToken)
+	securityToken, err := domain.NewUnsubscribeTokens([]byte("test-key")).Sign("user-1", domain.CategorySecurity, domain.ChannelEmail)
+	require.NoError(t, err)
+	_, err = preferences.Unsubscribe(context.Background(), securityToken)
+	require.ErrorIs(t, err, domain.ErrInvalidUnsubscribeToken)
+}
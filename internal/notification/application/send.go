@@ -2,52 +2,293 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
 )
 
+// minScheduleDelay is how far in the future an email must be requested for to be scheduled. Emails
+// due sooner, like the scheduled emails coming back from the deferred queue, are sent right away.
+const minScheduleDelay = time.Second
+
 type EmailSender struct {
-	repository domain.Repository
-	sender     domain.Sender
+	repository   domain.Repository
+	claims       domain.DeliveryClaims
+	limiter      domain.RateLimiter
+	deferred     domain.DeferredQueue
+	renderer     domain.Renderer
+	sender       domain.Sender
+	preferences  *Preferences
+	suppressions *Suppressions
+	dedupWindow  time.Duration
+	rateLimits   domain.RateLimitPolicy
 }
 
-func NewEmailSender(repository domain.Repository, sender domain.Sender) *EmailSender {
+// NewEmailSender creates a new EmailSender. Emails requested again with the idempotency key of an
+// email delivered less than dedupWindow before are not sent again, and emails over rateLimits are
+// held in the deferred queue.
+func NewEmailSender(
+	repository domain.Repository,
+	claims domain.DeliveryClaims,
+	limiter domain.RateLimiter,
+	deferred domain.DeferredQueue,
+	renderer domain.Renderer,
+	sender domain.Sender,
+	preferences *Preferences,
+	suppressions *Suppressions,
+	dedupWindow time.Duration,
+	rateLimits domain.RateLimitPolicy,
+) *EmailSender {
 	return &EmailSender{
-		repository: repository,
-		sender:     sender,
+		repository:   repository,
+		claims:       claims,
+		limiter:      limiter,
+		deferred:     deferred,
+		renderer:     renderer,
+		sender:       sender,
+		preferences:  preferences,
+		suppressions: suppressions,
+		dedupWindow:  dedupWindow,
+		rateLimits:   rateLimits,
 	}
 }
 
-func (s *EmailSender) Send(ctx context.Context, recipient, subject, body, templateName string) (*domain.EmailSend, error) {
-	emailSend := &domain.EmailSend{
-		ID:           uuid.New().String(),
-		Recipient:    recipient,
-		Subject:      subject,
-		Body:         body,
-		TemplateName: templateName,
-		SentAt:       time.Now(),
-		Status:       "pending",
+// Send renders the requested email, records it as pending, sends it and records whether the delivery
+// succeeded, so that the delivery history also shows the emails whose send was interrupted.
+// Emails requested for later are recorded as scheduled and held in the deferred queue until then.
+// Emails that cannot be rendered are recorded as failed without being sent, emails whose
+// idempotency key was already delivered within the deduplication window are recorded as
+// duplicates, emails to a suppressed address as suppressed, emails of a category their recipient
+// disabled as skipped, and emails over a rate limit as deferred or dropped.
+func (s *EmailSender) Send(ctx context.Context, request events.EmailSendRequest) (*domain.EmailSend, error) {
+	now := time.Now()
+	emailSend := newEmailSend(request, now)
+	if request.SendAt.After(now.Add(minScheduleDelay)) {
+		// The request comes back once due, so its idempotency key is not claimed until then
+		if err := s.deferred.Defer(ctx, request, request.SendAt); err != nil {
+			return nil, err
+		}
+		emailSend.Status = domain.EmailSendStatusScheduled
+		emailSend.ErrorMessage = "scheduled for " + request.SendAt.UTC().Format(time.RFC3339)
+		if err := s.repository.Save(ctx, emailSend); err != nil {
+			return nil, err
+		}
+		return emailSend, nil
+	}
+	if emailSend.IdempotencyKey != "" {
+		duplicateOf, err := s.claim(ctx, emailSend)
+		if err != nil {
+			return nil, err
+		}
+		if duplicateOf != "" {
+			emailSend.Status = domain.EmailSendStatusDuplicate
+			emailSend.DuplicateOf = duplicateOf
+			emailSend.ErrorMessage = "duplicate of email send " + duplicateOf
+			if err := s.repository.Save(ctx, emailSend); err != nil {
+				return nil, err
+			}
+			return emailSend, nil
+		}
+	}
+	suppression, err := s.suppressions.Active(ctx, emailSend.Recipient, now)
+	if err != nil {
+		return nil, err
+	}
+	if suppression != nil {
+		emailSend.Status = domain.EmailSendStatusSuppressed
+		emailSend.ErrorMessage = "recipient is suppressed after a " + strings.ReplaceAll(suppression.Reason, "_", " ")
+		if err := s.repository.Save(ctx, emailSend); err != nil {
+			return nil, err
+		}
+		return emailSend, nil
+	}
+	if request.UserID != "" && domain.IsOptionalCategory(emailSend.Category) {
+		enabled, err := s.preferences.IsEnabled(ctx, request.UserID, emailSend.Category, domain.ChannelEmail)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			emailSend.Status = domain.EmailSendStatusSkipped
+			emailSend.ErrorMessage = fmt.Sprintf("recipient disabled %s emails", emailSend.Category)
+			if err := s.repository.Save(ctx, emailSend); err != nil {
+				return nil, err
+			}
+			return emailSend, nil
+		}
+		if emailSend.UnsubscribeURL == "" {
+			unsubscribeURL, err := s.preferences.UnsubscribeURL(request.UserID, emailSend.Category, domain.ChannelEmail)
+			if err != nil {
+				return nil, err
+			}
+			emailSend.UnsubscribeURL = unsubscribeURL
+		}
+	}
+	held, err := s.holdBack(ctx, emailSend, request, now)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		return emailSend, nil
+	}
+	if err := s.renderer.Render(emailSend); err != nil {
+		emailSend.Status = domain.EmailSendStatusFailed
+		emailSend.ErrorMessage = err.Error()
+		err = s.release(ctx, emailSend, err)
+		if saveErr := s.repository.Save(ctx, emailSend); saveErr != nil {
+			return nil, fmt.Errorf("%w (and failed to record it: %v)", err, saveErr)
+		}
+		return nil, err
+	}
+	if err := s.repository.Save(ctx, emailSend); err != nil {
+		return nil, err
 	}
 
 	// Try to send the email
+	attemptedAt := time.Now()
+	emailSend.Attempts++
+	emailSend.LastAttemptAt = &attemptedAt
 	if err := s.sender.Send(ctx, emailSend); err != nil {
-		emailSend.Status = "failed"
+		emailSend.Status = domain.EmailSendStatusFailed
 		emailSend.ErrorMessage = err.Error()
+		err = s.release(ctx, emailSend, err)
 		// Still save the failed attempt for logging purposes
 		if saveErr := s.repository.Save(ctx, emailSend); saveErr != nil {
-			return nil, saveErr
+			return nil, fmt.Errorf("%w (and failed to record it: %v)", err, saveErr)
 		}
 		return nil, err
 	}
 
 	// Mark as sent and save
-	emailSend.Status = "sent"
+	emailSend.Status = domain.EmailSendStatusSent
+	emailSend.SentAt = time.Now()
 	if err := s.repository.Save(ctx, emailSend); err != nil {
 		return nil, err
 	}
 
 	return emailSend, nil
 }
+
+// Cancel cancels the scheduled or deferred email with idempotencyKey, recording it as cancelled.
+// It returns domain.ErrScheduledEmailNotFound when no such email is waiting to be sent.
+func (s *EmailSender) Cancel(ctx context.Context, idempotencyKey string) (*domain.EmailSend, error) {
+	request, err := s.deferred.Cancel(ctx, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	emailSend := newEmailSend(request, time.Now())
+	emailSend.Status = domain.EmailSendStatusCancelled
+	emailSend.ErrorMessage = "cancelled before it was sent"
+	if err := s.repository.Save(ctx, emailSend); err != nil {
+		return nil, err
+	}
+	return emailSend, nil
+}
+
+// newEmailSend creates the pending email send of request.
+func newEmailSend(request events.EmailSendRequest, now time.Time) *domain.EmailSend {
+	return &domain.EmailSend{
+		ID:             uuid.New().String(),
+		Recipient:      request.Recipient,
+		Subject:        request.Subject,
+		Body:           request.Body,
+		Locale:         request.Locale,
+		TemplateName:   request.TemplateName,
+		TemplateData:   request.TemplateData,
+		UnsubscribeURL: request.UnsubscribeURL,
+		Category:       EmailCategory(request.TemplateName),
+		IdempotencyKey: request.IdempotencyKey,
+		CorrelationID:  request.CorrelationID,
+		SentAt:         now,
+		CreatedAt:      now,
+		Status:         domain.EmailSendStatusPending,
+	}
+}
+
+// claim claims the idempotency key of emailSend, returning the ID of the email send that already
+// delivers it, if any. Claims live in Redis; when the key is free there, the delivery history is
+// checked as well, in case the claim was lost.
+func (s *EmailSender) claim(ctx context.Context, emailSend *domain.EmailSend) (string, error) {
+	claimed, claimedBy, err := s.claims.Claim(ctx, emailSend.IdempotencyKey, emailSend.ID, s.dedupWindow)
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return claimedBy, nil
+	}
+
+	delivered, err := s.repository.FindByIdempotencyKey(ctx, emailSend.IdempotencyKey, emailSend.CreatedAt.Add(-s.dedupWindow))
+	if errors.Is(err, domain.ErrEmailSendNotFound) {
+		return "", nil
+	}
+	// The email send does not deliver the key after all, so its claim is released and later
+	// requests check the history again
+	if releaseErr := s.claims.Release(ctx, emailSend.IdempotencyKey, emailSend.ID); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return delivered.ID, nil
+}
+
+// holdBack takes a token from the rate limits of emailSend. When a limit is exhausted, the email
+// is recorded as deferred and its request held in the deferred queue until the limits allow it,
+// or recorded as dropped if that would deliver it too late after it was requested or, for a
+// scheduled email, after the time it was scheduled for. It reports whether the email was held
+// back.
+func (s *EmailSender) holdBack(ctx context.Context, emailSend *domain.EmailSend, request events.EmailSendRequest, now time.Time) (bool, error) {
+	wait, err := s.limiter.Take(ctx, s.rateLimits.Buckets(emailSend.Recipient, emailSend.Category))
+	if err != nil {
+		return false, s.release(ctx, emailSend, err)
+	}
+	if wait <= 0 {
+		return false, nil
+	}
+
+	if request.RequestedAt.IsZero() {
+		request.RequestedAt = now
+	}
+	// Scheduled emails are delayed from the time they were scheduled for
+	dueAt := request.RequestedAt
+	if request.SendAt.After(dueAt) {
+		dueAt = request.SendAt
+	}
+	retryAt := now.Add(wait)
+	if s.rateLimits.MaxDelay > 0 && retryAt.Sub(dueAt) > s.rateLimits.MaxDelay {
+		emailSend.Status = domain.EmailSendStatusDropped
+		emailSend.ErrorMessage = fmt.Sprintf("rate limited for more than %s", s.rateLimits.MaxDelay)
+		return true, s.repository.Save(ctx, emailSend)
+	}
+
+	// The request comes back with the same idempotency key, which must not be a duplicate of this
+	if err := s.release(ctx, emailSend, nil); err != nil {
+		return true, err
+	}
+	if err := s.deferred.Defer(ctx, request, retryAt); err != nil {
+		return true, err
+	}
+	emailSend.Status = domain.EmailSendStatusDeferred
+	emailSend.ErrorMessage = "rate limited until " + retryAt.UTC().Format(time.RFC3339)
+	return true, s.repository.Save(ctx, emailSend)
+}
+
+// release frees the idempotency key of an email that was not delivered, so that a new request for
+// it is sent. err is the delivery error, if any, which is returned.
+func (s *EmailSender) release(ctx context.Context, emailSend *domain.EmailSend, err error) error {
+	if emailSend.IdempotencyKey == "" {
+		return err
+	}
+	if releaseErr := s.claims.Release(ctx, emailSend.IdempotencyKey, emailSend.ID); releaseErr != nil {
+		if err == nil {
+			return releaseErr
+		}
+		return fmt.Errorf("%w (and failed to release its idempotency key: %v)", err, releaseErr)
+	}
+	return err
+}
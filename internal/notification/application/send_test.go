@@ -0,0 +1,498 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository is an in-memory implementation of domain.Repository for testing
+type fakeRepository struct {
+	emailSends map[string]domain.EmailSend
+	statuses   []string
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{emailSends: make(map[string]domain.EmailSend)}
+}
+
+func (r *fakeRepository) Save(ctx context.Context, emailSend *domain.EmailSend) error {
+	r.emailSends[emailSend.ID] = *emailSend
+	r.statuses = append(r.statuses, emailSend.Status)
+	return nil
+}
+
+func (r *fakeRepository) GetByID(ctx context.Context, id string) (*domain.EmailSend, error) {
+	emailSend, ok := r.emailSends[id]
+	if !ok {
+		return nil, domain.ErrEmailSendNotFound
+	}
+	return &emailSend, nil
+}
+
+func (r *fakeRepository) GetByRecipient(ctx context.Context, recipient string, limit int) ([]*domain.EmailSend, error) {
+	return r.List(ctx, domain.EmailSendFilter{Recipient: recipient, Limit: limit})
+}
+
+func (r *fakeRepository) FindByIdempotencyKey(ctx context.Context, key string, since time.Time) (*domain.EmailSend, error) {
+	var found *domain.EmailSend
+	for _, emailSend := range r.emailSends {
+		if emailSend.IdempotencyKey != key || emailSend.CreatedAt.Before(since) ||
+			emailSend.Status == domain.EmailSendStatusFailed || emailSend.Status == domain.EmailSendStatusDuplicate ||
+			emailSend.Status == domain.EmailSendStatusDeferred || emailSend.Status == domain.EmailSendStatusDropped ||
+			emailSend.Status == domain.EmailSendStatusScheduled || emailSend.Status == domain.EmailSendStatusCancelled {
+			continue
+		}
+		if found == nil || emailSend.CreatedAt.After(found.CreatedAt) {
+			emailSend := emailSend
+			found = &emailSend
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrEmailSendNotFound
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, filter domain.EmailSendFilter) ([]*domain.EmailSend, error) {
+	var emailSends []*domain.EmailSend
+	for _, emailSend := range r.emailSends {
+		if (filter.Recipient == "" || emailSend.Recipient == filter.Recipient) && (filter.Status == "" || emailSend.Status == filter.Status) {
+			emailSends = append(emailSends, &emailSend)
+		}
+	}
+	return emailSends, nil
+}
+
+// fakeDeliveryClaims is an in-memory implementation of domain.DeliveryClaims for testing, whose
+// claims do not expire
+type fakeDeliveryClaims struct {
+	claims map[string]string
+}
+
+func newFakeDeliveryClaims() *fakeDeliveryClaims {
+	return &fakeDeliveryClaims{claims: make(map[string]string)}
+}
+
+func (c *fakeDeliveryClaims) Claim(ctx context.Context, key, emailSendID string, ttl time.Duration) (bool, string, error) {
+	if claimedBy, ok := c.claims[key]; ok {
+		return false, claimedBy, nil
+	}
+	c.claims[key] = emailSendID
+	return true, "", nil
+}
+
+func (c *fakeDeliveryClaims) Release(ctx context.Context, key, emailSendID string) error {
+	if c.claims[key] == emailSendID {
+		delete(c.claims, key)
+	}
+	return nil
+}
+
+// fakeRateLimiter is an implementation of domain.RateLimiter for testing that makes every email
+// wait for wait
+type fakeRateLimiter struct {
+	wait time.Duration
+}
+
+func (l *fakeRateLimiter) Take(ctx context.Context, buckets []domain.RateLimitBucket) (time.Duration, error) {
+	return l.wait, nil
+}
+
+// fakeDeferredQueue is an in-memory implementation of domain.DeferredQueue for testing
+type fakeDeferredQueue struct {
+	requests []events.EmailSendRequest
+	dueAt    []time.Time
+}
+
+func (q *fakeDeferredQueue) Defer(ctx context.Context, request events.EmailSendRequest, at time.Time) error {
+	q.requests = append(q.requests, request)
+	q.dueAt = append(q.dueAt, at)
+	return nil
+}
+
+func (q *fakeDeferredQueue) PopDue(ctx context.Context, now time.Time, limit int) ([]events.EmailSendRequest, error) {
+	var due []events.EmailSendRequest
+	for i := 0; i < len(q.requests) && len(due) < limit; {
+		if q.dueAt[i].After(now) {
+			i++
+			continue
+		}
+		due = append(due, q.requests[i])
+		q.requests = append(q.requests[:i], q.requests[i+1:]...)
+		q.dueAt = append(q.dueAt[:i], q.dueAt[i+1:]...)
+	}
+	return due, nil
+}
+
+func (q *fakeDeferredQueue) Cancel(ctx context.Context, idempotencyKey string) (events.EmailSendRequest, error) {
+	for i, request := range q.requests {
+		if request.IdempotencyKey == idempotencyKey {
+			q.requests = append(q.requests[:i], q.requests[i+1:]...)
+			q.dueAt = append(q.dueAt[:i], q.dueAt[i+1:]...)
+			return request, nil
+		}
+	}
+	return events.EmailSendRequest{}, domain.ErrScheduledEmailNotFound
+}
+
+// fakeSender records the emails it is asked to send and fails with err if set
+type fakeSender struct {
+	sent []*domain.EmailSend
+	err  error
+}
+
+func (s *fakeSender) Send(ctx context.Context, emailSend *domain.EmailSend) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, emailSend)
+	return nil
+}
+
+// fakeRenderer renders the plain body of an email as its HTML body and fails with err if set
+type fakeRenderer struct {
+	err error
+}
+
+func (r *fakeRenderer) Render(emailSend *domain.EmailSend) error {
+	if r.err != nil {
+		return r.err
+	}
+	emailSend.HTMLBody = "<p>" + emailSend.Body + "</p>"
+	return nil
+}
+
+// fakePreferenceRepository is an in-memory implementation of domain.PreferenceRepository for testing
+type fakePreferenceRepository struct {
+	preferences map[string]*domain.Preference
+}
+
+func newFakePreferenceRepository() *fakePreferenceRepository {
+	return &fakePreferenceRepository{preferences: make(map[string]*domain.Preference)}
+}
+
+func (r *fakePreferenceRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Preference, error) {
+	var preferences []*domain.Preference
+	for _, preference := range r.preferences {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (r *fakePreferenceRepository) IsEnabled(ctx context.Context, userID, category, channel string) (bool, error) {
+	preference, ok := r.preferences[userID+":"+category+":"+channel]
+	return !ok || preference.Enabled, nil
+}
+
+func (r *fakePreferenceRepository) Save(ctx context.Context, preference *domain.Preference) error {
+	r.preferences[preference.UserID+":"+preference.Category+":"+preference.Channel] = preference
+	return nil
+}
+
+// fakeSuppressionRepository is an in-memory implementation of domain.SuppressionRepository for testing
+type fakeSuppressionRepository struct {
+	suppressions map[string]*domain.Suppression
+}
+
+func newFakeSuppressionRepository() *fakeSuppressionRepository {
+	return &fakeSuppressionRepository{suppressions: make(map[string]*domain.Suppression)}
+}
+
+func (r *fakeSuppressionRepository) Get(ctx context.Context, recipient string) (*domain.Suppression, error) {
+	suppression, ok := r.suppressions[domain.NormalizeRecipient(recipient)]
+	if !ok {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	return suppression, nil
+}
+
+func (r *fakeSuppressionRepository) Save(ctx context.Context, suppression *domain.Suppression) error {
+	r.suppressions[domain.NormalizeRecipient(suppression.Recipient)] = suppression
+	return nil
+}
+
+func (r *fakeSuppressionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Suppression, error) {
+	var suppressions []*domain.Suppression
+	for _, suppression := range r.suppressions {
+		suppressions = append(suppressions, suppression)
+	}
+	return suppressions, nil
+}
+
+func (r *fakeSuppressionRepository) Delete(ctx context.Context, recipient string) error {
+	if _, ok := r.suppressions[domain.NormalizeRecipient(recipient)]; !ok {
+		return domain.ErrSuppressionNotFound
+	}
+	delete(r.suppressions, domain.NormalizeRecipient(recipient))
+	return nil
+}
+
+func newTestSuppressions() *Suppressions {
+	return NewSuppressions(newFakeSuppressionRepository(), domain.SuppressionPolicy{SoftBouncePeriod: 24 * time.Hour, SoftBounceLimit: 3})
+}
+
+func newTestEmailSender(repository domain.Repository, renderer domain.Renderer, sender domain.Sender, preferences *Preferences) *EmailSender {
+	return NewEmailSender(repository, newFakeDeliveryClaims(), &fakeRateLimiter{}, &fakeDeferredQueue{}, renderer, sender, preferences, newTestSuppressions(), 24*time.Hour, domain.RateLimitPolicy{})
+}
+
+func newTestPreferences(repository domain.PreferenceRepository) *Preferences {
+	if repository == nil {
+		repository = newFakePreferenceRepository()
+	}
+	return NewPreferences(repository, domain.NewUnsubscribeTokens([]byte("test-key")), "https://api.chatear.app/api/v1/unsubscribe")
+}
+
+func TestEmailSender_SendRecordsDelivery(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+
+	emailSend, err := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil)).Send(context.Background(), events.EmailSendRequest{
+		Recipient:      "user@example.com",
+		Subject:        "Hello",
+		Body:           "Body",
+		Locale:         "en",
+		TemplateName:   "welcome",
+		TemplateData:   json.RawMessage(`{"name":"User"}`),
+		UnsubscribeURL: "https://chatear.app/unsubscribe?token=abc",
+	})
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "<p>Body</p>", sender.sent[0].HTMLBody)
+	assert.Equal(t, "https://chatear.app/unsubscribe?token=abc", sender.sent[0].UnsubscribeURL)
+
+	assert.Equal(t, []string{domain.EmailSendStatusPending, domain.EmailSendStatusSent}, repository.statuses)
+	stored, err := repository.GetByID(context.Background(), emailSend.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusSent, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.NotNil(t, stored.LastAttemptAt)
+	assert.Equal(t, "welcome", stored.TemplateName)
+}
+
+func TestEmailSender_SendRecordsFailure(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{err: errors.New("550 mailbox unavailable")}
+
+	_, err := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil)).Send(context.Background(), events.EmailSendRequest{Recipient: "user@example.com", Subject: "Hello", Body: "Body"})
+	require.Error(t, err)
+
+	failed, err := repository.List(context.Background(), domain.EmailSendFilter{Status: domain.EmailSendStatusFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "550 mailbox unavailable", failed[0].ErrorMessage)
+	assert.Equal(t, 1, failed[0].Attempts)
+}
+
+func TestEmailSender_SendRecordsRenderFailure(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+	renderer := &fakeRenderer{err: domain.ErrUnknownTemplate}
+
+	_, err := newTestEmailSender(repository, renderer, sender, newTestPreferences(nil)).Send(context.Background(), events.EmailSendRequest{Recipient: "user@example.com", TemplateName: "unknown"})
+	require.ErrorIs(t, err, domain.ErrUnknownTemplate)
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, []string{domain.EmailSendStatusFailed}, repository.statuses)
+}
+
+func TestEmailSender_SendAddsUnsubscribeLinkToOptionalEmails(t *testing.T) {
+	sender := &fakeSender{}
+	emailSender := newTestEmailSender(newFakeRepository(), &fakeRenderer{}, sender, newTestPreferences(nil))
+
+	_, err := emailSender.Send(context.Background(), events.EmailSendRequest{UserID: "user-1", Recipient: "user@example.com", TemplateName: events.EmailTemplateWelcome})
+	require.NoError(t, err)
+	_, err = emailSender.Send(context.Background(), events.EmailSendRequest{UserID: "user-1", Recipient: "user@example.com", TemplateName: events.EmailTemplatePasswordReset})
+	require.NoError(t, err)
+
+	require.Len(t, sender.sent, 2)
+	assert.Equal(t, domain.CategoryProduct, sender.sent[0].Category)
+	assert.Contains(t, sender.sent[0].UnsubscribeURL, "https://api.chatear.app/api/v1/unsubscribe?token=")
+	assert.Equal(t, domain.CategorySecurity, sender.sent[1].Category)
+	assert.Empty(t, sender.sent[1].UnsubscribeURL)
+}
+
+func TestEmailSender_SendSkipsDisabledCategories(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+	preferences := newTestPreferences(nil)
+	_, err := preferences.Update(context.Background(), "user-1", domain.CategoryProduct, domain.ChannelEmail, false)
+	require.NoError(t, err)
+
+	emailSend, err := newTestEmailSender(repository, &fakeRenderer{}, sender, preferences).Send(context.Background(), events.EmailSendRequest{UserID: "user-1", Recipient: "user@example.com", TemplateName: events.EmailTemplateWelcome})
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, domain.EmailSendStatusSkipped, emailSend.Status)
+	assert.Equal(t, []string{domain.EmailSendStatusSkipped}, repository.statuses)
+}
+
+func TestEmailSender_SendDeduplicatesByIdempotencyKey(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+	emailSender := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil))
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", CorrelationID: "request-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body"}
+
+	first, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+	second, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Len(t, sender.sent, 1)
+	assert.Equal(t, domain.EmailSendStatusSent, first.Status)
+	assert.Equal(t, "request-1", first.CorrelationID)
+	assert.Equal(t, domain.EmailSendStatusDuplicate, second.Status)
+	assert.Equal(t, first.ID, second.DuplicateOf)
+	stored, err := repository.GetByID(context.Background(), second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusDuplicate, stored.Status)
+}
+
+func TestEmailSender_SendDeduplicatesAgainstHistoryWhenClaimIsLost(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body"}
+
+	first, err := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil)).Send(context.Background(), request)
+	require.NoError(t, err)
+	// A new set of claims has lost the claim of the first email
+	second, err := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil)).Send(context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Len(t, sender.sent, 1)
+	assert.Equal(t, domain.EmailSendStatusDuplicate, second.Status)
+	assert.Equal(t, first.ID, second.DuplicateOf)
+}
+
+func TestEmailSender_SendRetriesFailedIdempotentEmails(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{err: errors.New("421 try again later")}
+	emailSender := newTestEmailSender(repository, &fakeRenderer{}, sender, newTestPreferences(nil))
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body"}
+
+	_, err := emailSender.Send(context.Background(), request)
+	require.Error(t, err)
+	sender.err = nil
+	emailSend, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.EmailSendStatusSent, emailSend.Status)
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestEmailSender_SendDefersRateLimitedEmails(t *testing.T) {
+	repository := newFakeRepository()
+	claims := newFakeDeliveryClaims()
+	limiter := &fakeRateLimiter{wait: 10 * time.Minute}
+	deferred := &fakeDeferredQueue{}
+	sender := &fakeSender{}
+	policy := domain.RateLimitPolicy{Recipient: domain.RateLimit{Count: 1, Period: time.Hour}, MaxDelay: time.Hour}
+	emailSender := NewEmailSender(repository, claims, limiter, deferred, &fakeRenderer{}, sender, newTestPreferences(nil), newTestSuppressions(), 24*time.Hour, policy)
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body", RequestedAt: time.Now()}
+
+	emailSend, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, domain.EmailSendStatusDeferred, emailSend.Status)
+	require.Len(t, deferred.requests, 1)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), deferred.dueAt[0], time.Minute)
+
+	// The deferred request is sent once the limit allows it, despite its idempotency key
+	limiter.wait = 0
+	emailSend, err = emailSender.Send(context.Background(), deferred.requests[0])
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusSent, emailSend.Status)
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestEmailSender_SendDropsEmailsRateLimitedPastMaxDelay(t *testing.T) {
+	repository := newFakeRepository()
+	deferred := &fakeDeferredQueue{}
+	sender := &fakeSender{}
+	policy := domain.RateLimitPolicy{Recipient: domain.RateLimit{Count: 1, Period: time.Hour}, MaxDelay: time.Hour}
+	emailSender := NewEmailSender(repository, newFakeDeliveryClaims(), &fakeRateLimiter{wait: 20 * time.Minute}, deferred, &fakeRenderer{}, sender, newTestPreferences(nil), newTestSuppressions(), 24*time.Hour, policy)
+
+	emailSend, err := emailSender.Send(context.Background(), events.EmailSendRequest{
+		Recipient:   "user@example.com",
+		Subject:     "Hello",
+		Body:        "Body",
+		RequestedAt: time.Now().Add(-50 * time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+	assert.Empty(t, deferred.requests)
+	assert.Equal(t, domain.EmailSendStatusDropped, emailSend.Status)
+	assert.Equal(t, []string{domain.EmailSendStatusDropped}, repository.statuses)
+}
+
+func TestEmailSender_SendSchedulesFutureEmails(t *testing.T) {
+	repository := newFakeRepository()
+	deferred := &fakeDeferredQueue{}
+	sender := &fakeSender{}
+	emailSender := NewEmailSender(repository, newFakeDeliveryClaims(), &fakeRateLimiter{}, deferred, &fakeRenderer{}, sender, newTestPreferences(nil), newTestSuppressions(), 24*time.Hour, domain.RateLimitPolicy{})
+	sendAt := time.Now().Add(7 * 24 * time.Hour)
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body", RequestedAt: time.Now(), SendAt: sendAt}
+
+	emailSend, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, domain.EmailSendStatusScheduled, emailSend.Status)
+	require.Len(t, deferred.requests, 1)
+	assert.Equal(t, sendAt, deferred.dueAt[0])
+
+	// Once due, the scheduled request is sent, its idempotency key having been left unclaimed
+	due := deferred.requests[0]
+	due.SendAt = time.Now()
+	emailSend, err = emailSender.Send(context.Background(), due)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusSent, emailSend.Status)
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestEmailSender_CancelScheduledEmail(t *testing.T) {
+	repository := newFakeRepository()
+	deferred := &fakeDeferredQueue{}
+	emailSender := NewEmailSender(repository, newFakeDeliveryClaims(), &fakeRateLimiter{}, deferred, &fakeRenderer{}, &fakeSender{}, newTestPreferences(nil), newTestSuppressions(), 24*time.Hour, domain.RateLimitPolicy{})
+	request := events.EmailSendRequest{IdempotencyKey: "key-1", Recipient: "user@example.com", Subject: "Hello", Body: "Body", SendAt: time.Now().Add(time.Hour)}
+	_, err := emailSender.Send(context.Background(), request)
+	require.NoError(t, err)
+
+	emailSend, err := emailSender.Cancel(context.Background(), "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusCancelled, emailSend.Status)
+	assert.Equal(t, "key-1", emailSend.IdempotencyKey)
+	assert.Empty(t, deferred.requests)
+	assert.Equal(t, []string{domain.EmailSendStatusScheduled, domain.EmailSendStatusCancelled}, repository.statuses)
+
+	// Emails are only cancelled once
+	_, err = emailSender.Cancel(context.Background(), "key-1")
+	assert.ErrorIs(t, err, domain.ErrScheduledEmailNotFound)
+}
+
+func TestEmailSender_SendSkipsSuppressedRecipients(t *testing.T) {
+	repository := newFakeRepository()
+	sender := &fakeSender{}
+	suppressions := newTestSuppressions()
+	_, err := suppressions.Record(context.Background(), domain.DeliveryFeedback{Recipient: "Gone@Example.com", Type: domain.FeedbackHardBounce, Source: domain.FeedbackSourceMailbox})
+	require.NoError(t, err)
+	emailSender := NewEmailSender(repository, newFakeDeliveryClaims(), &fakeRateLimiter{}, &fakeDeferredQueue{}, &fakeRenderer{}, sender, newTestPreferences(nil), suppressions, 24*time.Hour, domain.RateLimitPolicy{})
+
+	emailSend, err := emailSender.Send(context.Background(), events.EmailSendRequest{Recipient: "gone@example.com", TemplateName: events.EmailTemplatePasswordReset})
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, domain.EmailSendStatusSuppressed, emailSend.Status)
+	assert.Equal(t, "recipient is suppressed after a hard bounce", emailSend.ErrorMessage)
+
+	// Removed from the suppression list, the address is sent emails again
+	require.NoError(t, suppressions.Remove(context.Background(), "gone@example.com"))
+	emailSend, err = emailSender.Send(context.Background(), events.EmailSendRequest{Recipient: "gone@example.com", TemplateName: events.EmailTemplatePasswordReset})
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailSendStatusSent, emailSend.Status)
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// Suppressions manages the suppression list, the addresses that bounced or complained, which are
+// not sent emails anymore.
+type Suppressions struct {
+	repository domain.SuppressionRepository
+	policy     domain.SuppressionPolicy
+}
+
+// NewSuppressions creates a new Suppressions applying policy to the delivery feedback.
+func NewSuppressions(repository domain.SuppressionRepository, policy domain.SuppressionPolicy) *Suppressions {
+	return &Suppressions{repository: repository, policy: policy}
+}
+
+// Record suppresses the recipient of a bounce or complaint according to the suppression policy.
+// Feedback without a time is recorded as received now.
+func (s *Suppressions) Record(ctx context.Context, feedback domain.DeliveryFeedback) (*domain.Suppression, error) {
+	if domain.NormalizeRecipient(feedback.Recipient) == "" {
+		return nil, fmt.Errorf("%w: recipient is required", domain.ErrInvalidFeedback)
+	}
+	if !domain.IsValidFeedbackType(feedback.Type) {
+		return nil, fmt.Errorf("%w: unknown type %q", domain.ErrInvalidFeedback, feedback.Type)
+	}
+	if feedback.OccurredAt.IsZero() {
+		feedback.OccurredAt = time.Now()
+	}
+
+	existing, err := s.repository.Get(ctx, feedback.Recipient)
+	if err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+		return nil, err
+	}
+	suppression := s.policy.Apply(existing, feedback)
+	if err := s.repository.Save(ctx, suppression); err != nil {
+		return nil, err
+	}
+	return suppression, nil
+}
+
+// RecordAll records every feedback, returning the errors of the ones that could not be recorded.
+func (s *Suppressions) RecordAll(ctx context.Context, feedback []domain.DeliveryFeedback) error {
+	var errs []error
+	for _, f := range feedback {
+		if _, err := s.Record(ctx, f); err != nil {
+			errs = append(errs, fmt.Errorf("failed to record %s of %s: %w", f.Type, f.Recipient, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ProcessMailbox records the feedback of the new reports in the bounce mailbox.
+func (s *Suppressions) ProcessMailbox(ctx context.Context, mailbox domain.BounceMailbox) (int, error) {
+	return mailbox.Process(ctx, s.RecordAll)
+}
+
+// Active returns the suppression of recipient if it is suppressed at now, or nil.
+func (s *Suppressions) Active(ctx context.Context, recipient string, now time.Time) (*domain.Suppression, error) {
+	suppression, err := s.repository.Get(ctx, recipient)
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !suppression.Active(now) {
+		return nil, nil
+	}
+	return suppression, nil
+}
+
+// IsDeliverable reports whether emails are sent to recipient, i.e. whether it is not suppressed.
+func (s *Suppressions) IsDeliverable(ctx context.Context, recipient string) (bool, error) {
+	suppression, err := s.Active(ctx, recipient, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return suppression == nil, nil
+}
+
+// List returns the suppressions, most recently updated first.
+func (s *Suppressions) List(ctx context.Context, limit, offset int) ([]*domain.Suppression, error) {
+	return s.repository.List(ctx, limit, offset)
+}
+
+// Remove removes recipient from the suppression list, e.g. once its mailbox is fixed.
+func (s *Suppressions) Remove(ctx context.Context, recipient string) error {
+	return s.repository.Delete(ctx, recipient)
+}
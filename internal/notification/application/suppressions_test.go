@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressions_RecordSuppressesSoftBouncesTemporarily(t *testing.T) {
+	suppressions := newTestSuppressions()
+	occurredAt := time.Now().Add(-25 * time.Hour)
+
+	_, err := suppressions.Record(context.Background(), domain.DeliveryFeedback{Recipient: "full@example.com", Type: domain.FeedbackSoftBounce, OccurredAt: occurredAt})
+	require.NoError(t, err)
+
+	deliverable, err := suppressions.IsDeliverable(context.Background(), "full@example.com")
+	require.NoError(t, err)
+	assert.True(t, deliverable)
+
+	_, err = suppressions.Record(context.Background(), domain.DeliveryFeedback{Recipient: "full@example.com", Type: domain.FeedbackSoftBounce})
+	require.NoError(t, err)
+	deliverable, err = suppressions.IsDeliverable(context.Background(), "Full@Example.com")
+	require.NoError(t, err)
+	assert.False(t, deliverable)
+}
+
+func TestSuppressions_RecordRejectsInvalidFeedback(t *testing.T) {
+	suppressions := newTestSuppressions()
+
+	_, err := suppressions.Record(context.Background(), domain.DeliveryFeedback{Type: domain.FeedbackHardBounce})
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback)
+	_, err = suppressions.Record(context.Background(), domain.DeliveryFeedback{Recipient: "user@example.com", Type: "unsubscribe"})
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback)
+}
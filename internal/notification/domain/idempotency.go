@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DeliveryClaims records which email send delivers each idempotency key, so that an email
+// requested more than once, by a retried publish or a redelivered message, goes out once.
+type DeliveryClaims interface {
+	// Claim claims key for the email send emailSendID for ttl. When the key is already claimed,
+	// it returns false and the ID of the email send holding the claim.
+	Claim(ctx context.Context, key, emailSendID string, ttl time.Duration) (claimed bool, claimedBy string, err error)
+	// Release frees the claim of emailSendID on key, so that a new request for the email can
+	// deliver it. Claims held by other email sends are left untouched.
+	Release(ctx context.Context, key, emailSendID string) error
+}
@@ -1,17 +1,69 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
 
+var (
+	ErrEmailSendNotFound = errors.New("email send not found")
+	ErrUnknownTemplate   = errors.New("unknown email template")
+)
+
+// Delivery statuses of an EmailSend, matching the notifications.status CHECK constraint.
+const (
+	EmailSendStatusPending = "pending"
+	EmailSendStatusSent    = "sent"
+	EmailSendStatusFailed  = "failed"
+	// EmailSendStatusSkipped is the status of the emails not sent because their recipient
+	// disabled their category.
+	EmailSendStatusSkipped = "skipped"
+	// EmailSendStatusDuplicate is the status of the emails not sent because an email with the
+	// same idempotency key was already delivered. DuplicateOf is the ID of that email.
+	EmailSendStatusDuplicate = "duplicate"
+	// EmailSendStatusDeferred is the status of the emails held back by a rate limit, which are
+	// requested again, as new email sends, once the limit allows them.
+	EmailSendStatusDeferred = "deferred"
+	// EmailSendStatusDropped is the status of the emails held back by a rate limit for longer
+	// than the rate limit policy allows, which are not sent.
+	EmailSendStatusDropped = "dropped"
+	// EmailSendStatusSuppressed is the status of the emails not sent because their recipient is
+	// on the suppression list, after bouncing or complaining.
+	EmailSendStatusSuppressed = "suppressed"
+	// EmailSendStatusScheduled is the status of the emails requested with a send time in the
+	// future, which are requested again, as new email sends, at that time.
+	EmailSendStatusScheduled = "scheduled"
+	// EmailSendStatusCancelled is the status of the scheduled or deferred emails cancelled before
+	// they were sent.
+	EmailSendStatusCancelled = "cancelled"
+)
+
+// EmailSend is an email and its delivery status. Body is the plain-text part of the email and
+// HTMLBody, which is not stored, its optional HTML part. Locale, which is not stored either, is
+// the language its template is rendered in, and UnsubscribeURL the one-click unsubscribe link of
+// the non-transactional emails. Category is the notification category of the email.
+// IdempotencyKey and CorrelationID come from the request of the email.
 type EmailSend struct {
-	ID           string
-	Recipient    string
-	Subject      string
-	Body         string
-	TemplateName string
-	SentAt       time.Time
-	CreatedAt    time.Time
-	ErrorMessage string
-	Status       string
+	ID             string
+	Recipient      string
+	Subject        string
+	Body           string
+	HTMLBody       string
+	Locale         string
+	UnsubscribeURL string
+	TemplateName   string
+	TemplateData   json.RawMessage
+	Category       string
+	IdempotencyKey string
+	CorrelationID  string
+	DuplicateOf    string
+	SentAt         time.Time
+	CreatedAt      time.Time
+	ErrorMessage   string
+	Status         string
+	Attempts       int
+	LastAttemptAt  *time.Time
 }
 
 type Notification struct {
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownCategory          = errors.New("unknown notification category")
+	ErrUnknownChannel           = errors.New("unknown notification channel")
+	ErrRequiredCategory         = errors.New("security notifications cannot be disabled")
+	ErrInvalidUnsubscribeToken  = errors.New("invalid unsubscribe token")
+	ErrUnsubscribeNotConfigured = errors.New("unsubscribe links are not configured")
+)
+
+// Notification categories, matching the notification_preferences.category CHECK constraint
+// (security excepted, since it is never stored).
+const (
+	// CategorySecurity covers sign-in, password, verification and account deletion emails, which
+	// users cannot opt out of.
+	CategorySecurity = "security"
+	// CategoryAccount covers notices about the user's account, like data exports.
+	CategoryAccount = "account"
+	// CategoryProduct covers onboarding and product announcements.
+	CategoryProduct = "product"
+	// CategoryDigest covers periodic summaries of activity.
+	CategoryDigest = "digest"
+)
+
+// Categories lists the notification categories in the order they are shown to users.
+var Categories = []string{CategorySecurity, CategoryAccount, CategoryProduct, CategoryDigest}
+
+// Notification channels, matching the notification_preferences.channel CHECK constraint.
+const (
+	ChannelEmail = "email"
+)
+
+// Channels lists the notification channels.
+var Channels = []string{ChannelEmail}
+
+// IsValidCategory reports whether category is a notification category.
+func IsValidCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidChannel reports whether channel is a notification channel.
+func IsValidChannel(channel string) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOptionalCategory reports whether users can disable the notifications of category.
+func IsOptionalCategory(category string) bool {
+	return category != CategorySecurity
+}
+
+// Preference is whether a user receives the notifications of a category on a channel.
+// UpdatedAt is nil for the default preferences, which are not stored.
+type Preference struct {
+	UserID    string
+	Category  string
+	Channel   string
+	Enabled   bool
+	UpdatedAt *time.Time
+}
+
+// Optional reports whether the user can disable the preference.
+func (p *Preference) Optional() bool {
+	return IsOptionalCategory(p.Category)
+}
+
+// PreferenceRepository stores the preferences users changed from the default, which is enabled.
+type PreferenceRepository interface {
+	// ListByUser returns the stored preferences of a user.
+	ListByUser(ctx context.Context, userID string) ([]*Preference, error)
+	// IsEnabled reports whether the user receives the notifications of category on channel,
+	// which is the case unless they disabled them.
+	IsEnabled(ctx context.Context, userID, category, channel string) (bool, error)
+	// Save inserts or replaces the preference.
+	Save(ctx context.Context, preference *Preference) error
+}
+
+// UnsubscribeTokens signs the tokens of the unsubscribe links, which let users disable a
+// category of notifications on a channel without signing in. Tokens do not expire, since mailbox
+// providers may follow the links of old emails.
+type UnsubscribeTokens struct {
+	key []byte
+}
+
+// NewUnsubscribeTokens creates UnsubscribeTokens signing with key.
+func NewUnsubscribeTokens(key []byte) *UnsubscribeTokens {
+	return &UnsubscribeTokens{key: key}
+}
+
+// Sign returns the token disabling category on channel for the user, of the form
+// <base64url(userID:category:channel)>.<base64url(HMAC-SHA256)>.
+func (t *UnsubscribeTokens) Sign(userID, category, channel string) (string, error) {
+	if len(t.key) == 0 {
+		return "", ErrUnsubscribeNotConfigured
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(userID + ":" + category + ":" + channel))
+	return payload + "." + t.signature(payload), nil
+}
+
+// Verify returns the user, category and channel of a token, or ErrInvalidUnsubscribeToken.
+func (t *UnsubscribeTokens) Verify(token string) (userID, category, channel string, err error) {
+	if len(t.key) == 0 {
+		return "", "", "", ErrUnsubscribeNotConfigured
+	}
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(t.signature(payload))) {
+		return "", "", "", ErrInvalidUnsubscribeToken
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", "", "", ErrInvalidUnsubscribeToken
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 3 {
+		return "", "", "", ErrInvalidUnsubscribeToken
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func (t *UnsubscribeTokens) signature(payload string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Count emails per Period, in bursts of up to Count emails. The zero RateLimit
+// is unlimited.
+type RateLimit struct {
+	Count  int
+	Period time.Duration
+}
+
+// Unlimited reports whether the rate limit allows any number of emails.
+func (l RateLimit) Unlimited() bool {
+	return l.Count <= 0 || l.Period <= 0
+}
+
+// String formats the rate limit as ParseRateLimit parses it.
+func (l RateLimit) String() string {
+	if l.Unlimited() {
+		return "unlimited"
+	}
+	return strconv.Itoa(l.Count) + "/" + l.Period.String()
+}
+
+// ParseRateLimit parses a rate limit of the form <count>/<period>, like 20/1h. An empty string,
+// "0" and "unlimited" are the unlimited rate limit.
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || value == "unlimited" {
+		return RateLimit{}, nil
+	}
+	countValue, periodValue, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected <count>/<period>", value)
+	}
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid count", value)
+	}
+	period, err := time.ParseDuration(periodValue)
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid period", value)
+	}
+	return RateLimit{Count: count, Period: period}, nil
+}
+
+// RateLimitPolicy is how many emails the notification worker sends. Global caps the throughput of
+// the email provider, Recipient the emails to each recipient, and Categories the emails of each
+// category to each recipient. Emails over a limit are deferred until it allows them, unless that
+// would deliver them more than MaxDelay after they were requested, in which case they are
+// dropped. A zero MaxDelay never drops emails.
+type RateLimitPolicy struct {
+	Global     RateLimit
+	Recipient  RateLimit
+	Categories map[string]RateLimit
+	MaxDelay   time.Duration
+}
+
+// RateLimitBucket is the token bucket of a RateLimit, identified by Key.
+type RateLimitBucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+// Buckets returns the buckets an email of category to recipient takes a token from.
+func (p RateLimitPolicy) Buckets(recipient, category string) []RateLimitBucket {
+	recipient = strings.ToLower(recipient)
+	var buckets []RateLimitBucket
+	if !p.Global.Unlimited() {
+		buckets = append(buckets, RateLimitBucket{Key: "global", Limit: p.Global})
+	}
+	if !p.Recipient.Unlimited() {
+		buckets = append(buckets, RateLimitBucket{Key: "recipient:" + recipient, Limit: p.Recipient})
+	}
+	if limit := p.Categories[category]; !limit.Unlimited() {
+		buckets = append(buckets, RateLimitBucket{Key: "category:" + category + ":" + recipient, Limit: limit})
+	}
+	return buckets
+}
+
+// RateLimiter keeps the token buckets of the rate limits.
+type RateLimiter interface {
+	// Take takes a token from every bucket, or from none of them when one is empty, in which case
+	// it returns how long until every bucket has a token again.
+	Take(ctx context.Context, buckets []RateLimitBucket) (wait time.Duration, err error)
+}
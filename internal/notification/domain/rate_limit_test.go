@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ParseRateLimit("20/1h")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimit{Count: 20, Period: time.Hour}, limit)
+	assert.Equal(t, "20/1h0m0s", limit.String())
+
+	for _, value := range []string{"", "0", "unlimited"} {
+		limit, err := ParseRateLimit(value)
+		require.NoError(t, err)
+		assert.True(t, limit.Unlimited(), value)
+	}
+
+	for _, value := range []string{"20", "x/1h", "20/soon", "20/-1h"} {
+		_, err := ParseRateLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRateLimitPolicy_Buckets(t *testing.T) {
+	policy := RateLimitPolicy{
+		Global:     RateLimit{Count: 50, Period: time.Second},
+		Recipient:  RateLimit{Count: 20, Period: time.Hour},
+		Categories: map[string]RateLimit{CategoryProduct: {Count: 3, Period: 24 * time.Hour}},
+	}
+
+	assert.Equal(t, []RateLimitBucket{
+		{Key: "global", Limit: policy.Global},
+		{Key: "recipient:user@example.com", Limit: policy.Recipient},
+		{Key: "category:product:user@example.com", Limit: policy.Categories[CategoryProduct]},
+	}, policy.Buckets("User@Example.com", CategoryProduct))
+	assert.Len(t, policy.Buckets("user@example.com", CategorySecurity), 2)
+	assert.Empty(t, RateLimitPolicy{}.Buckets("user@example.com", CategoryProduct))
+}
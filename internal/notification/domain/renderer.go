@@ -0,0 +1,8 @@
+package domain
+
+// Renderer renders the subject and bodies of an email from its template.
+type Renderer interface {
+	// Render sets the Subject, Body and HTMLBody of emailSend from its TemplateName and
+	// TemplateData. Emails without a template are laid out from their Subject and Body.
+	Render(emailSend *EmailSend) error
+}
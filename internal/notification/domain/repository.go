@@ -1,9 +1,26 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// EmailSendFilter selects email sends in the delivery history. Empty fields match everything.
+type EmailSendFilter struct {
+	Recipient string
+	Status    string
+	Limit     int
+	Offset    int
+}
 
 type Repository interface {
 	Save(ctx context.Context, emailSend *EmailSend) error
 	GetByID(ctx context.Context, id string) (*EmailSend, error)
 	GetByRecipient(ctx context.Context, recipient string, limit int) ([]*EmailSend, error)
+	// List returns the email sends matching filter, newest first.
+	List(ctx context.Context, filter EmailSendFilter) ([]*EmailSend, error)
+	// FindByIdempotencyKey returns the most recent email send with the idempotency key created
+	// since the given time that was delivered or is being delivered, i.e. that is neither failed,
+	// a duplicate, deferred, dropped, scheduled nor cancelled. It returns ErrEmailSendNotFound if there is none.
+	FindByIdempotencyKey(ctx context.Context, key string, since time.Time) (*EmailSend, error)
 }
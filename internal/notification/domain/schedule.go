@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// ErrScheduledEmailNotFound is returned when cancelling an email that is not held anymore, because
+// it was already sent, cancelled or never scheduled.
+var ErrScheduledEmailNotFound = errors.New("scheduled email not found")
+
+// DeferredQueue holds email requests until they are due to be sent: the emails scheduled for later
+// and the emails held back by a rate limit. Requests are held by idempotency key, so that holding
+// a request again replaces the one held with the same key.
+type DeferredQueue interface {
+	// Defer holds request until at.
+	Defer(ctx context.Context, request events.EmailSendRequest, at time.Time) error
+	// PopDue removes and returns up to limit requests due at now, the earliest first.
+	PopDue(ctx context.Context, now time.Time, limit int) ([]events.EmailSendRequest, error)
+	// Cancel removes and returns the request held with idempotencyKey, or returns
+	// ErrScheduledEmailNotFound.
+	Cancel(ctx context.Context, idempotencyKey string) (events.EmailSendRequest, error)
+}
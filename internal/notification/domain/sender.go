@@ -1,7 +1,34 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Sender delivers rendered emails through an email transport, like SMTP or the API of an email
+// provider.
 type Sender interface {
 	Send(ctx context.Context, emailSend *EmailSend) error
 }
+
+// CapturedEmail is an email kept by a Mailbox instead of being delivered. Raw is its MIME message.
+type CapturedEmail struct {
+	ID         string
+	Recipient  string
+	Subject    string
+	Body       string
+	HTMLBody   string
+	Raw        []byte
+	CapturedAt time.Time
+}
+
+// Mailbox is implemented by the Senders that capture emails for development and tests, so that
+// they can be inspected.
+type Mailbox interface {
+	// List returns the captured emails, the most recent first.
+	List() []*CapturedEmail
+	// Get returns the captured email with the ID of its EmailSend, or ErrEmailSendNotFound.
+	Get(id string) (*CapturedEmail, error)
+	// Clear discards the captured emails.
+	Clear()
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrInvalidFeedback     = errors.New("invalid delivery feedback")
+	ErrUnknownProvider     = errors.New("unknown email provider")
+)
+
+// Delivery feedback types, matching the email_suppressions.reason CHECK constraint.
+const (
+	// FeedbackHardBounce is a permanent delivery failure, like a mailbox that does not exist.
+	FeedbackHardBounce = "hard_bounce"
+	// FeedbackSoftBounce is a temporary delivery failure, like a full mailbox.
+	FeedbackSoftBounce = "soft_bounce"
+	// FeedbackComplaint is a recipient marking an email as spam.
+	FeedbackComplaint = "complaint"
+)
+
+// Sources of delivery feedback.
+const (
+	FeedbackSourceWebhook = "webhook"
+	FeedbackSourceMailbox = "mailbox"
+)
+
+// IsValidFeedbackType reports whether feedbackType is a delivery feedback type.
+func IsValidFeedbackType(feedbackType string) bool {
+	switch feedbackType {
+	case FeedbackHardBounce, FeedbackSoftBounce, FeedbackComplaint:
+		return true
+	}
+	return false
+}
+
+// NormalizeRecipient returns the form email addresses are suppressed under.
+func NormalizeRecipient(recipient string) string {
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
+
+// DeliveryFeedback is a bounce or complaint reported for an email sent to Recipient, by the
+// webhook of the email provider or a bounce report in the bounce mailbox. Detail is the diagnostic
+// of the report, and Source names where it came from (e.g. "webhook:ses").
+type DeliveryFeedback struct {
+	Recipient  string
+	Type       string
+	Detail     string
+	Source     string
+	OccurredAt time.Time
+}
+
+// Suppression is an email address that is not sent emails anymore, because of the bounces or
+// complaint of Reason. SoftBounces counts the soft bounces of the address within the soft bounce
+// window of the SuppressionPolicy. SuppressedUntil is nil for permanent suppressions.
+type Suppression struct {
+	Recipient       string
+	Reason          string
+	Detail          string
+	Source          string
+	SoftBounces     int
+	SuppressedUntil *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Active reports whether the address is suppressed at now.
+func (s *Suppression) Active(now time.Time) bool {
+	return s.SuppressedUntil == nil || now.Before(*s.SuppressedUntil)
+}
+
+// SuppressionPolicy is how delivery feedback suppresses addresses. Hard bounces and complaints
+// suppress an address permanently. A soft bounce suppresses it for SoftBouncePeriod, and
+// permanently once it soft bounced SoftBounceLimit times, soft bounces older than
+// SoftBounceWindow being forgotten. A zero SoftBounceLimit never suppresses soft bouncing
+// addresses permanently.
+type SuppressionPolicy struct {
+	SoftBouncePeriod time.Duration
+	SoftBounceLimit  int
+	SoftBounceWindow time.Duration
+}
+
+// Apply returns suppression, which is nil for an address that is not suppressed yet, updated with
+// feedback. A complaint is never replaced by a bounce, and a permanent bounce by a soft one.
+func (p SuppressionPolicy) Apply(suppression *Suppression, feedback DeliveryFeedback) *Suppression {
+	updated := Suppression{Recipient: NormalizeRecipient(feedback.Recipient), CreatedAt: feedback.OccurredAt}
+	if suppression != nil {
+		updated = *suppression
+	}
+
+	switch feedback.Type {
+	case FeedbackHardBounce, FeedbackComplaint:
+		if updated.Reason == FeedbackComplaint && feedback.Type != FeedbackComplaint {
+			return &updated
+		}
+		updated.Reason = feedback.Type
+		updated.SuppressedUntil = nil
+	case FeedbackSoftBounce:
+		if suppression != nil && updated.Reason != FeedbackSoftBounce {
+			return &updated
+		}
+		if suppression != nil && p.SoftBounceWindow > 0 && feedback.OccurredAt.Sub(updated.UpdatedAt) > p.SoftBounceWindow {
+			updated.SoftBounces = 0
+		}
+		updated.Reason = FeedbackSoftBounce
+		updated.SoftBounces++
+		if p.SoftBounceLimit > 0 && updated.SoftBounces >= p.SoftBounceLimit {
+			updated.SuppressedUntil = nil
+		} else {
+			suppressedUntil := feedback.OccurredAt.Add(p.SoftBouncePeriod)
+			updated.SuppressedUntil = &suppressedUntil
+		}
+	}
+	updated.Detail = feedback.Detail
+	updated.Source = feedback.Source
+	updated.UpdatedAt = feedback.OccurredAt
+	return &updated
+}
+
+// SuppressionRepository stores the suppressed addresses, one per normalized address.
+type SuppressionRepository interface {
+	// Get returns the suppression of recipient, active or not, or ErrSuppressionNotFound.
+	Get(ctx context.Context, recipient string) (*Suppression, error)
+	// Save inserts or replaces the suppression.
+	Save(ctx context.Context, suppression *Suppression) error
+	// List returns the suppressions, most recently updated first.
+	List(ctx context.Context, limit, offset int) ([]*Suppression, error)
+	// Delete removes the suppression of recipient, or returns ErrSuppressionNotFound.
+	Delete(ctx context.Context, recipient string) error
+}
+
+// FeedbackParser parses the bounces and complaints of the webhook payloads of an email provider.
+type FeedbackParser interface {
+	ParseFeedback(body []byte) ([]DeliveryFeedback, error)
+}
+
+// BounceMailbox is the mailbox the bounce and complaint reports of sent emails are delivered to.
+type BounceMailbox interface {
+	// Process parses each new report and passes its feedback, if any, to handle. Reports are
+	// marked as processed unless handle fails, in which case they are processed again on the next
+	// call. It returns the number of reports processed.
+	Process(ctx context.Context, handle func(ctx context.Context, feedback []DeliveryFeedback) error) (int, error)
+}
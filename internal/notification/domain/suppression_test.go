@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressionPolicy_Apply(t *testing.T) {
+	policy := SuppressionPolicy{SoftBouncePeriod: 24 * time.Hour, SoftBounceLimit: 3, SoftBounceWindow: 7 * 24 * time.Hour}
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	softBounce := func(at time.Time) DeliveryFeedback {
+		return DeliveryFeedback{Recipient: "User@Example.com", Type: FeedbackSoftBounce, Source: FeedbackSourceMailbox, OccurredAt: at}
+	}
+
+	// A soft bounce suppresses the address for the soft bounce period
+	suppression := policy.Apply(nil, softBounce(now))
+	assert.Equal(t, "user@example.com", suppression.Recipient)
+	assert.Equal(t, FeedbackSoftBounce, suppression.Reason)
+	assert.Equal(t, 1, suppression.SoftBounces)
+	assert.True(t, suppression.Active(now.Add(23*time.Hour)))
+	assert.False(t, suppression.Active(now.Add(25*time.Hour)))
+
+	// Soft bounces older than the window are forgotten
+	suppression = policy.Apply(suppression, softBounce(now.Add(8*24*time.Hour)))
+	assert.Equal(t, 1, suppression.SoftBounces)
+
+	// The limit of soft bounces suppresses the address permanently
+	suppression = policy.Apply(suppression, softBounce(now.Add(9*24*time.Hour)))
+	suppression = policy.Apply(suppression, softBounce(now.Add(10*24*time.Hour)))
+	assert.Equal(t, 3, suppression.SoftBounces)
+	assert.Nil(t, suppression.SuppressedUntil)
+
+	// A complaint is not replaced by a later bounce
+	suppression = policy.Apply(suppression, DeliveryFeedback{Recipient: "user@example.com", Type: FeedbackComplaint, OccurredAt: now})
+	suppression = policy.Apply(suppression, DeliveryFeedback{Recipient: "user@example.com", Type: FeedbackHardBounce, OccurredAt: now})
+	assert.Equal(t, FeedbackComplaint, suppression.Reason)
+	assert.True(t, suppression.Active(now.Add(365*24*time.Hour)))
+}
+
+func TestSuppressionPolicy_ApplyKeepsHardBounces(t *testing.T) {
+	policy := SuppressionPolicy{SoftBouncePeriod: time.Hour}
+	now := time.Now()
+
+	suppression := policy.Apply(nil, DeliveryFeedback{Recipient: "gone@example.com", Type: FeedbackHardBounce, OccurredAt: now})
+	suppression = policy.Apply(suppression, DeliveryFeedback{Recipient: "gone@example.com", Type: FeedbackSoftBounce, OccurredAt: now})
+
+	require.NotNil(t, suppression)
+	assert.Equal(t, FeedbackHardBounce, suppression.Reason)
+	assert.Nil(t, suppression.SuppressedUntil)
+}
@@ -1 +0,0 @@
-package notification
\ No newline at end of file
@@ -0,0 +1,177 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// ParseBounceReport parses the delivery feedback of a message received in the bounce mailbox: the
+// failed recipients of a delivery status notification (RFC 3464) and the recipient of an abuse
+// report (RFC 5965). Other messages, like auto-replies, have no feedback.
+func ParseBounceReport(r io.Reader) ([]domain.DeliveryFeedback, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bounce report: %w", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, nil
+	}
+	// Feedback of reports without a valid date is recorded as received now
+	occurredAt, _ := message.Header.Date()
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	switch strings.ToLower(params["report-type"]) {
+	case "delivery-status":
+		return parseDeliveryStatusReport(parts, occurredAt)
+	case "feedback-report":
+		return parseFeedbackReport(parts, occurredAt)
+	}
+	return nil, nil
+}
+
+// parseDeliveryStatusReport returns a bounce for each recipient whose delivery failed, hard for
+// permanent (5.x.x) failures and soft for transient (4.x.x) ones. Delayed deliveries are still
+// being retried by the reporting server, so they are not bounces.
+func parseDeliveryStatusReport(parts *multipart.Reader, occurredAt time.Time) ([]domain.DeliveryFeedback, error) {
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("delivery status notification without a message/delivery-status part")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delivery status notification: %w", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if mediaType != "message/delivery-status" && mediaType != "message/global-delivery-status" {
+			continue
+		}
+
+		blocks, err := readHeaderBlocks(part)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delivery status: %w", err)
+		}
+		var feedback []domain.DeliveryFeedback
+		// The first block holds the per-message fields, the others one recipient each
+		for _, fields := range blocks[min(1, len(blocks)):] {
+			if !strings.EqualFold(fields.Get("Action"), "failed") {
+				continue
+			}
+			recipient := reportAddress(fields.Get("Final-Recipient"))
+			if recipient == "" {
+				recipient = reportAddress(fields.Get("Original-Recipient"))
+			}
+			if recipient == "" {
+				continue
+			}
+			feedbackType := domain.FeedbackHardBounce
+			if strings.HasPrefix(strings.TrimSpace(fields.Get("Status")), "4") {
+				feedbackType = domain.FeedbackSoftBounce
+			}
+			detail := strings.TrimSpace(fields.Get("Status"))
+			if diagnostic := reportValue(fields.Get("Diagnostic-Code")); diagnostic != "" {
+				detail += " " + diagnostic
+			}
+			feedback = append(feedback, domain.DeliveryFeedback{
+				Recipient:  recipient,
+				Type:       feedbackType,
+				Detail:     detail,
+				Source:     domain.FeedbackSourceMailbox,
+				OccurredAt: occurredAt,
+			})
+		}
+		return feedback, nil
+	}
+}
+
+// parseFeedbackReport returns a complaint for the recipient of an abuse report, taken from its
+// Original-Rcpt-To field or, when missing, from the To header of the reported message.
+func parseFeedbackReport(parts *multipart.Reader, occurredAt time.Time) ([]domain.DeliveryFeedback, error) {
+	var feedbackType, recipient string
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feedback report: %w", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch mediaType {
+		case "message/feedback-report":
+			blocks, err := readHeaderBlocks(part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read feedback report: %w", err)
+			}
+			if len(blocks) > 0 {
+				feedbackType = strings.TrimSpace(blocks[0].Get("Feedback-Type"))
+				if address := reportAddress(blocks[0].Get("Original-Rcpt-To")); address != "" {
+					recipient = address
+				}
+			}
+		case "message/rfc822", "text/rfc822-headers":
+			if recipient != "" {
+				continue
+			}
+			header, err := textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+			if err != nil && !errors.Is(err, io.EOF) {
+				continue
+			}
+			if address, err := mail.ParseAddress(header.Get("To")); err == nil {
+				recipient = address.Address
+			}
+		}
+	}
+	if feedbackType == "" || strings.EqualFold(feedbackType, "not-spam") || recipient == "" {
+		return nil, nil
+	}
+	return []domain.DeliveryFeedback{{
+		Recipient:  recipient,
+		Type:       domain.FeedbackComplaint,
+		Detail:     feedbackType,
+		Source:     domain.FeedbackSourceMailbox,
+		OccurredAt: occurredAt,
+	}}, nil
+}
+
+// readHeaderBlocks reads the blocks of header fields, separated by blank lines, of a report part.
+func readHeaderBlocks(r io.Reader) ([]textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	var blocks []textproto.MIMEHeader
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			blocks = append(blocks, fields)
+		}
+		if errors.Is(err, io.EOF) {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reportAddress returns the address of a typed address field, like "rfc822; user@example.com".
+func reportAddress(value string) string {
+	address := strings.Trim(reportValue(value), "<>")
+	return domain.NormalizeRecipient(address)
+}
+
+// reportValue strips the type of a typed report field, like "smtp; 550 5.1.1 User unknown".
+func reportValue(value string) string {
+	if _, typed, ok := strings.Cut(value, ";"); ok {
+		return strings.TrimSpace(typed)
+	}
+	return strings.TrimSpace(value)
+}
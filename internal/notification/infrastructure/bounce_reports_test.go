@@ -0,0 +1,99 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deliveryStatusReport = "From: MAILER-DAEMON@mx.example.com\r\n" +
+	"To: bounces@chatear.app\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"Date: Mon, 06 May 2024 10:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"dsn\"\r\n" +
+	"\r\n" +
+	"--dsn\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message could not be delivered.\r\n" +
+	"--dsn\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"Arrival-Date: Mon, 06 May 2024 09:59:58 +0000\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; Gone@Example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 User unknown\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; full@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; slow@example.com\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.4.1\r\n" +
+	"--dsn--\r\n"
+
+const abuseReport = "From: fbl@isp.example.net\r\n" +
+	"To: bounces@chatear.app\r\n" +
+	"Subject: Abuse report\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=feedback-report; boundary=\"arf\"\r\n" +
+	"\r\n" +
+	"--arf\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"This is an email abuse report.\r\n" +
+	"--arf\r\n" +
+	"Content-Type: message/feedback-report\r\n" +
+	"\r\n" +
+	"Feedback-Type: abuse\r\n" +
+	"User-Agent: ExampleFBL/1.0\r\n" +
+	"Version: 1\r\n" +
+	"--arf\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"From: Chatear <no-reply@chatear.app>\r\n" +
+	"To: <user@example.net>\r\n" +
+	"Subject: Welcome\r\n" +
+	"\r\n" +
+	"Hello\r\n" +
+	"--arf--\r\n"
+
+func TestParseBounceReport_DeliveryStatusNotification(t *testing.T) {
+	feedback, err := ParseBounceReport(strings.NewReader(deliveryStatusReport))
+	require.NoError(t, err)
+
+	occurredAt := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	require.Len(t, feedback, 2)
+	assert.Equal(t, "gone@example.com", feedback[0].Recipient)
+	assert.Equal(t, domain.FeedbackHardBounce, feedback[0].Type)
+	assert.Equal(t, "5.1.1 550 5.1.1 User unknown", feedback[0].Detail)
+	assert.Equal(t, domain.FeedbackSourceMailbox, feedback[0].Source)
+	assert.True(t, occurredAt.Equal(feedback[0].OccurredAt))
+	assert.Equal(t, "full@example.com", feedback[1].Recipient)
+	assert.Equal(t, domain.FeedbackSoftBounce, feedback[1].Type)
+}
+
+func TestParseBounceReport_AbuseReport(t *testing.T) {
+	feedback, err := ParseBounceReport(strings.NewReader(abuseReport))
+	require.NoError(t, err)
+
+	require.Len(t, feedback, 1)
+	assert.Equal(t, "user@example.net", feedback[0].Recipient)
+	assert.Equal(t, domain.FeedbackComplaint, feedback[0].Type)
+	assert.Equal(t, "abuse", feedback[0].Detail)
+}
+
+func TestParseBounceReport_IgnoresOtherMessages(t *testing.T) {
+	feedback, err := ParseBounceReport(strings.NewReader("From: user@example.com\r\nSubject: Out of office\r\n\r\nBack on Monday\r\n"))
+	require.NoError(t, err)
+	assert.Empty(t, feedback)
+}
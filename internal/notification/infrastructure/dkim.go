@@ -0,0 +1,202 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+)
+
+// dkimSignedHeaders are the headers covered by DKIM signatures, when present in the message.
+// List-Unsubscribe and List-Unsubscribe-Post must be signed for one-click unsubscribe (RFC 8058).
+var dkimSignedHeaders = []string{
+	"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type",
+	"Content-Language", "List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// DKIMSigner signs messages with DKIM (RFC 6376), using relaxed canonicalization of the header
+// and the body, and rsa-sha256 or ed25519-sha256 (RFC 8463) depending on its key.
+type DKIMSigner struct {
+	domain    string
+	selector  string
+	signer    crypto.Signer
+	algorithm string
+}
+
+// NewDKIMSigner creates a new DKIMSigner from the PEM-encoded RSA (PKCS #1 or #8) or Ed25519
+// (PKCS #8) private key of selector in domain.
+func NewDKIMSigner(domain, selector string, keyPEM []byte) (*DKIMSigner, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("DKIM private key is not PEM-encoded")
+	}
+
+	var key any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid DKIM private key: %w", err)
+	}
+
+	signer := &DKIMSigner{domain: domain, selector: selector}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signer.signer, signer.algorithm = key, "rsa-sha256"
+	case ed25519.PrivateKey:
+		signer.signer, signer.algorithm = key, "ed25519-sha256"
+	default:
+		return nil, fmt.Errorf("DKIM private key must be RSA or Ed25519, not %T", key)
+	}
+	return signer, nil
+}
+
+// newDKIMSignerFromConfig creates the DKIMSigner configured by DKIM_SELECTOR and DKIM_PRIVATE_KEY
+// or DKIM_PRIVATE_KEY_PATH, signing for DKIM_DOMAIN or else for the domain of fromDomain. It
+// returns nil when DKIM is not configured.
+func newDKIMSignerFromConfig(cfg *config.Config, fromDomain string) (*DKIMSigner, error) {
+	if cfg.DKIMSelector == "" {
+		return nil, nil
+	}
+	keyPEM := []byte(cfg.DKIMPrivateKey)
+	if len(keyPEM) == 0 {
+		if cfg.DKIMPrivateKeyPath == "" {
+			return nil, errors.New("DKIM_SELECTOR is set but neither DKIM_PRIVATE_KEY nor DKIM_PRIVATE_KEY_PATH is")
+		}
+		var err error
+		if keyPEM, err = os.ReadFile(cfg.DKIMPrivateKeyPath); err != nil {
+			return nil, fmt.Errorf("failed to read DKIM private key: %w", err)
+		}
+	}
+	domain := cfg.DKIMDomain
+	if domain == "" {
+		domain = fromDomain
+	}
+	return NewDKIMSigner(domain, cfg.DKIMSelector, keyPEM)
+}
+
+// Sign returns message with a DKIM-Signature header prepended, signed at time now.
+func (s *DKIMSigner) Sign(message []byte, now time.Time) ([]byte, error) {
+	header, body, ok := bytes.Cut(message, []byte("\r\n\r\n"))
+	if !ok {
+		return nil, errors.New("message has no body")
+	}
+	fields := parseHeaderFields(string(header) + "\r\n")
+
+	bodyHash := sha256.Sum256(canonicalizeBodyRelaxed(body))
+	var signed []string
+	var hashed strings.Builder
+	for _, name := range dkimSignedHeaders {
+		if field, ok := lastHeaderField(fields, name); ok {
+			signed = append(signed, name)
+			hashed.WriteString(canonicalizeHeaderRelaxed(field))
+		}
+	}
+
+	value := "v=1; a=" + s.algorithm + "; c=relaxed/relaxed; d=" + s.domain + "; s=" + s.selector + ";\r\n" +
+		"\tt=" + strconv.FormatInt(now.Unix(), 10) + "; h=" + strings.Join(signed, ":") + ";\r\n" +
+		"\tbh=" + base64.StdEncoding.EncodeToString(bodyHash[:]) + ";\r\n" +
+		"\tb="
+	// The signature covers its own header with an empty b= tag and without the final CRLF
+	hashed.WriteString(strings.TrimSuffix(canonicalizeHeaderRelaxed("DKIM-Signature: "+value+"\r\n"), "\r\n"))
+	digest := sha256.Sum256([]byte(hashed.String()))
+
+	var signature []byte
+	var err error
+	if s.algorithm == "ed25519-sha256" {
+		// Ed25519 signs the SHA-256 hash itself (RFC 8463)
+		signature, err = s.signer.Sign(rand.Reader, digest[:], crypto.Hash(0))
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message with DKIM: %w", err)
+	}
+
+	signedMessage := make([]byte, 0, len(message)+len(value)+512)
+	signedMessage = append(signedMessage, "DKIM-Signature: "+value+base64.StdEncoding.EncodeToString(signature)+"\r\n"...)
+	return append(signedMessage, message...), nil
+}
+
+// parseHeaderFields splits a CRLF-terminated header into its fields, keeping their folding.
+func parseHeaderFields(header string) []string {
+	var fields []string
+	for _, line := range strings.SplitAfter(header, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+		} else {
+			fields = append(fields, line)
+		}
+	}
+	return fields
+}
+
+// lastHeaderField returns the last field named name, which is the one DKIM signs first.
+func lastHeaderField(fields []string, name string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fieldName, _, ok := strings.Cut(fields[i], ":"); ok && strings.EqualFold(strings.TrimRight(fieldName, " \t"), name) {
+			return fields[i], true
+		}
+	}
+	return "", false
+}
+
+// canonicalizeHeaderRelaxed applies the relaxed header canonicalization of RFC 6376 3.4.2 to a
+// CRLF-terminated field.
+func canonicalizeHeaderRelaxed(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = strings.ReplaceAll(value, "\r\n", "")
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Trim(collapseWhitespace(value), " ") + "\r\n"
+}
+
+// canonicalizeBodyRelaxed applies the relaxed body canonicalization of RFC 6376 3.4.4.
+func canonicalizeBodyRelaxed(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// collapseWhitespace replaces every run of spaces and tabs with a single space.
+func collapseWhitespace(line string) string {
+	var collapsed strings.Builder
+	inWhitespace := false
+	for _, r := range line {
+		if r == ' ' || r == '\t' {
+			if !inWhitespace {
+				collapsed.WriteByte(' ')
+			}
+			inWhitespace = true
+			continue
+		}
+		inWhitespace = false
+		collapsed.WriteRune(r)
+	}
+	return collapsed.String()
+}
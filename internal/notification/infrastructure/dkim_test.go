@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDKIMCanonicalization_RFC6376Example(t *testing.T) {
+	// RFC 6376 section 3.4.5
+	fields := parseHeaderFields("A: X\r\nB : Y\t\r\n\tZ  \r\n")
+	require.Len(t, fields, 2)
+	assert.Equal(t, "a:X\r\n", canonicalizeHeaderRelaxed(fields[0]))
+	assert.Equal(t, "b:Y Z\r\n", canonicalizeHeaderRelaxed(fields[1]))
+	assert.Equal(t, " C\r\nD E\r\n", string(canonicalizeBodyRelaxed([]byte(" C \r\nD \t E\r\n\r\n\r\n"))))
+	assert.Empty(t, canonicalizeBodyRelaxed([]byte("\r\n\r\n")))
+}
+
+func TestDKIMSigner_SignsWithRSAAndEd25519(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		algorithm string
+		keyPEM    []byte
+		verify    func(digest, signature []byte) bool
+	}{
+		{
+			"rsa-sha256",
+			pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			func(digest, signature []byte) bool {
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest, signature) == nil
+			},
+		},
+		{
+			"ed25519-sha256",
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+			func(digest, signature []byte) bool { return ed25519.Verify(edPublic, digest, signature) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			builder, err := newMessageBuilder(&config.Config{
+				SMTPFrom:       "Chatear <no-reply@chatear.app>",
+				DKIMSelector:   "mail",
+				DKIMPrivateKey: string(tt.keyPEM),
+			})
+			require.NoError(t, err)
+
+			raw, err := builder.build(&domain.EmailSend{
+				ID:             "1",
+				Recipient:      "user@example.com",
+				Subject:        "Novidades do Chatear",
+				Body:           "Olá!",
+				HTMLBody:       "<p>Olá!</p>",
+				Locale:         "pt-BR",
+				UnsubscribeURL: "https://chatear.app/unsubscribe?token=abc",
+			}, time.Now())
+			require.NoError(t, err)
+
+			message := string(raw)
+			require.True(t, strings.HasPrefix(message, "DKIM-Signature: "))
+			header, body, _ := strings.Cut(message, "\r\n\r\n")
+			fields := parseHeaderFields(header + "\r\n")
+			tags := dkimTags(fields[0])
+			assert.Equal(t, tt.algorithm, tags["a"])
+			assert.Equal(t, "chatear.app", tags["d"])
+			assert.Equal(t, "mail", tags["s"])
+			assert.Equal(t, "From:To:Subject:Date:Message-ID:MIME-Version:Content-Type:Content-Language:List-Unsubscribe:List-Unsubscribe-Post", tags["h"])
+
+			bodyHash := sha256.Sum256(canonicalizeBodyRelaxed([]byte(body)))
+			assert.Equal(t, base64.StdEncoding.EncodeToString(bodyHash[:]), tags["bh"])
+
+			var hashed strings.Builder
+			for _, name := range strings.Split(tags["h"], ":") {
+				field, ok := lastHeaderField(fields[1:], name)
+				require.True(t, ok, name)
+				hashed.WriteString(canonicalizeHeaderRelaxed(field))
+			}
+			unsigned := regexp.MustCompile(`b=[A-Za-z0-9+/=]+\r\n$`).ReplaceAllString(fields[0], "b=\r\n")
+			hashed.WriteString(strings.TrimSuffix(canonicalizeHeaderRelaxed(unsigned), "\r\n"))
+			digest := sha256.Sum256([]byte(hashed.String()))
+			signature, err := base64.StdEncoding.DecodeString(tags["b"])
+			require.NoError(t, err)
+			assert.True(t, tt.verify(digest[:], signature), "signature verifies")
+
+			_, err = mail.ReadMessage(strings.NewReader(message))
+			assert.NoError(t, err, "signed message is still a valid message")
+		})
+	}
+}
+
+func TestNewMessageBuilder_RequiresDKIMKey(t *testing.T) {
+	_, err := newMessageBuilder(&config.Config{SMTPFrom: "no-reply@chatear.app", DKIMSelector: "mail"})
+	assert.ErrorContains(t, err, "DKIM_PRIVATE_KEY")
+
+	_, err = newMessageBuilder(&config.Config{SMTPFrom: "no-reply@chatear.app", DKIMSelector: "mail", DKIMPrivateKey: "not a key"})
+	assert.Error(t, err)
+}
+
+// dkimTags parses the tags of a DKIM-Signature field.
+func dkimTags(field string) map[string]string {
+	_, value, _ := strings.Cut(field, ":")
+	tags := map[string]string{}
+	for _, tag := range strings.Split(value, ";") {
+		name, tagValue, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(name)] = strings.Join(strings.Fields(tagValue), "")
+	}
+	return tags
+}
@@ -0,0 +1,213 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// NewFeedbackParsers returns the parsers of the bounce and complaint webhooks of the supported
+// email providers, by provider name.
+func NewFeedbackParsers() map[string]domain.FeedbackParser {
+	return map[string]domain.FeedbackParser{
+		"generic":  feedbackParserFunc(parseGenericFeedback),
+		"ses":      feedbackParserFunc(parseSESFeedback),
+		"sendgrid": feedbackParserFunc(parseSendGridFeedback),
+		"postmark": feedbackParserFunc(parsePostmarkFeedback),
+	}
+}
+
+type feedbackParserFunc func(body []byte) ([]domain.DeliveryFeedback, error)
+
+func (f feedbackParserFunc) ParseFeedback(body []byte) ([]domain.DeliveryFeedback, error) {
+	return f(body)
+}
+
+// genericFeedbackEvent is an event of the generic webhook, a JSON array of them, for providers
+// without an adapter and for relays translating their payloads.
+type genericFeedbackEvent struct {
+	Recipient  string    `json:"recipient"`
+	Type       string    `json:"type"`
+	Detail     string    `json:"detail"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func parseGenericFeedback(body []byte) ([]domain.DeliveryFeedback, error) {
+	var events []genericFeedbackEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFeedback, err)
+	}
+	feedback := make([]domain.DeliveryFeedback, 0, len(events))
+	for _, event := range events {
+		if !domain.IsValidFeedbackType(event.Type) {
+			return nil, fmt.Errorf("%w: unknown type %q", domain.ErrInvalidFeedback, event.Type)
+		}
+		feedback = append(feedback, domain.DeliveryFeedback{
+			Recipient:  event.Recipient,
+			Type:       event.Type,
+			Detail:     event.Detail,
+			Source:     domain.FeedbackSourceWebhook + ":generic",
+			OccurredAt: event.OccurredAt,
+		})
+	}
+	return feedback, nil
+}
+
+// snsMessage is the envelope of the Amazon SNS notifications SES publishes its feedback in.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesNotification is an SES bounce or complaint notification. Notifications have a
+// notificationType, and the event publishing of configuration sets an eventType.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           struct {
+		BounceType        string    `json:"bounceType"`
+		Timestamp         time.Time `json:"timestamp"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string    `json:"complaintFeedbackType"`
+		Timestamp             time.Time `json:"timestamp"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+func parseSESFeedback(body []byte) ([]domain.DeliveryFeedback, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFeedback, err)
+	}
+	switch envelope.Type {
+	case "Notification":
+	case "SubscriptionConfirmation":
+		return nil, fmt.Errorf("%w: confirm the SNS subscription by opening %s", domain.ErrInvalidFeedback, envelope.SubscribeURL)
+	default:
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(envelope.Message), &notification); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFeedback, err)
+	}
+	source := domain.FeedbackSourceWebhook + ":ses"
+	var feedback []domain.DeliveryFeedback
+	switch notification.NotificationType + notification.EventType {
+	case "Bounce":
+		feedbackType := domain.FeedbackSoftBounce
+		if notification.Bounce.BounceType == "Permanent" {
+			feedbackType = domain.FeedbackHardBounce
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			feedback = append(feedback, domain.DeliveryFeedback{
+				Recipient:  recipient.EmailAddress,
+				Type:       feedbackType,
+				Detail:     recipient.DiagnosticCode,
+				Source:     source,
+				OccurredAt: notification.Bounce.Timestamp,
+			})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			feedback = append(feedback, domain.DeliveryFeedback{
+				Recipient:  recipient.EmailAddress,
+				Type:       domain.FeedbackComplaint,
+				Detail:     notification.Complaint.ComplaintFeedbackType,
+				Source:     source,
+				OccurredAt: notification.Complaint.Timestamp,
+			})
+		}
+	}
+	return feedback, nil
+}
+
+// sendGridEvent is an event of the SendGrid event webhook, which posts JSON arrays of them.
+type sendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func parseSendGridFeedback(body []byte) ([]domain.DeliveryFeedback, error) {
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFeedback, err)
+	}
+	var feedback []domain.DeliveryFeedback
+	for _, event := range events {
+		var feedbackType string
+		switch {
+		case event.Event == "spamreport":
+			feedbackType = domain.FeedbackComplaint
+		case event.Event == "bounce" && event.Type == "blocked":
+			feedbackType = domain.FeedbackSoftBounce
+		case event.Event == "bounce":
+			feedbackType = domain.FeedbackHardBounce
+		default:
+			continue
+		}
+		var occurredAt time.Time
+		if event.Timestamp > 0 {
+			occurredAt = time.Unix(event.Timestamp, 0)
+		}
+		feedback = append(feedback, domain.DeliveryFeedback{
+			Recipient:  event.Email,
+			Type:       feedbackType,
+			Detail:     event.Reason,
+			Source:     domain.FeedbackSourceWebhook + ":sendgrid",
+			OccurredAt: occurredAt,
+		})
+	}
+	return feedback, nil
+}
+
+// postmarkEvent is a Postmark bounce or spam complaint webhook, which posts one event at a time.
+type postmarkEvent struct {
+	RecordType  string    `json:"RecordType"`
+	Type        string    `json:"Type"`
+	Email       string    `json:"Email"`
+	Description string    `json:"Description"`
+	Details     string    `json:"Details"`
+	BouncedAt   time.Time `json:"BouncedAt"`
+}
+
+func parsePostmarkFeedback(body []byte) ([]domain.DeliveryFeedback, error) {
+	var event postmarkEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFeedback, err)
+	}
+	var feedbackType string
+	switch {
+	case event.RecordType == "SpamComplaint" || event.Type == "SpamComplaint":
+		feedbackType = domain.FeedbackComplaint
+	case event.RecordType != "Bounce":
+		return nil, nil
+	case event.Type == "HardBounce" || event.Type == "BadEmailAddress":
+		feedbackType = domain.FeedbackHardBounce
+	case event.Type == "SoftBounce" || event.Type == "Transient" || event.Type == "DnsError":
+		feedbackType = domain.FeedbackSoftBounce
+	default:
+		return nil, nil
+	}
+	return []domain.DeliveryFeedback{{
+		Recipient:  event.Email,
+		Type:       feedbackType,
+		Detail:     strings.TrimSpace(event.Description + " " + event.Details),
+		Source:     domain.FeedbackSourceWebhook + ":postmark",
+		OccurredAt: event.BouncedAt,
+	}}, nil
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackParsers(t *testing.T) {
+	tests := []struct {
+		provider string
+		body     string
+		want     []domain.DeliveryFeedback
+	}{
+		{
+			provider: "generic",
+			body:     `[{"recipient":"gone@example.com","type":"hard_bounce","detail":"550 5.1.1"}]`,
+			want:     []domain.DeliveryFeedback{{Recipient: "gone@example.com", Type: domain.FeedbackHardBounce, Detail: "550 5.1.1", Source: "webhook:generic"}},
+		},
+		{
+			provider: "ses",
+			body: `{"Type":"Notification","Message":"{\"notificationType\":\"Bounce\",\"bounce\":{\"bounceType\":\"Permanent\",` +
+				`\"bouncedRecipients\":[{\"emailAddress\":\"gone@example.com\",\"diagnosticCode\":\"smtp; 550 5.1.1\"}]}}"}`,
+			want: []domain.DeliveryFeedback{{Recipient: "gone@example.com", Type: domain.FeedbackHardBounce, Detail: "smtp; 550 5.1.1", Source: "webhook:ses"}},
+		},
+		{
+			provider: "ses",
+			body: `{"Type":"Notification","Message":"{\"eventType\":\"Complaint\",\"complaint\":{\"complaintFeedbackType\":\"abuse\",` +
+				`\"complainedRecipients\":[{\"emailAddress\":\"user@example.com\"}]}}"}`,
+			want: []domain.DeliveryFeedback{{Recipient: "user@example.com", Type: domain.FeedbackComplaint, Detail: "abuse", Source: "webhook:ses"}},
+		},
+		{
+			provider: "sendgrid",
+			body:     `[{"email":"gone@example.com","event":"bounce","type":"bounce","reason":"550"},{"email":"user@example.com","event":"delivered"},{"email":"spam@example.com","event":"spamreport"}]`,
+			want: []domain.DeliveryFeedback{
+				{Recipient: "gone@example.com", Type: domain.FeedbackHardBounce, Detail: "550", Source: "webhook:sendgrid"},
+				{Recipient: "spam@example.com", Type: domain.FeedbackComplaint, Source: "webhook:sendgrid"},
+			},
+		},
+		{
+			provider: "postmark",
+			body:     `{"RecordType":"Bounce","Type":"SoftBounce","Email":"full@example.com","Description":"Mailbox full"}`,
+			want:     []domain.DeliveryFeedback{{Recipient: "full@example.com", Type: domain.FeedbackSoftBounce, Detail: "Mailbox full", Source: "webhook:postmark"}},
+		},
+		{
+			provider: "postmark",
+			body:     `{"RecordType":"Delivery","Email":"user@example.com"}`,
+		},
+	}
+
+	parsers := NewFeedbackParsers()
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			feedback, err := parsers[tt.provider].ParseFeedback([]byte(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, feedback)
+		})
+	}
+}
+
+func TestFeedbackParsers_RejectInvalidPayloads(t *testing.T) {
+	parsers := NewFeedbackParsers()
+
+	_, err := parsers["generic"].ParseFeedback([]byte(`[{"recipient":"user@example.com","type":"unsubscribe"}]`))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback)
+	_, err = parsers["ses"].ParseFeedback([]byte(`{"Type":"SubscriptionConfirmation","SubscribeURL":"https://sns.example.com/confirm"}`))
+	assert.ErrorContains(t, err, "https://sns.example.com/confirm")
+	_, err = parsers["sendgrid"].ParseFeedback([]byte(`not json`))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback)
+}
@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// httpEmailRequest is the JSON body posted to the email provider. Its fields follow the send
+// endpoints of the common providers, so that most of them only need a thin proxy, if any.
+type httpEmailRequest struct {
+	From      string            `json:"from"`
+	To        []string          `json:"to"`
+	Subject   string            `json:"subject"`
+	Text      string            `json:"text"`
+	HTML      string            `json:"html,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Reference string            `json:"reference"`
+}
+
+// maxHTTPErrorBody limits how much of a failed response is kept in the delivery error.
+const maxHTTPErrorBody = 512
+
+// HTTPSender sends rendered emails by posting them as JSON to the API of an email provider.
+type HTTPSender struct {
+	url    string
+	apiKey string
+	from   *mail.Address
+	client *http.Client
+}
+
+// NewHTTPSender creates a new HTTPSender posting to EMAIL_HTTP_URL with EMAIL_HTTP_API_KEY as
+// bearer token.
+func NewHTTPSender(cfg *config.Config) (*HTTPSender, error) {
+	from, err := mail.ParseAddress(cfg.SMTPFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM address %q: %w", cfg.SMTPFrom, err)
+	}
+	if cfg.EmailHTTPURL == "" {
+		return nil, errors.New("EMAIL_HTTP_URL environment variable not set")
+	}
+
+	return &HTTPSender{
+		url:    cfg.EmailHTTPURL,
+		apiKey: cfg.EmailHTTPAPIKey,
+		from:   from,
+		client: &http.Client{Timeout: cfg.EmailTimeout},
+	}, nil
+}
+
+// Send posts emailSend, which must have been rendered, to the provider. Any response other than
+// 2xx fails the delivery with the status and the start of the response body.
+func (s *HTTPSender) Send(ctx context.Context, emailSend *domain.EmailSend) error {
+	body, err := json.Marshal(httpEmailRequest{
+		From:      s.from.String(),
+		To:        []string{emailSend.Recipient},
+		Subject:   emailSend.Subject,
+		Text:      emailSend.Body,
+		HTML:      emailSend.HTMLBody,
+		Headers:   map[string]string{"Message-ID": messageID(s.from, emailSend.ID)},
+		Reference: emailSend.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post email to provider: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxHTTPErrorBody))
+		return fmt.Errorf("email provider responded %s: %s", response.Status, bytes.TrimSpace(responseBody))
+	}
+	// Drain the body so that the connection is reused
+	io.Copy(io.Discard, response.Body)
+	return nil
+}
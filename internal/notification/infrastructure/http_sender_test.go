@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender_PostsEmailToProvider(t *testing.T) {
+	var authorization string
+	var request httpEmailRequest
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer provider.Close()
+
+	sender, err := NewHTTPSender(&config.Config{SMTPFrom: "Chatear <no-reply@chatear.app>", EmailHTTPURL: provider.URL, EmailHTTPAPIKey: "key", EmailTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	err = sender.Send(context.Background(), &domain.EmailSend{ID: "42", Recipient: "user@example.com", Subject: "Olá", Body: "Oi", HTMLBody: "<p>Oi</p>"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer key", authorization)
+	assert.Equal(t, httpEmailRequest{
+		From:      `"Chatear" <no-reply@chatear.app>`,
+		To:        []string{"user@example.com"},
+		Subject:   "Olá",
+		Text:      "Oi",
+		HTML:      "<p>Oi</p>",
+		Headers:   map[string]string{"Message-ID": "<42@chatear.app>"},
+		Reference: "42",
+	}, request)
+}
+
+func TestHTTPSender_FailsOnErrorResponses(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"domain not verified"}`, http.StatusUnprocessableEntity)
+	}))
+	defer provider.Close()
+
+	sender, err := NewHTTPSender(&config.Config{SMTPFrom: "no-reply@chatear.app", EmailHTTPURL: provider.URL, EmailTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	err = sender.Send(context.Background(), &domain.EmailSend{ID: "42", Recipient: "user@example.com", Subject: "Hello", Body: "Hi"})
+	assert.ErrorContains(t, err, "422 Unprocessable Entity")
+	assert.ErrorContains(t, err, "domain not verified")
+}
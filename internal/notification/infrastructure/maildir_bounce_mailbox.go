@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// MaildirBounceMailbox is a domain.BounceMailbox reading the reports delivered into a Maildir,
+// either directly by the mail server or synced from an IMAP mailbox (e.g. by mbsync or fetchmail).
+// Processed reports are moved from new to cur and flagged as seen, as a mail client would.
+type MaildirBounceMailbox struct {
+	dir string
+}
+
+// NewMaildirBounceMailbox creates a new MaildirBounceMailbox reading from dir, creating its tmp,
+// new and cur directories when missing.
+func NewMaildirBounceMailbox(dir string) (*MaildirBounceMailbox, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create maildir %s: %w", dir, err)
+		}
+	}
+	return &MaildirBounceMailbox{dir: dir}, nil
+}
+
+// Process parses the reports in new, the oldest first. Reports that cannot be parsed are moved to
+// cur without being flagged as seen, and reported in the returned error.
+func (m *MaildirBounceMailbox) Process(ctx context.Context, handle func(ctx context.Context, feedback []domain.DeliveryFeedback) error) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(m.dir, "new"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read bounce mailbox: %w", err)
+	}
+	// Maildir names start with the delivery time, so they sort in delivery order
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	processed := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		feedback, parseErr := m.parse(entry.Name())
+		if parseErr != nil {
+			errs = append(errs, fmt.Errorf("failed to parse bounce report %s: %w", entry.Name(), parseErr))
+			if err := m.moveToCur(entry.Name(), ""); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if len(feedback) > 0 {
+			if err := handle(ctx, feedback); err != nil {
+				errs = append(errs, fmt.Errorf("failed to handle bounce report %s: %w", entry.Name(), err))
+				continue
+			}
+		}
+		if err := m.moveToCur(entry.Name(), "S"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		processed++
+	}
+	return processed, errors.Join(errs...)
+}
+
+func (m *MaildirBounceMailbox) parse(name string) ([]domain.DeliveryFeedback, error) {
+	file, err := os.Open(filepath.Join(m.dir, "new", name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseBounceReport(file)
+}
+
+// moveToCur moves a message from new to cur with the given Maildir flags.
+func (m *MaildirBounceMailbox) moveToCur(name, flags string) error {
+	if err := os.Rename(filepath.Join(m.dir, "new", name), filepath.Join(m.dir, "cur", name+":2,"+flags)); err != nil {
+		return fmt.Errorf("failed to move bounce report %s to cur: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaildirBounceMailbox_Process(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bounces")
+	mailbox, err := NewMaildirBounceMailbox(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new", "1.dsn"), []byte(deliveryStatusReport), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new", "2.reply"), []byte("Subject: Out of office\r\n\r\nBack on Monday\r\n"), 0o644))
+
+	var handled []domain.DeliveryFeedback
+	processed, err := mailbox.Process(context.Background(), func(ctx context.Context, feedback []domain.DeliveryFeedback) error {
+		handled = append(handled, feedback...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Len(t, handled, 2)
+
+	newFiles, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	assert.Empty(t, newFiles)
+	assert.FileExists(t, filepath.Join(dir, "cur", "1.dsn:2,S"))
+	assert.FileExists(t, filepath.Join(dir, "cur", "2.reply:2,S"))
+}
+
+func TestMaildirBounceMailbox_KeepsReportsWhoseFeedbackFailed(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bounces")
+	mailbox, err := NewMaildirBounceMailbox(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new", "1.dsn"), []byte(deliveryStatusReport), 0o644))
+
+	processed, err := mailbox.Process(context.Background(), func(ctx context.Context, feedback []domain.DeliveryFeedback) error {
+		return errors.New("database is down")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 0, processed)
+	assert.FileExists(t, filepath.Join(dir, "new", "1.dsn"))
+}
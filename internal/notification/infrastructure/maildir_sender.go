@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// MaildirSender writes rendered emails into a Maildir instead of sending them, each as the .eml
+// file a mail client would receive. It is meant for development and staging environments.
+type MaildirSender struct {
+	dir      string
+	messages *messageBuilder
+}
+
+// NewMaildirSender creates a new MaildirSender writing into EMAIL_FILE_DIR, creating its tmp, new
+// and cur directories when missing.
+func NewMaildirSender(cfg *config.Config) (*MaildirSender, error) {
+	messages, err := newMessageBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(cfg.EmailFileDir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create maildir %s: %w", cfg.EmailFileDir, err)
+		}
+	}
+
+	return &MaildirSender{dir: cfg.EmailFileDir, messages: messages}, nil
+}
+
+// Send writes emailSend, which must have been rendered, into the new directory of the Maildir.
+// The message is written into tmp first and then moved, so readers never see a partial file.
+func (s *MaildirSender) Send(ctx context.Context, emailSend *domain.EmailSend) error {
+	now := time.Now()
+	message, err := s.messages.build(emailSend, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.%s.eml", now.UnixNano(), emailSend.ID)
+	tmpPath := filepath.Join(s.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, message, 0o644); err != nil {
+		return fmt.Errorf("failed to write email to maildir: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(s.dir, "new", name)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to deliver email to maildir: %w", err)
+	}
+	return nil
+}
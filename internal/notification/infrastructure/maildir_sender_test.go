@@ -0,0 +1,37 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaildirSender_DeliversIntoNew(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := NewMaildirSender(&config.Config{SMTPFrom: "no-reply@chatear.app", EmailFileDir: dir})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), &domain.EmailSend{ID: "42", Recipient: "user@example.com", Subject: "Hello", Body: "Hi"}))
+
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Regexp(t, `^\d+\.42\.eml$`, files[0].Name())
+	tmpFiles, err := os.ReadDir(filepath.Join(dir, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles)
+
+	raw, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	require.NoError(t, err)
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "<user@example.com>", message.Header.Get("To"))
+}
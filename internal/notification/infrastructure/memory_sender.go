@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// memorySenderCapacity is how many emails a MemorySender keeps before discarding the oldest.
+const memorySenderCapacity = 500
+
+// MemorySender captures rendered emails in memory instead of sending them, so that development
+// environments and tests can inspect them. It implements domain.Mailbox.
+type MemorySender struct {
+	messages *messageBuilder
+
+	mu     sync.Mutex
+	emails []*domain.CapturedEmail
+}
+
+// NewMemorySender creates a new MemorySender.
+func NewMemorySender(cfg *config.Config) (*MemorySender, error) {
+	messages, err := newMessageBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &MemorySender{messages: messages}, nil
+}
+
+// Send captures emailSend, which must have been rendered, with its MIME message as it would have
+// been sent, DKIM signature included.
+func (s *MemorySender) Send(ctx context.Context, emailSend *domain.EmailSend) error {
+	now := time.Now()
+	message, err := s.messages.build(emailSend, now)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.emails) == memorySenderCapacity {
+		s.emails = s.emails[1:]
+	}
+	s.emails = append(s.emails, &domain.CapturedEmail{
+		ID:         emailSend.ID,
+		Recipient:  emailSend.Recipient,
+		Subject:    emailSend.Subject,
+		Body:       emailSend.Body,
+		HTMLBody:   emailSend.HTMLBody,
+		Raw:        message,
+		CapturedAt: now,
+	})
+	return nil
+}
+
+// List returns the captured emails, the most recent first.
+func (s *MemorySender) List() []*domain.CapturedEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emails := make([]*domain.CapturedEmail, len(s.emails))
+	for i, email := range s.emails {
+		emails[len(s.emails)-1-i] = email
+	}
+	return emails
+}
+
+// Get returns the captured email with the given EmailSend ID.
+func (s *MemorySender) Get(id string) (*domain.CapturedEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, email := range s.emails {
+		if email.ID == id {
+			return email, nil
+		}
+	}
+	return nil, domain.ErrEmailSendNotFound
+}
+
+// Clear discards the captured emails.
+func (s *MemorySender) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emails = nil
+}
@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySender_CapturesEmails(t *testing.T) {
+	sender, err := NewMemorySender(&config.Config{SMTPFrom: "no-reply@chatear.app"})
+	require.NoError(t, err)
+
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, sender.Send(context.Background(), &domain.EmailSend{ID: id, Recipient: "user@example.com", Subject: "Hello " + id, Body: "Hi"}))
+	}
+
+	emails := sender.List()
+	require.Len(t, emails, 2)
+	assert.Equal(t, "2", emails[0].ID, "most recent first")
+	email, err := sender.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello 1", email.Subject)
+	assert.Contains(t, string(email.Raw), "Message-ID: <1@chatear.app>")
+
+	sender.Clear()
+	assert.Empty(t, sender.List())
+	_, err = sender.Get("1")
+	assert.ErrorIs(t, err, domain.ErrEmailSendNotFound)
+}
+
+func TestNewSender_SelectsTransport(t *testing.T) {
+	sender, err := NewSender(&config.Config{EmailTransport: EmailTransportMemory, SMTPFrom: "no-reply@chatear.app"})
+	require.NoError(t, err)
+	assert.Implements(t, (*domain.Mailbox)(nil), sender)
+
+	sender, err = NewSender(&config.Config{EmailTransport: "pigeon", SMTPFrom: "no-reply@chatear.app"})
+	assert.ErrorContains(t, err, "EMAIL_TRANSPORT")
+	assert.Nil(t, sender)
+}
@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// messageBuilder builds the MIME messages sent by the transports that deliver whole messages,
+// signed with DKIM when it is configured.
+type messageBuilder struct {
+	from   *mail.Address
+	signer *DKIMSigner
+}
+
+// newMessageBuilder creates a messageBuilder sending from SMTP_FROM, with the DKIM settings of cfg.
+func newMessageBuilder(cfg *config.Config) (*messageBuilder, error) {
+	from, err := mail.ParseAddress(cfg.SMTPFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM address %q: %w", cfg.SMTPFrom, err)
+	}
+	signer, err := newDKIMSignerFromConfig(cfg, addressDomain(from))
+	if err != nil {
+		return nil, err
+	}
+	return &messageBuilder{from: from, signer: signer}, nil
+}
+
+// build builds the message of emailSend, dated now.
+func (b *messageBuilder) build(emailSend *domain.EmailSend, now time.Time) ([]byte, error) {
+	message, err := buildMIMEMessage(b.from, emailSend, now)
+	if err != nil || b.signer == nil {
+		return message, err
+	}
+	return b.signer.Sign(message, now)
+}
+
+// buildMIMEMessage builds the RFC 5322 message of emailSend: a multipart/alternative message with
+// its plain-text and HTML parts, or a plain-text message when it has no HTML part. Bodies are
+// quoted-printable encoded and the subject RFC 2047 encoded, so that any UTF-8 text is preserved.
+// Emails with an unsubscribe URL get the one-click List-Unsubscribe headers of RFC 8058.
+func buildMIMEMessage(from *mail.Address, emailSend *domain.EmailSend, date time.Time) ([]byte, error) {
+	to, err := mail.ParseAddress(emailSend.Recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", emailSend.Recipient, err)
+	}
+
+	var message bytes.Buffer
+	writeHeader(&message, "From", from.String())
+	writeHeader(&message, "To", to.String())
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("utf-8", emailSend.Subject))
+	writeHeader(&message, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&message, "Message-ID", messageID(from, emailSend.ID))
+	writeHeader(&message, "MIME-Version", "1.0")
+	// Asks auto-responders not to reply (RFC 3834)
+	writeHeader(&message, "Auto-Submitted", "auto-generated")
+	if emailSend.Locale != "" {
+		writeHeader(&message, "Content-Language", emailSend.Locale)
+	}
+	if emailSend.UnsubscribeURL != "" {
+		writeHeader(&message, "List-Unsubscribe", "<"+emailSend.UnsubscribeURL+">")
+		writeHeader(&message, "List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
+	if emailSend.HTMLBody == "" {
+		writeHeader(&message, "Content-Type", "text/plain; charset=UTF-8")
+		writeHeader(&message, "Content-Transfer-Encoding", "quoted-printable")
+		message.WriteString("\r\n")
+		if err := writeQuotedPrintable(&message, emailSend.Body); err != nil {
+			return nil, err
+		}
+		return message.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&message)
+	writeHeader(&message, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	message.WriteString("\r\n")
+	// Clients show the last part they support, so the HTML part comes after the text one
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", emailSend.Body},
+		{"text/html; charset=UTF-8", emailSend.HTMLBody},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
+
+func writeHeader(message *bytes.Buffer, key, value string) {
+	message.WriteString(key + ": " + value + "\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID returns the Message-ID of an email from its ID, in the domain of the sender.
+func messageID(from *mail.Address, id string) string {
+	return "<" + id + "@" + addressDomain(from) + ">"
+}
+
+// addressDomain returns the domain of address, or localhost when it has none.
+func addressDomain(address *mail.Address) string {
+	if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+		return address.Address[at+1:]
+	}
+	return "localhost"
+}
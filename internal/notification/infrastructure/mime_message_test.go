@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMIMEMessage_MultipartAlternative(t *testing.T) {
+	from := &mail.Address{Name: "Chatear", Address: "no-reply@chatear.app"}
+	emailSend := &domain.EmailSend{
+		ID:        "3f0c1d2e-0000-4000-8000-000000000001",
+		Recipient: "user@example.com",
+		Subject:   "Sua conta será excluída",
+		Body:      "Olá, João!\nAté logo.",
+		HTMLBody:  "<p>Olá, João!</p>",
+		Locale:    "pt-BR",
+	}
+
+	raw, err := buildMIMEMessage(from, emailSend, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Sua conta será excluída", subject)
+	assert.Equal(t, `"Chatear" <no-reply@chatear.app>`, message.Header.Get("From"))
+	assert.Equal(t, "<3f0c1d2e-0000-4000-8000-000000000001@chatear.app>", message.Header.Get("Message-ID"))
+	assert.Equal(t, "1.0", message.Header.Get("MIME-Version"))
+	assert.Equal(t, "Sun, 01 Mar 2026 12:00:00 +0000", message.Header.Get("Date"))
+	assert.Equal(t, "auto-generated", message.Header.Get("Auto-Submitted"))
+	assert.Equal(t, "pt-BR", message.Header.Get("Content-Language"))
+	assert.Empty(t, message.Header.Get("List-Unsubscribe"), "transactional emails cannot be unsubscribed from")
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	// multipart.Reader decodes quoted-printable parts
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var contentTypes, bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, contentTypes)
+	assert.Equal(t, []string{"Olá, João!\r\nAté logo.", "<p>Olá, João!</p>"}, bodies)
+}
+
+func TestBuildMIMEMessage_PlainText(t *testing.T) {
+	from := &mail.Address{Address: "no-reply@chatear.app"}
+	raw, err := buildMIMEMessage(from, &domain.EmailSend{ID: "1", Recipient: "user@example.com", Subject: "Hello", Body: "Hi"}, time.Now())
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=UTF-8", message.Header.Get("Content-Type"))
+	assert.Equal(t, "quoted-printable", message.Header.Get("Content-Transfer-Encoding"))
+
+	_, err = buildMIMEMessage(from, &domain.EmailSend{ID: "1", Recipient: "not an address"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestBuildMIMEMessage_ListUnsubscribe(t *testing.T) {
+	from := &mail.Address{Address: "no-reply@chatear.app"}
+	raw, err := buildMIMEMessage(from, &domain.EmailSend{
+		ID:             "1",
+		Recipient:      "user@example.com",
+		Subject:        "News",
+		Body:           "Hi",
+		UnsubscribeURL: "https://chatear.app/unsubscribe?token=abc",
+	}, time.Now())
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "<https://chatear.app/unsubscribe?token=abc>", message.Header.Get("List-Unsubscribe"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", message.Header.Get("List-Unsubscribe-Post"))
+}
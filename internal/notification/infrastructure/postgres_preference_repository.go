@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// PostgresPreferenceRepository is a PostgreSQL implementation of the domain.PreferenceRepository,
+// storing preferences in the notification_preferences table.
+type PostgresPreferenceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresPreferenceRepository creates a new PostgresPreferenceRepository.
+func NewPostgresPreferenceRepository(pool *pgxpool.Pool) *PostgresPreferenceRepository {
+	return &PostgresPreferenceRepository{pool: pool}
+}
+
+// ListByUser returns the stored preferences of a user.
+func (r *PostgresPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Preference, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT user_id, category, channel, enabled, updated_at
+		 FROM notification_preferences
+		 WHERE user_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification preferences: %w", err)
+	}
+	preferences, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Preference, error) {
+		var preference domain.Preference
+		var userID uuid.UUID
+		if err := row.Scan(&userID, &preference.Category, &preference.Channel, &preference.Enabled, &preference.UpdatedAt); err != nil {
+			return nil, err
+		}
+		preference.UserID = userID.String()
+		return &preference, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan notification preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// IsEnabled reports whether the user receives the notifications of category on channel.
+func (r *PostgresPreferenceRepository) IsEnabled(ctx context.Context, userID, category, channel string) (bool, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return true, nil
+	}
+
+	var enabled bool
+	err = r.pool.QueryRow(ctx,
+		`SELECT enabled FROM notification_preferences WHERE user_id = $1 AND category = $2 AND channel = $3`,
+		id, category, channel).Scan(&enabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	return enabled, nil
+}
+
+// Save inserts or replaces the preference.
+func (r *PostgresPreferenceRepository) Save(ctx context.Context, preference *domain.Preference) error {
+	id, err := uuid.Parse(preference.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID %q: %w", preference.UserID, err)
+	}
+
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO notification_preferences (user_id, category, channel, enabled, updated_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id, category, channel) DO UPDATE
+		 SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at`,
+		id, preference.Category, preference.Channel, preference.Enabled, preference.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// emailNotificationType is the notifications.type of email sends.
+const emailNotificationType = "email"
+
+const emailSendColumns = `id, recipient, subject, body, COALESCE(template, ''), COALESCE(category, ''),
+	COALESCE(idempotency_key, ''), COALESCE(correlation_id, ''), COALESCE(duplicate_of::text, ''), sent_at,
+	created_at, COALESCE(error, ''), status, attempts, last_attempt_at`
+
+// PostgresRepository is a PostgreSQL implementation of the domain.Repository, storing email sends
+// in the notifications table.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgresRepository.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Save inserts the email send, or updates its delivery status if it was already saved.
+func (r *PostgresRepository) Save(ctx context.Context, emailSend *domain.EmailSend) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO notifications (id, type, recipient, subject, body, template, category, idempotency_key, correlation_id, duplicate_of,
+		                            sent_at, created_at, error, status, attempts, last_attempt_at)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, '')::uuid,
+		         $11, $12, NULLIF($13, ''), $14, $15, $16)
+		 ON CONFLICT (id) DO UPDATE
+		 SET sent_at = EXCLUDED.sent_at, error = EXCLUDED.error, status = EXCLUDED.status,
+		     attempts = EXCLUDED.attempts, last_attempt_at = EXCLUDED.last_attempt_at`,
+		emailSend.ID, emailNotificationType, emailSend.Recipient, emailSend.Subject, emailSend.Body, emailSend.TemplateName, emailSend.Category,
+		emailSend.IdempotencyKey, emailSend.CorrelationID, emailSend.DuplicateOf,
+		emailSend.SentAt, emailSend.CreatedAt, emailSend.ErrorMessage, emailSend.Status, emailSend.Attempts, emailSend.LastAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save email send: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns an email send by its ID.
+func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.EmailSend, error) {
+	emailSendID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.ErrEmailSendNotFound
+	}
+
+	emailSend, err := scanEmailSend(r.pool.QueryRow(ctx,
+		`SELECT `+emailSendColumns+` FROM notifications WHERE id = $1 AND type = $2`, emailSendID, emailNotificationType))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrEmailSendNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email send: %w", err)
+	}
+	return emailSend, nil
+}
+
+// GetByRecipient returns the most recent email sends to a recipient, newest first.
+func (r *PostgresRepository) GetByRecipient(ctx context.Context, recipient string, limit int) ([]*domain.EmailSend, error) {
+	return r.List(ctx, domain.EmailSendFilter{Recipient: recipient, Limit: limit})
+}
+
+// List returns the email sends matching filter, newest first.
+func (r *PostgresRepository) List(ctx context.Context, filter domain.EmailSendFilter) ([]*domain.EmailSend, error) {
+	conditions := []string{"type = $1"}
+	args := []any{emailNotificationType}
+	if filter.Recipient != "" {
+		args = append(args, filter.Recipient)
+		conditions = append(conditions, "recipient = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, "status = $"+strconv.Itoa(len(args)))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+emailSendColumns+`
+		 FROM notifications
+		 WHERE `+strings.Join(conditions, " AND ")+`
+		 ORDER BY created_at DESC, id
+		 LIMIT $`+strconv.Itoa(len(args)-1)+` OFFSET $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email sends: %w", err)
+	}
+	emailSends, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.EmailSend, error) {
+		return scanEmailSend(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan email sends: %w", err)
+	}
+	return emailSends, nil
+}
+
+// FindByIdempotencyKey returns the most recent email send with the idempotency key created since
+// the given time that is neither failed, a duplicate, deferred, dropped, scheduled nor cancelled.
+func (r *PostgresRepository) FindByIdempotencyKey(ctx context.Context, key string, since time.Time) (*domain.EmailSend, error) {
+	emailSend, err := scanEmailSend(r.pool.QueryRow(ctx,
+		`SELECT `+emailSendColumns+`
+		 FROM notifications
+		 WHERE idempotency_key = $1 AND created_at >= $2 AND type = $3 AND status NOT IN ($4, $5, $6, $7, $8, $9)
+		 ORDER BY created_at DESC
+		 LIMIT 1`,
+		key, since, emailNotificationType, domain.EmailSendStatusFailed, domain.EmailSendStatusDuplicate,
+		domain.EmailSendStatusDeferred, domain.EmailSendStatusDropped, domain.EmailSendStatusScheduled,
+		domain.EmailSendStatusCancelled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrEmailSendNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email send by idempotency key: %w", err)
+	}
+	return emailSend, nil
+}
+
+func scanEmailSend(row pgx.Row) (*domain.EmailSend, error) {
+	var emailSend domain.EmailSend
+	err := row.Scan(
+		&emailSend.ID, &emailSend.Recipient, &emailSend.Subject, &emailSend.Body, &emailSend.TemplateName, &emailSend.Category,
+		&emailSend.IdempotencyKey, &emailSend.CorrelationID, &emailSend.DuplicateOf,
+		&emailSend.SentAt, &emailSend.CreatedAt, &emailSend.ErrorMessage, &emailSend.Status, &emailSend.Attempts, &emailSend.LastAttemptAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &emailSend, nil
+}
@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+const suppressionColumns = `recipient, reason, COALESCE(detail, ''), source, soft_bounces, suppressed_until, created_at, updated_at`
+
+// PostgresSuppressionRepository is a PostgreSQL implementation of the
+// domain.SuppressionRepository, storing suppressions in the email_suppressions table.
+type PostgresSuppressionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresSuppressionRepository creates a new PostgresSuppressionRepository.
+func NewPostgresSuppressionRepository(pool *pgxpool.Pool) *PostgresSuppressionRepository {
+	return &PostgresSuppressionRepository{pool: pool}
+}
+
+// Get returns the suppression of recipient, active or not.
+func (r *PostgresSuppressionRepository) Get(ctx context.Context, recipient string) (*domain.Suppression, error) {
+	suppression, err := scanSuppression(r.pool.QueryRow(ctx,
+		`SELECT `+suppressionColumns+` FROM email_suppressions WHERE recipient = $1`, domain.NormalizeRecipient(recipient)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email suppression: %w", err)
+	}
+	return suppression, nil
+}
+
+// Save inserts or replaces the suppression.
+func (r *PostgresSuppressionRepository) Save(ctx context.Context, suppression *domain.Suppression) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO email_suppressions (recipient, reason, detail, source, soft_bounces, suppressed_until, created_at, updated_at)
+		 VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8)
+		 ON CONFLICT (recipient) DO UPDATE
+		 SET reason = EXCLUDED.reason, detail = EXCLUDED.detail, source = EXCLUDED.source, soft_bounces = EXCLUDED.soft_bounces,
+		     suppressed_until = EXCLUDED.suppressed_until, updated_at = EXCLUDED.updated_at`,
+		domain.NormalizeRecipient(suppression.Recipient), suppression.Reason, suppression.Detail, suppression.Source,
+		suppression.SoftBounces, suppression.SuppressedUntil, suppression.CreatedAt, suppression.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save email suppression: %w", err)
+	}
+	return nil
+}
+
+// List returns the suppressions, most recently updated first.
+func (r *PostgresSuppressionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Suppression, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+suppressionColumns+`
+		 FROM email_suppressions
+		 ORDER BY updated_at DESC, recipient
+		 LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email suppressions: %w", err)
+	}
+	suppressions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Suppression, error) {
+		return scanSuppression(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan email suppressions: %w", err)
+	}
+	return suppressions, nil
+}
+
+// Delete removes the suppression of recipient.
+func (r *PostgresSuppressionRepository) Delete(ctx context.Context, recipient string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM email_suppressions WHERE recipient = $1`, domain.NormalizeRecipient(recipient))
+	if err != nil {
+		return fmt.Errorf("failed to delete email suppression: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSuppressionNotFound
+	}
+	return nil
+}
+
+func scanSuppression(row pgx.Row) (*domain.Suppression, error) {
+	var suppression domain.Suppression
+	err := row.Scan(
+		&suppression.Recipient, &suppression.Reason, &suppression.Detail, &suppression.Source, &suppression.SoftBounces,
+		&suppression.SuppressedUntil, &suppression.CreatedAt, &suppression.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &suppression, nil
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// deferredQueueKey is the sorted set of the IDs of the deferred email requests, scored by the time
+// in milliseconds they are due, and deferredRequestsKey the hash of the requests by ID. Requests
+// are identified by their idempotency key, so that they can be cancelled by it.
+const (
+	deferredQueueKey    = "notification:email:deferred"
+	deferredRequestsKey = "notification:email:deferred:requests"
+)
+
+// deferScript holds the request ARGV[3] with ID ARGV[1] until ARGV[2], replacing the request held
+// with that ID, if any.
+var deferScript = redis.NewScript(`
+redis.call("HSET", KEYS[2], ARGV[1], ARGV[3])
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+return 1
+`)
+
+// popDueScript removes and returns the requests of up to ARGV[2] IDs of KEYS[1] scored at most
+// ARGV[1], so that each due request is popped by a single worker replica.
+var popDueScript = redis.NewScript(`
+local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+local requests = {}
+for _, id in ipairs(ids) do
+	local request = redis.call("HGET", KEYS[2], id)
+	if request then
+		redis.call("HDEL", KEYS[2], id)
+		table.insert(requests, request)
+	end
+end
+if #ids > 0 then
+	redis.call("ZREM", KEYS[1], unpack(ids))
+end
+return requests
+`)
+
+// cancelScript removes and returns the request with ID ARGV[1], or returns false when there is none.
+var cancelScript = redis.NewScript(`
+local request = redis.call("HGET", KEYS[2], ARGV[1])
+if request then
+	redis.call("HDEL", KEYS[2], ARGV[1])
+	redis.call("ZREM", KEYS[1], ARGV[1])
+end
+return request
+`)
+
+// RedisDeferredQueue is a Redis implementation of the domain.DeferredQueue, holding requests in a
+// sorted set and a hash so that they survive restarts of the notification worker.
+type RedisDeferredQueue struct {
+	client *redis.Client
+}
+
+// NewRedisDeferredQueue creates a new RedisDeferredQueue.
+func NewRedisDeferredQueue(client *redis.Client) *RedisDeferredQueue {
+	return &RedisDeferredQueue{client: client}
+}
+
+// Defer holds request until at. Requests without an idempotency key get a random ID, so they
+// cannot be cancelled.
+func (q *RedisDeferredQueue) Defer(ctx context.Context, request events.EmailSendRequest, at time.Time) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deferred email request: %w", err)
+	}
+	id := request.IdempotencyKey
+	if id == "" {
+		id = uuid.NewString()
+	}
+	keys := []string{deferredQueueKey, deferredRequestsKey}
+	if err := deferScript.Run(ctx, q.client, keys, id, at.UnixMilli(), data).Err(); err != nil {
+		return fmt.Errorf("failed to defer email request: %w", err)
+	}
+	return nil
+}
+
+// PopDue removes and returns up to limit requests due at now, the earliest first. Requests that
+// cannot be decoded are discarded and reported in the error, along with the decoded ones.
+func (q *RedisDeferredQueue) PopDue(ctx context.Context, now time.Time, limit int) ([]events.EmailSendRequest, error) {
+	keys := []string{deferredQueueKey, deferredRequestsKey}
+	data, err := popDueScript.Run(ctx, q.client, keys, strconv.FormatInt(now.UnixMilli(), 10), limit).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pop due email requests: %w", err)
+	}
+
+	requests := make([]events.EmailSendRequest, 0, len(data))
+	var errs []error
+	for _, item := range data {
+		var request events.EmailSendRequest
+		if err := json.Unmarshal([]byte(item), &request); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmarshal deferred email request: %w", err))
+			continue
+		}
+		requests = append(requests, request)
+	}
+	return requests, errors.Join(errs...)
+}
+
+// Cancel removes and returns the request held with idempotencyKey.
+func (q *RedisDeferredQueue) Cancel(ctx context.Context, idempotencyKey string) (events.EmailSendRequest, error) {
+	data, err := cancelScript.Run(ctx, q.client, []string{deferredQueueKey, deferredRequestsKey}, idempotencyKey).Text()
+	if errors.Is(err, redis.Nil) {
+		return events.EmailSendRequest{}, domain.ErrScheduledEmailNotFound
+	}
+	if err != nil {
+		return events.EmailSendRequest{}, fmt.Errorf("failed to cancel deferred email request: %w", err)
+	}
+	var request events.EmailSendRequest
+	if err := json.Unmarshal([]byte(data), &request); err != nil {
+		return events.EmailSendRequest{}, fmt.Errorf("failed to unmarshal deferred email request: %w", err)
+	}
+	return request, nil
+}
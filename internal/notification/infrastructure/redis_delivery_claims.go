@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// releaseClaimScript deletes the claim key if it is still held by the caller.
+var releaseClaimScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisDeliveryClaims is a Redis implementation of the domain.DeliveryClaims, holding each claim
+// in a key that expires with the deduplication window.
+type RedisDeliveryClaims struct {
+	client *redis.Client
+}
+
+// NewRedisDeliveryClaims creates a new RedisDeliveryClaims.
+func NewRedisDeliveryClaims(client *redis.Client) *RedisDeliveryClaims {
+	return &RedisDeliveryClaims{client: client}
+}
+
+// Claim claims key for emailSendID unless another email send holds it.
+func (c *RedisDeliveryClaims) Claim(ctx context.Context, key, emailSendID string, ttl time.Duration) (bool, string, error) {
+	claimKey := deliveryClaimKey(key)
+	claimed, err := c.client.SetNX(ctx, claimKey, emailSendID, ttl).Result()
+	if err != nil {
+		return false, "", fmt.Errorf("failed to claim idempotency key %s: %w", key, err)
+	}
+	if claimed {
+		return true, "", nil
+	}
+
+	claimedBy, err := c.client.Get(ctx, claimKey).Result()
+	if errors.Is(err, redis.Nil) {
+		// The claim expired or was released in between, so the key is free again
+		return c.Claim(ctx, key, emailSendID, ttl)
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get claim of idempotency key %s: %w", key, err)
+	}
+	return false, claimedBy, nil
+}
+
+// Release frees the claim of emailSendID on key.
+func (c *RedisDeliveryClaims) Release(ctx context.Context, key, emailSendID string) error {
+	if err := releaseClaimScript.Run(ctx, c.client, []string{deliveryClaimKey(key)}, emailSendID).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key %s: %w", key, err)
+	}
+	return nil
+}
+
+func deliveryClaimKey(key string) string {
+	return "notification:email:idempotency:" + key
+}
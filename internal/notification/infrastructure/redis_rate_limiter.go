@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// takeTokensScript takes a token from every bucket in KEYS, or from none of them when one is
+// empty, in which case it returns the milliseconds until all of them have a token. ARGV holds the
+// current time in milliseconds, then the capacity and refill period in milliseconds of each bucket.
+// Buckets are hashes of their tokens and the time they were last taken from, refilled
+// continuously at capacity tokens per period.
+var takeTokensScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens = {}
+local wait = 0
+for i, key in ipairs(KEYS) do
+	local capacity = tonumber(ARGV[i * 2])
+	local period = tonumber(ARGV[i * 2 + 1])
+	local state = redis.call("HMGET", key, "tokens", "at")
+	local available = tonumber(state[1]) or capacity
+	local at = tonumber(state[2]) or now
+	if now > at then
+		available = math.min(capacity, available + (now - at) * capacity / period)
+	end
+	tokens[i] = available
+	if available < 1 then
+		wait = math.max(wait, math.ceil((1 - available) * period / capacity))
+	end
+end
+if wait > 0 then
+	return wait
+end
+for i, key in ipairs(KEYS) do
+	redis.call("HSET", key, "tokens", tostring(tokens[i] - 1), "at", now)
+	redis.call("PEXPIRE", key, ARGV[i * 2 + 1])
+end
+return 0
+`)
+
+// RedisRateLimiter is a Redis implementation of the domain.RateLimiter, shared by every replica
+// of the notification worker.
+type RedisRateLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimiter creates a new RedisRateLimiter.
+func NewRedisRateLimiter(client *redis.Client) *RedisRateLimiter {
+	return &RedisRateLimiter{client: client}
+}
+
+// Take takes a token from every bucket, or returns how long until they all have one.
+func (l *RedisRateLimiter) Take(ctx context.Context, buckets []domain.RateLimitBucket) (time.Duration, error) {
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(buckets))
+	args := []any{time.Now().UnixMilli()}
+	for i, bucket := range buckets {
+		keys[i] = "notification:ratelimit:" + bucket.Key
+		args = append(args, bucket.Limit.Count, bucket.Limit.Period.Milliseconds())
+	}
+	wait, err := takeTokensScript.Run(ctx, l.client, keys, args...).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to take rate limit tokens: %w", err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// Email transports, selected with EMAIL_TRANSPORT.
+const (
+	EmailTransportSMTP   = "smtp"
+	EmailTransportHTTP   = "http"
+	EmailTransportFile   = "file"
+	EmailTransportMemory = "memory"
+)
+
+// NewSender creates the Sender of the email transport selected by cfg.
+func NewSender(cfg *config.Config) (domain.Sender, error) {
+	var sender domain.Sender
+	var err error
+	switch cfg.EmailTransport {
+	case EmailTransportSMTP:
+		sender, err = NewSMTPSender(cfg)
+	case EmailTransportHTTP:
+		sender, err = NewHTTPSender(cfg)
+	case EmailTransportFile:
+		sender, err = NewMaildirSender(cfg)
+	case EmailTransportMemory:
+		sender, err = NewMemorySender(cfg)
+	default:
+		err = fmt.Errorf("invalid EMAIL_TRANSPORT %q: must be smtp, http, file or memory", cfg.EmailTransport)
+	}
+	if err != nil {
+		// Not the typed nil pointer of the failed constructor
+		return nil, err
+	}
+	return sender, nil
+}
@@ -1,89 +1,205 @@
 package infrastructure
 
 import (
-	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"html/template"
+	"net"
 	"net/smtp"
-	"os"
-	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/jefersonprimer/chatear-backend/config"
 	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
 )
 
+// SMTP connection security modes.
+const (
+	// SMTPSecuritySTARTTLS upgrades the connection with STARTTLS, which the server must support.
+	SMTPSecuritySTARTTLS = "starttls"
+	// SMTPSecurityTLS connects with implicit TLS, usually on port 465.
+	SMTPSecurityTLS = "tls"
+	// SMTPSecurityNone sends in clear text, for local relays and development servers only.
+	SMTPSecurityNone = "none"
+)
+
+// SMTPSender sends rendered emails through an SMTP server. It keeps its connection open between
+// emails and reconnects when the server has closed it.
 type SMTPSender struct {
-	host          string
-	port          int
-	user          string
-	password      string
-	from          string
-	templates     map[string]*template.Template
+	host     string
+	addr     string
+	security string
+	username string
+	password string
+	messages *messageBuilder
+	timeout  time.Duration
+
+	// mu serializes the emails sent over client.
+	mu     sync.Mutex
+	conn   net.Conn
+	client *smtp.Client
 }
 
+// NewSMTPSender creates a new SMTPSender from the SMTP settings of cfg.
 func NewSMTPSender(cfg *config.Config) (*SMTPSender, error) {
-	templates := make(map[string]*template.Template)
-
-	// Load all templates from the templates directory
-	templateDir := "internal/notification/infrastructure/templates"
-	templateFiles := []string{"email.txt", "welcome.html", "magic_link.html"}
-
-	for _, templateFile := range templateFiles {
-		templatePath := filepath.Join(templateDir, templateFile)
-		if _, err := os.Stat(templatePath); err == nil {
-			t, err := template.ParseFiles(templatePath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse template %s: %w", templateFile, err)
-			}
-			templateName := templateFile[:len(templateFile)-len(filepath.Ext(templateFile))]
-			templates[templateName] = t
-		}
+	messages, err := newMessageBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SMTPHost == "" {
+		return nil, errors.New("SMTP_HOST environment variable not set")
+	}
+	switch cfg.SMTPSecurity {
+	case SMTPSecuritySTARTTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, fmt.Errorf("invalid SMTP_SECURITY %q: must be starttls, tls or none", cfg.SMTPSecurity)
 	}
 
 	return &SMTPSender{
-		host:          cfg.SMTPHost,
-		port:          cfg.SMTPPort,
-		user:          cfg.SMTPUser,
-		password:      cfg.SMTPPass,
-		from:          cfg.SMTPFrom,
-		templates:     templates,
-	},
-il
+		host:     cfg.SMTPHost,
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		security: cfg.SMTPSecurity,
+		username: cfg.SMTPUser,
+		password: cfg.SMTPPass,
+		messages: messages,
+		timeout:  cfg.EmailTimeout,
+	}, nil
 }
 
+// Send sends emailSend, which must have been rendered, as a MIME message. The whole exchange
+// with the server must finish within the timeout and before ctx is done.
 func (s *SMTPSender) Send(ctx context.Context, emailSend *domain.EmailSend) error {
-	auth := smtp.PlainAuth("", s.user, s.password, s.host)
-
-	var body bytes.Buffer
-
-	// Use template if specified, otherwise use the body directly
-	if emailSend.TemplateName != "" {
-		if template, exists := s.templates[emailSend.TemplateName]; exists {
-			if err := template.Execute(&body, emailSend); err != nil {
-				return fmt.Errorf("failed to execute template %s: %w", emailSend.TemplateName, err)
-			}
-		} else {
-			// Fallback to default template or plain body
-			body.WriteString(emailSend.Body)
+	message, err := s.messages.build(emailSend, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A reused connection may have been closed by the server while idle: reset it, and
+	// reconnect when that fails
+	if s.client != nil {
+		s.setDeadline(ctx)
+		if err := s.client.Reset(); err != nil {
+			s.closeConnection()
+		}
+	}
+	if s.client == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
 		}
+	}
+
+	s.setDeadline(ctx)
+	if err := s.deliver(emailSend.Recipient, message); err != nil {
+		// The session may be left in any state, so it is not reused
+		s.closeConnection()
+		return err
+	}
+	return nil
+}
+
+// Close ends the SMTP session, if one is open.
+func (s *SMTPSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Quit()
+	s.closeConnection()
+	return err
+}
+
+// connect opens a connection to the server, secures it and authenticates.
+func (s *SMTPSender) connect(ctx context.Context) error {
+	dialCtx := ctx
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if s.security == SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.host}}).DialContext(dialCtx, "tcp", s.addr)
 	} else {
-		body.WriteString(emailSend.Body)
+		conn, err = dialer.DialContext(dialCtx, "tcp", s.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", s.addr, err)
 	}
+	s.conn = conn
+	s.setDeadline(ctx)
 
-	// Determine content type based on template
-	contentType := "text/plain"
-	if emailSend.TemplateName != "" && filepath.Ext(emailSend.TemplateName) == ".html" {
-		contentType = "text/html"
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		s.conn = nil
+		return fmt.Errorf("failed to start SMTP session with %s: %w", s.addr, err)
 	}
+	s.client = client
 
-	msg := []byte("To: " + emailSend.Recipient + "\r\n" +
-		"Subject: " + emailSend.Subject + "\r\n" +
-		"Content-Type: " + contentType + "; charset=UTF-8\r\n" +
-		"\r\n" +
-		body.String() + "\r\n")
+	if s.security == SMTPSecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			s.closeConnection()
+			return fmt.Errorf("SMTP server %s does not support STARTTLS", s.addr)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			s.closeConnection()
+			return fmt.Errorf("failed to start TLS with SMTP server %s: %w", s.addr, err)
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			s.closeConnection()
+			return fmt.Errorf("failed to authenticate with SMTP server %s: %w", s.addr, err)
+		}
+	}
+	return nil
+}
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+// deliver sends message to recipient over the open session.
+func (s *SMTPSender) deliver(recipient string, message []byte) error {
+	if err := s.client.Mail(s.messages.from.Address); err != nil {
+		return err
+	}
+	if err := s.client.Rcpt(recipient); err != nil {
+		return err
+	}
+	writer, err := s.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
 
-	return smtp.SendMail(addr, auth, s.from, []string{emailSend.Recipient}, msg)
+// setDeadline bounds the next exchanges with the server by the timeout, if any, and ctx.
+func (s *SMTPSender) setDeadline(ctx context.Context) {
+	var deadline time.Time
+	if s.timeout > 0 {
+		deadline = time.Now().Add(s.timeout)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		deadline = ctxDeadline
+	}
+	s.conn.SetDeadline(deadline)
+}
+
+func (s *SMTPSender) closeConnection() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.client = nil
+	s.conn = nil
 }
@@ -0,0 +1,153 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/config"
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts SMTP sessions in clear text and records the messages it receives.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	connections []net.Conn
+	messages    []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.connections = append(server.connections, conn)
+			server.mu.Unlock()
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "DATA":
+			reply("354 Go ahead")
+			var message strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				message.WriteString(line)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, message.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// dropConnections closes the open sessions, like a server closing idle connections.
+func (s *fakeSMTPServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.connections {
+		conn.Close()
+	}
+}
+
+func (s *fakeSMTPServer) stats() (connections, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.connections), len(s.messages)
+}
+
+func newTestSMTPSender(t *testing.T, server *fakeSMTPServer) *SMTPSender {
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	sender, err := NewSMTPSender(&config.Config{
+		SMTPHost:     host,
+		SMTPPort:     portNumber,
+		SMTPFrom:     "Chatear <no-reply@chatear.app>",
+		SMTPSecurity: SMTPSecurityNone,
+		EmailTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { sender.Close() })
+	return sender
+}
+
+func TestSMTPSender_ReusesItsConnection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := newTestSMTPSender(t, server)
+	emailSend := &domain.EmailSend{ID: "1", Recipient: "user@example.com", Subject: "Hello", Body: "Hi"}
+
+	require.NoError(t, sender.Send(context.Background(), emailSend))
+	require.NoError(t, sender.Send(context.Background(), emailSend))
+	connections, messages := server.stats()
+	assert.Equal(t, 1, connections)
+	assert.Equal(t, 2, messages)
+
+	server.dropConnections()
+	require.NoError(t, sender.Send(context.Background(), emailSend), "a connection closed by the server is reopened")
+	connections, messages = server.stats()
+	assert.Equal(t, 2, connections)
+	assert.Equal(t, 3, messages)
+}
+
+func TestSMTPSender_RequiresSTARTTLSSupport(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := newTestSMTPSender(t, server)
+	sender.security = SMTPSecuritySTARTTLS
+
+	err := sender.Send(context.Background(), &domain.EmailSend{ID: "1", Recipient: "user@example.com", Subject: "Hello", Body: "Hi"})
+	assert.ErrorContains(t, err, "does not support STARTTLS")
+}
+
+func TestNewSMTPSender_ValidatesSecurity(t *testing.T) {
+	_, err := NewSMTPSender(&config.Config{SMTPHost: "smtp.example.com", SMTPFrom: "no-reply@chatear.app", SMTPSecurity: "ssl"})
+	assert.ErrorContains(t, err, "SMTP_SECURITY")
+}
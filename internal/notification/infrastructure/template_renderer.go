@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+)
+
+// templateFS holds the email templates. Every email has a .txt and a .html template under
+// emails/, defining its "content" and, in the .txt one, its "subject". They are laid out by the
+// "base" template of layouts/ and may use the partials/ of the same format. Their text comes
+// from the shared/i18n catalogs through the "t" function, so each template is parsed once per
+// locale.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// messageTemplate lays out the emails sent without a template from their subject and body.
+const messageTemplate = "message"
+
+// emailTemplateData creates the typed data of each email template.
+var emailTemplateData = map[string]func() any{
+	events.EmailTemplateVerification:      func() any { return &events.VerificationEmailData{} },
+	events.EmailTemplatePasswordReset:     func() any { return &events.PasswordResetEmailData{} },
+	events.EmailTemplateDeletionWarning:   func() any { return &events.DeletionWarningEmailData{} },
+	events.EmailTemplateMagicLink:         func() any { return &events.MagicLinkEmailData{} },
+	events.EmailTemplateWelcome:           func() any { return &events.WelcomeEmailData{} },
+	events.EmailTemplateLoginVerification: func() any { return &events.LoginVerificationEmailData{} },
+	events.EmailTemplateNewSignIn:         func() any { return &events.NewSignInEmailData{} },
+	events.EmailTemplateDeletionScheduled: func() any { return &events.DeletionScheduledEmailData{} },
+	events.EmailTemplateDeletionCancelled: func() any { return &events.DeletionCancelledEmailData{} },
+	events.EmailTemplateDataExportReady:   func() any { return &events.DataExportReadyEmailData{} },
+}
+
+// templateContext is what the templates are executed with.
+type templateContext struct {
+	Locale         string
+	Recipient      string
+	Subject        string
+	UnsubscribeURL string
+	Data           any
+}
+
+// messageData is the data of the message template.
+type messageData struct {
+	Subject string
+	Body    string
+}
+
+// buttonLink is the data of the button partial.
+type buttonLink struct {
+	URL   string
+	Label string
+}
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// TemplateRenderer renders emails from the templates embedded in the binary.
+type TemplateRenderer struct {
+	// templates holds the email templates by locale and name.
+	templates map[string]map[string]*emailTemplate
+}
+
+// NewTemplateRenderer parses the embedded email templates in every supported locale.
+func NewTemplateRenderer() (*TemplateRenderer, error) {
+	templates := make(map[string]map[string]*emailTemplate, len(i18n.Locales))
+	for _, locale := range i18n.Locales {
+		localeTemplates := make(map[string]*emailTemplate, len(emailTemplateData)+1)
+		for name := range emailTemplateData {
+			template, err := parseEmailTemplate(locale, name)
+			if err != nil {
+				return nil, err
+			}
+			localeTemplates[name] = template
+		}
+		template, err := parseEmailTemplate(locale, messageTemplate)
+		if err != nil {
+			return nil, err
+		}
+		localeTemplates[messageTemplate] = template
+		templates[locale] = localeTemplates
+	}
+
+	return &TemplateRenderer{templates: templates}, nil
+}
+
+// Render sets the subject and the plain-text and HTML bodies of emailSend in its locale, or in
+// the default locale when it is empty or unsupported, and sets its locale to the one rendered.
+func (r *TemplateRenderer) Render(emailSend *domain.EmailSend) error {
+	name := emailSend.TemplateName
+	var data any
+	if name == "" {
+		name = messageTemplate
+		data = messageData{Subject: emailSend.Subject, Body: emailSend.Body}
+	} else {
+		newData, ok := emailTemplateData[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownTemplate, name)
+		}
+		data = newData()
+		decoder := json.NewDecoder(bytes.NewReader(emailSend.TemplateData))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(data); err != nil {
+			return fmt.Errorf("invalid data for email template %s: %w", name, err)
+		}
+	}
+	locale := i18n.Resolve(emailSend.Locale)
+	template := r.templates[locale][name]
+
+	ctx := templateContext{Locale: locale, Recipient: emailSend.Recipient, UnsubscribeURL: emailSend.UnsubscribeURL, Data: data}
+	var subject, text, html bytes.Buffer
+	if err := template.text.ExecuteTemplate(&subject, "subject", ctx); err != nil {
+		return fmt.Errorf("failed to render subject of email template %s: %w", name, err)
+	}
+	ctx.Subject = strings.TrimSpace(subject.String())
+	if err := template.text.ExecuteTemplate(&text, "base", ctx); err != nil {
+		return fmt.Errorf("failed to render text of email template %s: %w", name, err)
+	}
+	if err := template.html.ExecuteTemplate(&html, "base", ctx); err != nil {
+		return fmt.Errorf("failed to render HTML of email template %s: %w", name, err)
+	}
+
+	emailSend.Locale = locale
+	emailSend.Subject = ctx.Subject
+	emailSend.Body = text.String()
+	emailSend.HTMLBody = html.String()
+	return nil
+}
+
+// parseEmailTemplate parses the text and HTML templates of an email in locale with their layout
+// and partials.
+func parseEmailTemplate(locale, name string) (*emailTemplate, error) {
+	funcs := localeFuncs(locale)
+	text, err := texttemplate.New(name).Funcs(funcs).
+		ParseFS(templateFS, "templates/layouts/*.txt", "templates/partials/*.txt", "templates/emails/"+name+".txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text of email template %s: %w", name, err)
+	}
+	html, err := htmltemplate.New(name).Funcs(funcs).Funcs(htmltemplate.FuncMap{
+		"link":       func(url, label string) buttonLink { return buttonLink{URL: url, Label: label} },
+		"paragraphs": paragraphs,
+	}).ParseFS(templateFS, "templates/layouts/*.html", "templates/partials/*.html", "templates/emails/"+name+".html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML of email template %s: %w", name, err)
+	}
+
+	for _, required := range []string{"base", "subject", "content"} {
+		if text.Lookup(required) == nil {
+			return nil, fmt.Errorf("text of email template %s does not define %q", name, required)
+		}
+	}
+	for _, required := range []string{"base", "content"} {
+		if html.Lookup(required) == nil {
+			return nil, fmt.Errorf("HTML of email template %s does not define %q", name, required)
+		}
+	}
+	return &emailTemplate{text: text, html: html}, nil
+}
+
+// localeFuncs returns the template functions that translate and format in locale.
+func localeFuncs(locale string) map[string]any {
+	return map[string]any{
+		"t":        func(key string, args ...any) string { return i18n.T(locale, key, args...) },
+		"date":     func(t time.Time) string { return i18n.FormatDate(locale, t) },
+		"datetime": func(t time.Time) string { return i18n.FormatDateTime(locale, t) },
+	}
+}
+
+// paragraphs renders a plain-text body as HTML paragraphs, keeping its line breaks.
+func paragraphs(body string) htmltemplate.HTML {
+	var html strings.Builder
+	for _, paragraph := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for i, line := range lines {
+			lines[i] = htmltemplate.HTMLEscapeString(line)
+		}
+		html.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return htmltemplate.HTML(html.String())
+}
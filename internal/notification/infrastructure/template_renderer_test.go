@@ -0,0 +1,144 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTemplatedEmailSend(t *testing.T, locale, templateName string, data any) *domain.EmailSend {
+	request, err := events.NewTemplatedEmailSendRequest(context.Background(), "", "user@example.com", locale, templateName, data)
+	require.NoError(t, err)
+	return &domain.EmailSend{Recipient: request.Recipient, Locale: request.Locale, TemplateName: request.TemplateName, TemplateData: request.TemplateData}
+}
+
+func TestTemplateRenderer_RendersEveryTemplate(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+	signIn := events.SignInDetails{Device: "Chrome on Linux", IPAddress: "203.0.113.7"}
+
+	tests := []struct {
+		template string
+		data     any
+		subject  string
+		link     string
+	}{
+		{events.EmailTemplateVerification, events.VerificationEmailData{Name: "Ana", VerificationLink: "https://chatear.app/verify-email?token=abc", ExpiresInMinutes: 15}, "Verify your email", "https://chatear.app/verify-email?token=abc"},
+		{events.EmailTemplatePasswordReset, events.PasswordResetEmailData{Name: "Ana", ResetLink: "https://chatear.app/reset-password?token=abc", ExpiresInMinutes: 15}, "Reset your password", "https://chatear.app/reset-password?token=abc"},
+		{events.EmailTemplateDeletionWarning, events.DeletionWarningEmailData{Name: "Ana", DeletionDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), CancelLink: "https://chatear.app/cancel-account-deletion?token=abc", SignInLink: "https://chatear.app"}, "Your account will be deleted soon", "https://chatear.app/cancel-account-deletion?token=abc"},
+		{events.EmailTemplateMagicLink, events.MagicLinkEmailData{MagicLink: "https://chatear.app/magic?token=abc", ExpiresInMinutes: 10}, "Your sign-in link", "https://chatear.app/magic?token=abc"},
+		{events.EmailTemplateWelcome, events.WelcomeEmailData{Name: "Ana", AppURL: "https://chatear.app"}, "Welcome to Chatear!", "https://chatear.app"},
+		{events.EmailTemplateLoginVerification, events.LoginVerificationEmailData{SignIn: signIn, ConfirmLink: "https://chatear.app/verify-login?token=abc"}, "Confirm your sign-in", "https://chatear.app/verify-login?token=abc"},
+		{events.EmailTemplateNewSignIn, events.NewSignInEmailData{SignIn: signIn, SignedInAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC), RevokeSessionsLink: "https://chatear.app/security/sessions"}, "New sign-in to your account", "https://chatear.app/security/sessions"},
+		{events.EmailTemplateDeletionScheduled, events.DeletionScheduledEmailData{Name: "Ana", DeletionDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), CancelLink: "https://chatear.app/cancel-account-deletion?token=abc"}, "Your account is scheduled for deletion", "https://chatear.app/cancel-account-deletion?token=abc"},
+		{events.EmailTemplateDataExportReady, events.DataExportReadyEmailData{Name: "Ana", DownloadLink: "https://chatear.app/exports/abc", ExpiresAt: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)}, "Your data export is ready", "https://chatear.app/exports/abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			emailSend := newTemplatedEmailSend(t, "en", tt.template, tt.data)
+			require.NoError(t, renderer.Render(emailSend))
+
+			assert.Equal(t, tt.subject, emailSend.Subject)
+			assert.Contains(t, emailSend.Body, tt.link)
+			assert.Contains(t, emailSend.Body, "This email was sent to user@example.com")
+			assert.NotContains(t, emailSend.Body, "<")
+			assert.Contains(t, emailSend.HTMLBody, `href="`+tt.link+`"`)
+			assert.Contains(t, emailSend.HTMLBody, "<title>"+tt.subject+"</title>")
+		})
+	}
+}
+
+func TestTemplateRenderer_DeletionWarningWithoutCancelLink(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+
+	emailSend := newTemplatedEmailSend(t, "en", events.EmailTemplateDeletionWarning, events.DeletionWarningEmailData{
+		Name:         "Ana",
+		DeletionDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		SignInLink:   "https://chatear.app",
+	})
+	require.NoError(t, renderer.Render(emailSend))
+
+	assert.Contains(t, emailSend.Body, "March 1, 2026")
+	assert.Contains(t, emailSend.Body, "sign in to keep your account.\n\nhttps://chatear.app")
+	assert.Contains(t, emailSend.HTMLBody, `href="https://chatear.app"`)
+}
+
+func TestTemplateRenderer_RendersInTheRecipientLocale(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+	data := events.DeletionScheduledEmailData{
+		Name:         "Ana",
+		DeletionDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		CancelLink:   "https://chatear.app/cancel-account-deletion?token=abc",
+	}
+
+	for _, locale := range []string{"pt-BR", "pt", ""} {
+		emailSend := newTemplatedEmailSend(t, locale, events.EmailTemplateDeletionScheduled, data)
+		require.NoError(t, renderer.Render(emailSend))
+
+		assert.Equal(t, "A exclusão da sua conta foi agendada", emailSend.Subject, locale)
+		assert.Contains(t, emailSend.Body, "Olá, Ana,", locale)
+		assert.Contains(t, emailSend.Body, "1 de março de 2026", locale)
+		assert.Contains(t, emailSend.Body, "Este e-mail foi enviado para user@example.com", locale)
+		assert.Contains(t, emailSend.HTMLBody, `<html lang="pt-BR">`, locale)
+	}
+
+	emailSend := newTemplatedEmailSend(t, "en-US", events.EmailTemplateNewSignIn, events.NewSignInEmailData{
+		SignIn:     events.SignInDetails{Device: "Safari on iPhone", IPAddress: "203.0.113.7"},
+		SignedInAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+	})
+	require.NoError(t, renderer.Render(emailSend))
+	assert.Contains(t, emailSend.Body, "Location: Unknown")
+	assert.Contains(t, emailSend.HTMLBody, `<html lang="en">`)
+}
+
+func TestTemplateRenderer_UnsubscribeFooter(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+
+	emailSend := newTemplatedEmailSend(t, "en", events.EmailTemplateWelcome, events.WelcomeEmailData{Name: "Ana", AppURL: "https://chatear.app"})
+	emailSend.UnsubscribeURL = "https://chatear.app/unsubscribe?token=abc"
+	require.NoError(t, renderer.Render(emailSend))
+
+	assert.Contains(t, emailSend.Body, "Unsubscribe: https://chatear.app/unsubscribe?token=abc")
+	assert.NotContains(t, emailSend.Body, "If you didn't request this email")
+	assert.Contains(t, emailSend.HTMLBody, `<a href="https://chatear.app/unsubscribe?token=abc">Unsubscribe</a>`)
+}
+
+func TestTemplateRenderer_LaysOutEmailsWithoutTemplate(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+
+	emailSend := &domain.EmailSend{Recipient: "user@example.com", Subject: "New sign-in", Body: "Hi <Ana>,\n\nA new device signed in.\nWas it you?"}
+	require.NoError(t, renderer.Render(emailSend))
+
+	assert.Equal(t, "New sign-in", emailSend.Subject)
+	assert.Contains(t, emailSend.Body, "Hi <Ana>,\n\nA new device signed in.")
+	assert.Contains(t, emailSend.HTMLBody, "<p>Hi &lt;Ana&gt;,</p>")
+	assert.Contains(t, emailSend.HTMLBody, "<p>A new device signed in.<br>\nWas it you?</p>")
+}
+
+func TestTemplateRenderer_RejectsUnknownTemplatesAndInvalidData(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	require.NoError(t, err)
+
+	err = renderer.Render(&domain.EmailSend{Recipient: "user@example.com", TemplateName: "newsletter"})
+	assert.ErrorIs(t, err, domain.ErrUnknownTemplate)
+
+	err = renderer.Render(&domain.EmailSend{Recipient: "user@example.com", TemplateName: events.EmailTemplateWelcome})
+	assert.Error(t, err, "templated emails require their data")
+
+	err = renderer.Render(&domain.EmailSend{
+		Recipient:    "user@example.com",
+		TemplateName: events.EmailTemplateWelcome,
+		TemplateData: json.RawMessage(`{"name":"Ana","reset_link":"https://chatear.app"}`),
+	})
+	assert.Error(t, err, "data of another template is rejected")
+}
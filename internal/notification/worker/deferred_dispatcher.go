@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+const (
+	// deferredDispatchInterval is how often the deferred queue is checked for due requests.
+	deferredDispatchInterval = time.Second
+	// deferredDispatchBatchSize is the number of due requests popped at once.
+	deferredDispatchBatchSize = 100
+)
+
+// DeferredEmailDispatcher publishes the email requests held in the deferred queue, scheduled or
+// rate limited, on email.send again once they are due.
+type DeferredEmailDispatcher struct {
+	conn  *nats.Conn
+	queue domain.DeferredQueue
+}
+
+// NewDeferredEmailDispatcher creates a new DeferredEmailDispatcher.
+func NewDeferredEmailDispatcher(conn *nats.Conn, queue domain.DeferredQueue) *DeferredEmailDispatcher {
+	return &DeferredEmailDispatcher{conn: conn, queue: queue}
+}
+
+// Run publishes the due requests until ctx is done.
+func (d *DeferredEmailDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(deferredDispatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+		}
+	}
+}
+
+func (d *DeferredEmailDispatcher) dispatchDue(ctx context.Context) {
+	for {
+		requests, err := d.queue.PopDue(ctx, time.Now(), deferredDispatchBatchSize)
+		if err != nil {
+			log.Printf("Error popping deferred email requests: %v", err)
+		}
+		for _, request := range requests {
+			data, err := json.Marshal(request)
+			if err == nil {
+				err = d.conn.Publish("email.send", data)
+			}
+			if err != nil {
+				log.Printf("Error publishing deferred email to %s: %v", request.Recipient, err)
+				// Held again, so that the request is not lost
+				if err := d.queue.Defer(ctx, request, time.Now().Add(deferredDispatchInterval)); err != nil {
+					log.Printf("Error deferring email to %s again: %v", request.Recipient, err)
+				}
+			}
+		}
+		if len(requests) < deferredDispatchBatchSize {
+			return
+		}
+	}
+}
@@ -1,113 +1,93 @@
 package worker
 
 import (
-
 	"context"
-
 	"encoding/json"
-
+	"errors"
+	"fmt"
 	"log"
 
-
-
 	"github.com/nats-io/nats.go"
 
 	"github.com/jefersonprimer/chatear-backend/internal/notification/application"
-
+	"github.com/jefersonprimer/chatear-backend/internal/notification/domain"
 	"github.com/jefersonprimer/chatear-backend/shared/events"
-
 )
 
-
-
 type NatsEmailConsumer struct {
-
 	conn        *nats.Conn
-
 	emailSender *application.EmailSender
-
 }
 
-
-
 func NewNatsEmailConsumer(conn *nats.Conn, emailSender *application.EmailSender) (*NatsEmailConsumer, error) {
-
-
-
 	return &NatsEmailConsumer{
-
-
-
 		conn:        conn,
-
-
-
 		emailSender: emailSender,
-
-
-
 	}, nil
-
-
-
 }
 
-
-
-func (c *NatsEmailConsumer) Start(ctx context.Context) {
-
+// Start subscribes to email.send and sends each requested email with ctx, and to email.cancel
+// and cancels each scheduled email. It returns once subscribed; draining the connection waits for
+// the emails being sent.
+func (c *NatsEmailConsumer) Start(ctx context.Context) error {
 	_, err := c.conn.Subscribe("email.send", func(msg *nats.Msg) {
-
 		c.handleEmailSend(ctx, msg)
-
 	})
-
 	if err != nil {
-
-		log.Fatalf("Error subscribing to email.send subject: %v", err)
-
+		return fmt.Errorf("error subscribing to email.send subject: %w", err)
+	}
+	_, err = c.conn.Subscribe("email.cancel", func(msg *nats.Msg) {
+		c.handleEmailCancel(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("error subscribing to email.cancel subject: %w", err)
 	}
-
-
 
 	log.Println("NATS email consumer started")
-
-	<-ctx.Done()
-
+	return nil
 }
 
-
-
 func (c *NatsEmailConsumer) handleEmailSend(ctx context.Context, msg *nats.Msg) {
-
 	var request events.EmailSendRequest
-
 	if err := json.Unmarshal(msg.Data, &request); err != nil {
-
 		log.Printf("Error unmarshaling email send request: %v", err)
-
 		return
-
 	}
 
+	log.Printf("Processing email send request for recipient: %s (idempotency key %q, correlation ID %q)", request.Recipient, request.IdempotencyKey, request.CorrelationID)
 
-
-	log.Printf("Processing email send request for recipient: %s", request.Recipient)
-
-
-
-	emailSend, err := c.emailSender.Send(ctx, request.Recipient, request.Subject, request.Body, request.TemplateName)
-
+	emailSend, err := c.emailSender.Send(ctx, request)
 	if err != nil {
-
 		log.Printf("Error sending email to %s: %v", request.Recipient, err)
-
 		return
-
 	}
 
-
-
+	switch emailSend.Status {
+	case domain.EmailSendStatusSkipped, domain.EmailSendStatusDuplicate, domain.EmailSendStatusDropped, domain.EmailSendStatusSuppressed:
+		log.Printf("Email to %s skipped with ID %s: %s", request.Recipient, emailSend.ID, emailSend.ErrorMessage)
+		return
+	case domain.EmailSendStatusDeferred, domain.EmailSendStatusScheduled:
+		log.Printf("Email to %s %s with ID %s: %s", request.Recipient, emailSend.Status, emailSend.ID, emailSend.ErrorMessage)
+		return
+	}
 	log.Printf("Email sent successfully to %s with ID: %s", request.Recipient, emailSend.ID)
+}
 
+func (c *NatsEmailConsumer) handleEmailCancel(ctx context.Context, msg *nats.Msg) {
+	var request events.EmailCancelRequest
+	if err := json.Unmarshal(msg.Data, &request); err != nil {
+		log.Printf("Error unmarshaling email cancel request: %v", err)
+		return
+	}
+
+	emailSend, err := c.emailSender.Cancel(ctx, request.IdempotencyKey)
+	if errors.Is(err, domain.ErrScheduledEmailNotFound) {
+		log.Printf("No scheduled email to cancel with idempotency key %q (correlation ID %q)", request.IdempotencyKey, request.CorrelationID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error cancelling email with idempotency key %q: %v", request.IdempotencyKey, err)
+		return
+	}
+	log.Printf("Email to %s cancelled with ID %s (idempotency key %q, correlation ID %q)", emailSend.Recipient, emailSend.ID, request.IdempotencyKey, request.CorrelationID)
 }
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+)
+
+// JobSummary is a registered job and its most recent run.
+type JobSummary struct {
+	Definition *domain.JobDefinition
+	LastRun    *domain.JobRun
+}
+
+// JobService exposes the registered jobs and their run history to administrators.
+type JobService struct {
+	JobDefinitionRepository domain.JobDefinitionRepository
+	JobRunRepository        domain.JobRunRepository
+	JobTriggerPublisher     domain.JobTriggerPublisher
+}
+
+// NewJobService creates a new JobService.
+func NewJobService(jobDefinitionRepository domain.JobDefinitionRepository, jobRunRepository domain.JobRunRepository, jobTriggerPublisher domain.JobTriggerPublisher) *JobService {
+	return &JobService{
+		JobDefinitionRepository: jobDefinitionRepository,
+		JobRunRepository:        jobRunRepository,
+		JobTriggerPublisher:     jobTriggerPublisher,
+	}
+}
+
+// ListJobs returns every registered job with its most recent run.
+func (s *JobService) ListJobs(ctx context.Context) ([]*JobSummary, error) {
+	definitions, err := s.JobDefinitionRepository.ListJobDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*JobSummary, len(definitions))
+	for i, definition := range definitions {
+		runs, err := s.JobRunRepository.ListJobRuns(ctx, definition.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+		summaries[i] = &JobSummary{Definition: definition}
+		if len(runs) > 0 {
+			summaries[i].LastRun = runs[0]
+		}
+	}
+	return summaries, nil
+}
+
+// ListJobRuns returns the most recent runs of a job, newest first.
+func (s *JobService) ListJobRuns(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	if _, err := s.JobDefinitionRepository.GetJobDefinition(ctx, jobName); err != nil {
+		return nil, err
+	}
+	return s.JobRunRepository.ListJobRuns(ctx, jobName, limit)
+}
+
+// TriggerJob queues a manual run of a job and notifies the schedulers running it.
+func (s *JobService) TriggerJob(ctx context.Context, jobName string, triggeredBy uuid.UUID) (*domain.JobRun, error) {
+	if _, err := s.JobDefinitionRepository.GetJobDefinition(ctx, jobName); err != nil {
+		return nil, err
+	}
+
+	run := domain.NewManualJobRun(jobName, triggeredBy)
+	if _, err := s.JobRunRepository.CreateJobRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	if err := s.JobTriggerPublisher.PublishJobTrigger(ctx, run); err != nil {
+		// Do not leave a run queued that no scheduler will pick up
+		if skipErr := run.Skip(time.Now(), "schedulers could not be notified"); skipErr == nil {
+			if updateErr := s.JobRunRepository.UpdateJobRun(ctx, run, domain.JobRunStatusQueued); updateErr != nil {
+				fmt.Printf("Warning: failed to skip run %s of job %s: %v\n", run.ID, jobName, updateErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to trigger job %s: %w", jobName, err)
+	}
+	return run, nil
+}
@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+)
+
+// abandonedRunGrace is how long past its deadline a run may take to record its result before it
+// is considered abandoned by a crashed scheduler.
+const abandonedRunGrace = 5 * time.Minute
+
+// defaultShutdownTimeout is how long runs in progress may take to finish once the scheduler stops.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Scheduler runs registered jobs on their cron schedule and records every run in the job run
+// history. Several instances may run the same jobs: each activation is claimed by a single
+// instance, and jobs with ConcurrencyForbid never overlap across instances.
+type Scheduler struct {
+	JobDefinitionRepository domain.JobDefinitionRepository
+	JobRunRepository        domain.JobRunRepository
+	JobLocker               domain.JobLocker
+	// ShutdownTimeout is how long runs in progress may take to finish once Run's context is done,
+	// before their context is cancelled.
+	ShutdownTimeout time.Duration
+
+	jobs       map[string]*domain.Job
+	names      []string
+	runs       sync.WaitGroup
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	now        func() time.Time
+}
+
+// NewScheduler creates a new Scheduler without jobs.
+func NewScheduler(jobDefinitionRepository domain.JobDefinitionRepository, jobRunRepository domain.JobRunRepository, jobLocker domain.JobLocker) *Scheduler {
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	return &Scheduler{
+		JobDefinitionRepository: jobDefinitionRepository,
+		JobRunRepository:        jobRunRepository,
+		JobLocker:               jobLocker,
+		ShutdownTimeout:         defaultShutdownTimeout,
+		jobs:                    make(map[string]*domain.Job),
+		runCtx:                  runCtx,
+		cancelRuns:              cancelRuns,
+		now:                     time.Now,
+	}
+}
+
+// Register adds a job to the scheduler. It must be called before Run.
+func (s *Scheduler) Register(job *domain.Job) error {
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.jobs[job.Name] = job
+	s.names = append(s.names, job.Name)
+	return nil
+}
+
+// Jobs returns the registered jobs in registration order.
+func (s *Scheduler) Jobs() []*domain.Job {
+	jobs := make([]*domain.Job, len(s.names))
+	for i, name := range s.names {
+		jobs[i] = s.jobs[name]
+	}
+	return jobs
+}
+
+// Run schedules the registered jobs until ctx is done, then waits up to ShutdownTimeout for the
+// runs in progress to finish. Runs still going after that are cancelled and their result recorded.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.jobs) == 0 {
+		return errors.New("no jobs registered")
+	}
+
+	var loops sync.WaitGroup
+	for _, job := range s.Jobs() {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	loops.Wait()
+
+	defer s.cancelRuns()
+	if !waitTimeout(&s.runs, s.ShutdownTimeout) {
+		fmt.Printf("Warning: cancelling job runs still in progress after %s\n", s.ShutdownTimeout)
+		s.cancelRuns()
+		s.runs.Wait()
+	}
+	return nil
+}
+
+// RunQueued starts a queued run, typically a manual run created by JobService.TriggerJob, in the background.
+func (s *Scheduler) RunQueued(ctx context.Context, runID uuid.UUID) error {
+	// The run stays queued for another instance once this one is shutting down
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	run, err := s.JobRunRepository.GetJobRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	job, ok := s.jobs[run.JobName]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrJobNotFound, run.JobName)
+	}
+	if run.Status != domain.JobRunStatusQueued {
+		return fmt.Errorf("%w: run %s is %s", domain.ErrInvalidJobRunTransition, run.ID, run.Status)
+	}
+
+	s.start(job, run)
+	return nil
+}
+
+// loop waits for each activation of job and dispatches it.
+func (s *Scheduler) loop(ctx context.Context, job *domain.Job) {
+	for {
+		next := job.Schedule.Next(s.now())
+		if next.IsZero() {
+			fmt.Printf("Warning: job %s has no upcoming activation and will not run\n", job.Name)
+			return
+		}
+		s.saveDefinition(ctx, job, next)
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.dispatch(ctx, job, domain.NewScheduledJobRun(job.Name, next))
+	}
+}
+
+// dispatch claims a scheduled run and starts it, unless another instance claimed it first.
+func (s *Scheduler) dispatch(ctx context.Context, job *domain.Job, run *domain.JobRun) {
+	if _, err := s.JobRunRepository.FailAbandonedJobRuns(ctx, job.Name, s.now().Add(-abandonedRunGrace)); err != nil {
+		fmt.Printf("Warning: failed to fail abandoned runs of job %s: %v\n", job.Name, err)
+	}
+
+	created, err := s.JobRunRepository.CreateJobRun(ctx, run)
+	if err != nil {
+		fmt.Printf("Warning: failed to record run of job %s: %v\n", job.Name, err)
+		return
+	}
+	if !created {
+		return
+	}
+	s.start(job, run)
+}
+
+// start executes a recorded run in the background. Runs are not tied to the context of the
+// caller, so that they can finish when the scheduler stops.
+func (s *Scheduler) start(job *domain.Job, run *domain.JobRun) {
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		if err := s.execute(s.runCtx, job, run); err != nil {
+			fmt.Printf("Warning: run %s of job %s failed: %v\n", run.ID, job.Name, err)
+		}
+	}()
+}
+
+// execute applies the concurrency policy of job and runs it.
+func (s *Scheduler) execute(ctx context.Context, job *domain.Job, run *domain.JobRun) error {
+	if job.Concurrency == domain.ConcurrencyAllow {
+		return s.runJob(ctx, job, run, nil)
+	}
+
+	err := s.JobLocker.TryLock(ctx, job.Name, func(ctx context.Context, fencingToken int64) error {
+		return s.runJob(ctx, job, run, &fencingToken)
+	})
+	if errors.Is(err, domain.ErrJobRunning) {
+		if err := run.Skip(s.now(), "a previous run is still in progress"); err != nil {
+			return err
+		}
+		return s.JobRunRepository.UpdateJobRun(ctx, run, domain.JobRunStatusQueued)
+	}
+	return err
+}
+
+// runJob starts run, calls the job within its timeout and records the result.
+func (s *Scheduler) runJob(ctx context.Context, job *domain.Job, run *domain.JobRun, fencingToken *int64) error {
+	if err := run.Start(s.now(), job.Timeout, fencingToken); err != nil {
+		return err
+	}
+	if err := s.JobRunRepository.UpdateJobRun(ctx, run, domain.JobRunStatusQueued); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	jobErr := callJob(runCtx, job.Run)
+	cancel()
+
+	if err := run.Finish(s.now(), jobErr); err != nil {
+		return err
+	}
+	// The result is saved even when ctx was cancelled on shutdown
+	saveCtx, cancelSave := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancelSave()
+	if err := s.JobRunRepository.UpdateJobRun(saveCtx, run, domain.JobRunStatusRunning); err != nil {
+		return errors.Join(jobErr, err)
+	}
+	return jobErr
+}
+
+// saveDefinition records the job and its next activation for administrators.
+func (s *Scheduler) saveDefinition(ctx context.Context, job *domain.Job, next time.Time) {
+	definition := job.JobDefinition
+	definition.NextRunAt = &next
+	definition.UpdatedAt = s.now()
+	if err := s.JobDefinitionRepository.SaveJobDefinition(ctx, &definition); err != nil {
+		fmt.Printf("Warning: failed to save definition of job %s: %v\n", job.Name, err)
+	}
+}
+
+// waitTimeout waits for wg for at most timeout and reports whether it completed.
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// callJob calls run, turning a panic into an error so that the run is still recorded.
+func callJob(ctx context.Context, run domain.JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx)
+}
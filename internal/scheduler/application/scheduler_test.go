@@ -0,0 +1,341 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobRunRepository is an in-memory implementation of domain.JobRunRepository for testing
+type fakeJobRunRepository struct {
+	mu   sync.Mutex
+	runs map[uuid.UUID]domain.JobRun
+}
+
+func newFakeJobRunRepository() *fakeJobRunRepository {
+	return &fakeJobRunRepository{runs: make(map[uuid.UUID]domain.JobRun)}
+}
+
+func (r *fakeJobRunRepository) CreateJobRun(ctx context.Context, run *domain.JobRun) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.runs {
+		if run.ScheduledAt != nil && existing.JobName == run.JobName && existing.ScheduledAt != nil && existing.ScheduledAt.Equal(*run.ScheduledAt) {
+			return false, nil
+		}
+	}
+	r.runs[run.ID] = *run
+	return true, nil
+}
+
+func (r *fakeJobRunRepository) GetJobRun(ctx context.Context, id uuid.UUID) (*domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, domain.ErrJobRunNotFound
+	}
+	return &run, nil
+}
+
+func (r *fakeJobRunRepository) UpdateJobRun(ctx context.Context, run *domain.JobRun, expectedStatus domain.JobRunStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.runs[run.ID].Status != expectedStatus {
+		return domain.ErrInvalidJobRunTransition
+	}
+	r.runs[run.ID] = *run
+	return nil
+}
+
+func (r *fakeJobRunRepository) ListJobRuns(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []*domain.JobRun
+	for _, run := range r.runs {
+		if run.JobName == jobName && len(runs) < limit {
+			runs = append(runs, &run)
+		}
+	}
+	return runs, nil
+}
+
+func (r *fakeJobRunRepository) FailAbandonedJobRuns(ctx context.Context, jobName string, deadlineBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var failed int64
+	for id, run := range r.runs {
+		if run.JobName == jobName && run.Status == domain.JobRunStatusRunning && run.DeadlineAt.Before(deadlineBefore) {
+			run.Status = domain.JobRunStatusFailed
+			r.runs[id] = run
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// fakeJobDefinitionRepository is an in-memory implementation of domain.JobDefinitionRepository for testing
+type fakeJobDefinitionRepository struct {
+	mu          sync.Mutex
+	definitions map[string]domain.JobDefinition
+}
+
+func newFakeJobDefinitionRepository() *fakeJobDefinitionRepository {
+	return &fakeJobDefinitionRepository{definitions: make(map[string]domain.JobDefinition)}
+}
+
+func (r *fakeJobDefinitionRepository) SaveJobDefinition(ctx context.Context, definition *domain.JobDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.definitions[definition.Name] = *definition
+	return nil
+}
+
+func (r *fakeJobDefinitionRepository) ListJobDefinitions(ctx context.Context) ([]*domain.JobDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var definitions []*domain.JobDefinition
+	for _, definition := range r.definitions {
+		definitions = append(definitions, &definition)
+	}
+	return definitions, nil
+}
+
+func (r *fakeJobDefinitionRepository) GetJobDefinition(ctx context.Context, name string) (*domain.JobDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	definition, ok := r.definitions[name]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	return &definition, nil
+}
+
+// fakeJobLocker is a domain.JobLocker that can pretend the lock is held by another instance
+type fakeJobLocker struct {
+	held bool
+}
+
+func (l *fakeJobLocker) TryLock(ctx context.Context, jobName string, fn func(ctx context.Context, fencingToken int64) error) error {
+	if l.held {
+		return domain.ErrJobRunning
+	}
+	return fn(ctx, 42)
+}
+
+// fakeJobTriggerPublisher records the published runs for testing
+type fakeJobTriggerPublisher struct {
+	published []*domain.JobRun
+	err       error
+}
+
+func (p *fakeJobTriggerPublisher) PublishJobTrigger(ctx context.Context, run *domain.JobRun) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, run)
+	return nil
+}
+
+func newTestScheduler(t *testing.T, locker *fakeJobLocker, run domain.JobFunc) (*Scheduler, *fakeJobRunRepository, *domain.Job) {
+	t.Helper()
+	runs := newFakeJobRunRepository()
+	scheduler := NewScheduler(newFakeJobDefinitionRepository(), runs, locker)
+	job, err := domain.NewJob("cleanup", "@hourly", time.Minute, domain.ConcurrencyForbid, run)
+	require.NoError(t, err)
+	require.NoError(t, scheduler.Register(job))
+	return scheduler, runs, job
+}
+
+func TestScheduler_DispatchRecordsSucceededRun(t *testing.T) {
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error { return nil })
+	run := domain.NewScheduledJobRun(job.Name, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	scheduler.dispatch(context.Background(), job, run)
+	scheduler.runs.Wait()
+
+	stored, err := runs.GetJobRun(context.Background(), run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunStatusSucceeded, stored.Status)
+	assert.NotNil(t, stored.StartedAt)
+	assert.NotNil(t, stored.FinishedAt)
+	require.NotNil(t, stored.FencingToken)
+	assert.Equal(t, int64(42), *stored.FencingToken)
+}
+
+func TestScheduler_DispatchRunsEachActivationOnce(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	})
+	scheduledAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Two instances reach the same activation
+	scheduler.dispatch(context.Background(), job, domain.NewScheduledJobRun(job.Name, scheduledAt))
+	scheduler.dispatch(context.Background(), job, domain.NewScheduledJobRun(job.Name, scheduledAt))
+	scheduler.runs.Wait()
+
+	assert.Equal(t, 1, calls)
+	assert.Len(t, runs.runs, 1)
+}
+
+func TestScheduler_SkipsRunWhilePreviousRunHoldsLock(t *testing.T) {
+	called := false
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{held: true}, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	run := domain.NewScheduledJobRun(job.Name, time.Now())
+
+	scheduler.dispatch(context.Background(), job, run)
+	scheduler.runs.Wait()
+
+	assert.False(t, called)
+	stored, err := runs.GetJobRun(context.Background(), run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunStatusSkipped, stored.Status)
+	assert.Equal(t, "a previous run is still in progress", stored.Error)
+}
+
+func TestScheduler_RecordsFailureAndPanic(t *testing.T) {
+	tests := []struct {
+		name      string
+		run       domain.JobFunc
+		wantError string
+	}{
+		{"error", func(ctx context.Context) error { return errors.New("database unavailable") }, "database unavailable"},
+		{"panic", func(ctx context.Context) error { panic("nil map") }, "job panicked: nil map"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, tt.run)
+			run := domain.NewScheduledJobRun(job.Name, time.Now())
+
+			scheduler.dispatch(context.Background(), job, run)
+			scheduler.runs.Wait()
+
+			stored, err := runs.GetJobRun(context.Background(), run.ID)
+			require.NoError(t, err)
+			assert.Equal(t, domain.JobRunStatusFailed, stored.Status)
+			assert.Equal(t, tt.wantError, stored.Error)
+		})
+	}
+}
+
+func TestScheduler_DispatchFailsAbandonedRuns(t *testing.T) {
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error { return nil })
+	abandoned := domain.NewScheduledJobRun(job.Name, time.Now().Add(-3*time.Hour))
+	require.NoError(t, abandoned.Start(time.Now().Add(-3*time.Hour), time.Hour, nil))
+	runs.runs[abandoned.ID] = *abandoned
+
+	scheduler.dispatch(context.Background(), job, domain.NewScheduledJobRun(job.Name, time.Now()))
+	scheduler.runs.Wait()
+
+	assert.Equal(t, domain.JobRunStatusFailed, runs.runs[abandoned.ID].Status)
+}
+
+func TestScheduler_RunQueuedStartsManualRun(t *testing.T) {
+	called := false
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	run := domain.NewManualJobRun(job.Name, uuid.New())
+	_, err := runs.CreateJobRun(context.Background(), run)
+	require.NoError(t, err)
+
+	require.NoError(t, scheduler.RunQueued(context.Background(), run.ID))
+	scheduler.runs.Wait()
+
+	assert.True(t, called)
+	assert.Equal(t, domain.JobRunStatusSucceeded, runs.runs[run.ID].Status)
+	// A run is only started once
+	assert.ErrorIs(t, scheduler.RunQueued(context.Background(), run.ID), domain.ErrInvalidJobRunTransition)
+}
+
+func TestJobService_TriggerJob(t *testing.T) {
+	definitions := newFakeJobDefinitionRepository()
+	require.NoError(t, definitions.SaveJobDefinition(context.Background(), &domain.JobDefinition{Name: "cleanup", Cron: "@hourly"}))
+	runs := newFakeJobRunRepository()
+	publisher := &fakeJobTriggerPublisher{}
+	service := NewJobService(definitions, runs, publisher)
+	adminID := uuid.New()
+
+	run, err := service.TriggerJob(context.Background(), "cleanup", adminID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunTriggerManual, run.Trigger)
+	assert.Equal(t, &adminID, run.TriggeredBy)
+	assert.Equal(t, domain.JobRunStatusQueued, runs.runs[run.ID].Status)
+	assert.Len(t, publisher.published, 1)
+
+	_, err = service.TriggerJob(context.Background(), "unknown", adminID)
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobService_TriggerJobSkipsRunWhenPublishFails(t *testing.T) {
+	definitions := newFakeJobDefinitionRepository()
+	require.NoError(t, definitions.SaveJobDefinition(context.Background(), &domain.JobDefinition{Name: "cleanup", Cron: "@hourly"}))
+	runs := newFakeJobRunRepository()
+	service := NewJobService(definitions, runs, &fakeJobTriggerPublisher{err: errors.New("nats: connection closed")})
+
+	_, err := service.TriggerJob(context.Background(), "cleanup", uuid.New())
+	require.Error(t, err)
+
+	require.Len(t, runs.runs, 1)
+	for _, run := range runs.runs {
+		assert.Equal(t, domain.JobRunStatusSkipped, run.Status)
+	}
+}
+
+func TestScheduler_RunLetsRunsInProgressFinishOnShutdown(t *testing.T) {
+	release := make(chan struct{})
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	run := domain.NewManualJobRun(job.Name, uuid.New())
+	_, err := runs.CreateJobRun(context.Background(), run)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, scheduler.RunQueued(ctx, run.ID))
+	cancel()
+	close(release)
+
+	require.NoError(t, scheduler.Run(ctx))
+	assert.Equal(t, domain.JobRunStatusSucceeded, runs.runs[run.ID].Status)
+}
+
+func TestScheduler_RunCancelsRunsPastShutdownTimeout(t *testing.T) {
+	scheduler, runs, job := newTestScheduler(t, &fakeJobLocker{}, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	scheduler.ShutdownTimeout = 10 * time.Millisecond
+	run := domain.NewManualJobRun(job.Name, uuid.New())
+	_, err := runs.CreateJobRun(context.Background(), run)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, scheduler.RunQueued(ctx, run.ID))
+	cancel()
+
+	require.NoError(t, scheduler.Run(ctx))
+	assert.Equal(t, domain.JobRunStatusFailed, runs.runs[run.ID].Status)
+	assert.Equal(t, context.Canceled.Error(), runs.runs[run.ID].Error)
+}
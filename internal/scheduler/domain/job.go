@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned by a JobLocker when another run of the job holds its lock.
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// ConcurrencyPolicy decides what happens when a job is due while a previous run is still in progress.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyForbid skips the new run, across every scheduler instance.
+	ConcurrencyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyAllow starts the new run alongside the previous one.
+	ConcurrencyAllow ConcurrencyPolicy = "allow"
+)
+
+// defaultJobTimeout is used for jobs registered without a timeout.
+const defaultJobTimeout = time.Hour
+
+// JobDefinition describes a registered job, as listed to administrators.
+type JobDefinition struct {
+	Name        string            `json:"name"`
+	Cron        string            `json:"cron"`
+	Timeout     time.Duration     `json:"timeout"`
+	Concurrency ConcurrencyPolicy `json:"concurrency"`
+	NextRunAt   *time.Time        `json:"next_run_at,omitempty"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// JobFunc is the work done by a job. The context is cancelled when the job times out or its
+// scheduler stops.
+type JobFunc func(ctx context.Context) error
+
+// Job is a job registered with the scheduler.
+type Job struct {
+	JobDefinition
+	Schedule Schedule
+	Run      JobFunc
+}
+
+// NewJob creates a job running run on the cron expression cron (see ParseSchedule). A zero timeout
+// defaults to one hour and an empty concurrency policy to ConcurrencyForbid.
+func NewJob(name, cron string, timeout time.Duration, concurrency ConcurrencyPolicy, run JobFunc) (*Job, error) {
+	if name == "" {
+		return nil, errors.New("job name is required")
+	}
+	schedule, err := ParseSchedule(cron)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", name, err)
+	}
+	if timeout <= 0 {
+		timeout = defaultJobTimeout
+	}
+	switch concurrency {
+	case "":
+		concurrency = ConcurrencyForbid
+	case ConcurrencyForbid, ConcurrencyAllow:
+	default:
+		return nil, fmt.Errorf("job %s: unknown concurrency policy %q", name, concurrency)
+	}
+
+	return &Job{
+		JobDefinition: JobDefinition{Name: name, Cron: cron, Timeout: timeout, Concurrency: concurrency},
+		Schedule:      schedule,
+		Run:           run,
+	}, nil
+}
+
+// JobDefinitionRepository stores the jobs registered by the schedulers, so that they can be
+// listed and triggered from other processes.
+type JobDefinitionRepository interface {
+	SaveJobDefinition(ctx context.Context, definition *JobDefinition) error
+	ListJobDefinitions(ctx context.Context) ([]*JobDefinition, error)
+	// GetJobDefinition returns ErrJobNotFound if no scheduler registered the job.
+	GetJobDefinition(ctx context.Context, name string) (*JobDefinition, error)
+}
+
+// JobLocker runs a function while holding the lock of a job, shared by every scheduler instance.
+type JobLocker interface {
+	// TryLock runs fn if the lock of the job is free and returns ErrJobRunning otherwise. fn receives
+	// the fencing token of the lock, which increases with every acquisition.
+	TryLock(ctx context.Context, jobName string, fn func(ctx context.Context, fencingToken int64) error) error
+}
+
+// JobTriggerPublisher notifies the schedulers of manually triggered runs.
+type JobTriggerPublisher interface {
+	PublishJobTrigger(ctx context.Context, run *JobRun) error
+}
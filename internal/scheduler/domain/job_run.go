@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRunStatus is the state of a job run, matching the job_runs.status CHECK constraint.
+//
+// A run moves queued → running → succeeded or failed, or queued → skipped when the concurrency
+// policy of its job prevents it from starting.
+type JobRunStatus string
+
+const (
+	JobRunStatusQueued    JobRunStatus = "queued"
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+	JobRunStatusSkipped   JobRunStatus = "skipped"
+)
+
+// JobRunTrigger is what started a job run.
+type JobRunTrigger string
+
+const (
+	JobRunTriggerSchedule JobRunTrigger = "schedule"
+	JobRunTriggerManual   JobRunTrigger = "manual"
+)
+
+var (
+	ErrJobRunNotFound          = errors.New("job run not found")
+	ErrInvalidJobRunTransition = errors.New("invalid job run status transition")
+)
+
+// JobRun is the record of one execution of a job.
+type JobRun struct {
+	ID      uuid.UUID     `json:"id"`
+	JobName string        `json:"job_name"`
+	Trigger JobRunTrigger `json:"trigger"`
+	// TriggeredBy is the administrator who started a manual run.
+	TriggeredBy *uuid.UUID `json:"triggered_by,omitempty"`
+	// ScheduledAt is the activation time of a scheduled run. It identifies the run, so that only
+	// one scheduler instance runs each activation.
+	ScheduledAt *time.Time   `json:"scheduled_at,omitempty"`
+	Status      JobRunStatus `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`
+	FinishedAt  *time.Time   `json:"finished_at,omitempty"`
+	// DeadlineAt is when a running run times out; runs still running past it were abandoned by a crashed scheduler.
+	DeadlineAt   *time.Time `json:"deadline_at,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	FencingToken *int64     `json:"fencing_token,omitempty"`
+}
+
+// NewScheduledJobRun creates a queued run for the activation of a job at scheduledAt.
+func NewScheduledJobRun(jobName string, scheduledAt time.Time) *JobRun {
+	return &JobRun{
+		ID:          uuid.New(),
+		JobName:     jobName,
+		Trigger:     JobRunTriggerSchedule,
+		ScheduledAt: &scheduledAt,
+		Status:      JobRunStatusQueued,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NewManualJobRun creates a queued run of a job triggered by an administrator.
+func NewManualJobRun(jobName string, triggeredBy uuid.UUID) *JobRun {
+	return &JobRun{
+		ID:          uuid.New(),
+		JobName:     jobName,
+		Trigger:     JobRunTriggerManual,
+		TriggeredBy: &triggeredBy,
+		Status:      JobRunStatusQueued,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Start marks a queued run as running until its deadline.
+func (r *JobRun) Start(at time.Time, timeout time.Duration, fencingToken *int64) error {
+	if r.Status != JobRunStatusQueued {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidJobRunTransition, r.Status, JobRunStatusRunning)
+	}
+	deadline := at.Add(timeout)
+	r.Status = JobRunStatusRunning
+	r.StartedAt = &at
+	r.DeadlineAt = &deadline
+	r.FencingToken = fencingToken
+	return nil
+}
+
+// Finish marks a running run as succeeded, or failed with the message of err.
+func (r *JobRun) Finish(at time.Time, err error) error {
+	if r.Status != JobRunStatusRunning {
+		return fmt.Errorf("%w: %s → finished", ErrInvalidJobRunTransition, r.Status)
+	}
+	r.Status = JobRunStatusSucceeded
+	if err != nil {
+		r.Status = JobRunStatusFailed
+		r.Error = err.Error()
+	}
+	r.FinishedAt = &at
+	return nil
+}
+
+// Skip marks a queued run as skipped for the given reason.
+func (r *JobRun) Skip(at time.Time, reason string) error {
+	if r.Status != JobRunStatusQueued {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidJobRunTransition, r.Status, JobRunStatusSkipped)
+	}
+	r.Status = JobRunStatusSkipped
+	r.Error = reason
+	r.FinishedAt = &at
+	return nil
+}
+
+// JobRunRepository defines the interface for interacting with job run history.
+type JobRunRepository interface {
+	// CreateJobRun records a new run. It returns false without error if a run of the same job was
+	// already recorded for the same ScheduledAt, i.e. another scheduler instance claimed the activation.
+	CreateJobRun(ctx context.Context, run *JobRun) (bool, error)
+	// GetJobRun returns ErrJobRunNotFound if the run does not exist.
+	GetJobRun(ctx context.Context, id uuid.UUID) (*JobRun, error)
+	// UpdateJobRun saves the run only if its stored status is still expectedStatus,
+	// and returns ErrInvalidJobRunTransition otherwise.
+	UpdateJobRun(ctx context.Context, run *JobRun, expectedStatus JobRunStatus) error
+	// ListJobRuns returns the most recent runs of a job, newest first.
+	ListJobRuns(ctx context.Context, jobName string, limit int) ([]*JobRun, error)
+	// FailAbandonedJobRuns marks the runs of a job whose deadline passed before the given time
+	// and that are still running as failed.
+	FailAbandonedJobRuns(ctx context.Context, jobName string, deadlineBefore time.Time) (int64, error)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	from := time.Date(2025, time.January, 31, 10, 17, 42, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{"5,50 * * * *", time.Date(2025, time.January, 31, 10, 50, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2025, time.February, 1, 3, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, time.February, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either matches
+		{"0 12 15 * 6", time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		{"@every 10m", time.Date(2025, time.January, 31, 10, 20, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 0s", "@every soon"} {
+		_, err := ParseSchedule(spec)
+		assert.ErrorIs(t, err, ErrInvalidSchedule, spec)
+	}
+}
+
+func TestParseSchedule_NeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
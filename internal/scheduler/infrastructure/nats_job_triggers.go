@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/application"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// jobTriggerQueue is the queue group of the schedulers, so that each trigger reaches a single instance.
+const jobTriggerQueue = "scheduler"
+
+// jobTriggerSubject returns the subject on which manual runs of a job are announced.
+func jobTriggerSubject(jobName string) string {
+	return "scheduler.jobs." + jobName + ".triggered"
+}
+
+// jobTriggeredMessage announces a queued manual run.
+type jobTriggeredMessage struct {
+	RunID   string `json:"run_id"`
+	JobName string `json:"job_name"`
+}
+
+// NATSJobTriggerPublisher is a NATS implementation of the domain.JobTriggerPublisher.
+type NATSJobTriggerPublisher struct {
+	conn *nats.Conn
+}
+
+// NewNATSJobTriggerPublisher creates a new NATSJobTriggerPublisher.
+func NewNATSJobTriggerPublisher(conn *nats.Conn) *NATSJobTriggerPublisher {
+	return &NATSJobTriggerPublisher{conn: conn}
+}
+
+// PublishJobTrigger announces a queued manual run to the schedulers of its job.
+func (p *NATSJobTriggerPublisher) PublishJobTrigger(ctx context.Context, run *domain.JobRun) error {
+	if p.conn == nil {
+		return fmt.Errorf("NATS connection is not available")
+	}
+	data, err := json.Marshal(jobTriggeredMessage{RunID: run.ID.String(), JobName: run.JobName})
+	if err != nil {
+		return err
+	}
+	return p.conn.Publish(jobTriggerSubject(run.JobName), data)
+}
+
+// SubscribeJobTriggers starts the manual runs announced for the jobs registered with scheduler.
+// Runs are started with ctx, which should be the context the scheduler runs with.
+func SubscribeJobTriggers(ctx context.Context, conn *nats.Conn, scheduler *application.Scheduler) ([]*nats.Subscription, error) {
+	var subs []*nats.Subscription
+	for _, job := range scheduler.Jobs() {
+		sub, err := conn.QueueSubscribe(jobTriggerSubject(job.Name), jobTriggerQueue, func(msg *nats.Msg) {
+			var message jobTriggeredMessage
+			if err := json.Unmarshal(msg.Data, &message); err != nil {
+				fmt.Printf("Warning: invalid job trigger: %v\n", err)
+				return
+			}
+			runID, err := uuid.Parse(message.RunID)
+			if err != nil {
+				fmt.Printf("Warning: invalid job trigger run ID %q\n", message.RunID)
+				return
+			}
+			if err := scheduler.RunQueued(ctx, runID); err != nil {
+				fmt.Printf("Warning: failed to start run %s of job %s: %v\n", runID, message.JobName, err)
+			}
+		})
+		if err != nil {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+			return nil, fmt.Errorf("failed to subscribe to triggers of job %s: %w", job.Name, err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
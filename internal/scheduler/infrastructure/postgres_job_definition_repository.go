@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+)
+
+const jobDefinitionColumns = `name, cron, timeout_seconds, concurrency, next_run_at, updated_at`
+
+// PostgresJobDefinitionRepository is a PostgreSQL implementation of the domain.JobDefinitionRepository.
+type PostgresJobDefinitionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresJobDefinitionRepository creates a new PostgresJobDefinitionRepository.
+func NewPostgresJobDefinitionRepository(pool *pgxpool.Pool) *PostgresJobDefinitionRepository {
+	return &PostgresJobDefinitionRepository{pool: pool}
+}
+
+// SaveJobDefinition inserts or replaces the definition of a job.
+func (r *PostgresJobDefinitionRepository) SaveJobDefinition(ctx context.Context, definition *domain.JobDefinition) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO scheduled_jobs (name, cron, timeout_seconds, concurrency, next_run_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (name) DO UPDATE
+		 SET cron = EXCLUDED.cron, timeout_seconds = EXCLUDED.timeout_seconds, concurrency = EXCLUDED.concurrency,
+		     next_run_at = EXCLUDED.next_run_at, updated_at = EXCLUDED.updated_at`,
+		definition.Name, definition.Cron, int64(definition.Timeout/time.Second), string(definition.Concurrency),
+		definition.NextRunAt, definition.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save job definition: %w", err)
+	}
+	return nil
+}
+
+// ListJobDefinitions returns every registered job ordered by name.
+func (r *PostgresJobDefinitionRepository) ListJobDefinitions(ctx context.Context) ([]*domain.JobDefinition, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+jobDefinitionColumns+` FROM scheduled_jobs ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job definitions: %w", err)
+	}
+	definitions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.JobDefinition, error) {
+		return scanJobDefinition(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// GetJobDefinition returns the definition of a job by its name.
+func (r *PostgresJobDefinitionRepository) GetJobDefinition(ctx context.Context, name string) (*domain.JobDefinition, error) {
+	definition, err := scanJobDefinition(r.pool.QueryRow(ctx, `SELECT `+jobDefinitionColumns+` FROM scheduled_jobs WHERE name = $1`, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job definition: %w", err)
+	}
+	return definition, nil
+}
+
+func scanJobDefinition(row pgx.Row) (*domain.JobDefinition, error) {
+	var definition domain.JobDefinition
+	var timeoutSeconds int64
+	var concurrency string
+	err := row.Scan(&definition.Name, &definition.Cron, &timeoutSeconds, &concurrency, &definition.NextRunAt, &definition.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	definition.Timeout = time.Duration(timeoutSeconds) * time.Second
+	definition.Concurrency = domain.ConcurrencyPolicy(concurrency)
+	return &definition, nil
+}
@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+)
+
+const jobRunColumns = `id, job_name, trigger, triggered_by, scheduled_at, status, created_at,
+	started_at, finished_at, deadline_at, COALESCE(error, ''), fencing_token`
+
+// PostgresJobRunRepository is a PostgreSQL implementation of the domain.JobRunRepository.
+type PostgresJobRunRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresJobRunRepository creates a new PostgresJobRunRepository.
+func NewPostgresJobRunRepository(pool *pgxpool.Pool) *PostgresJobRunRepository {
+	return &PostgresJobRunRepository{pool: pool}
+}
+
+// CreateJobRun inserts the run, relying on the unique (job_name, scheduled_at) index to let a
+// single scheduler instance claim each activation.
+func (r *PostgresJobRunRepository) CreateJobRun(ctx context.Context, run *domain.JobRun) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO job_runs (id, job_name, trigger, triggered_by, scheduled_at, status, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (job_name, scheduled_at) DO NOTHING`,
+		run.ID, run.JobName, string(run.Trigger), run.TriggeredBy, run.ScheduledAt, string(run.Status), run.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create job run: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetJobRun returns a run by its ID.
+func (r *PostgresJobRunRepository) GetJobRun(ctx context.Context, id uuid.UUID) (*domain.JobRun, error) {
+	run, err := scanJobRun(r.pool.QueryRow(ctx, `SELECT `+jobRunColumns+` FROM job_runs WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrJobRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job run: %w", err)
+	}
+	return run, nil
+}
+
+// UpdateJobRun saves the run if its stored status is still expectedStatus, so that a run is only
+// started and finished once. Runs saved under a job lease are fenced, so a holder whose lease was
+// taken over cannot overwrite them.
+func (r *PostgresJobRunRepository) UpdateJobRun(ctx context.Context, run *domain.JobRun, expectedStatus domain.JobRunStatus) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := infrastructure.CheckFence(ctx, tx); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE job_runs
+		 SET status = $3, started_at = $4, finished_at = $5, deadline_at = $6, error = NULLIF($7, ''), fencing_token = $8
+		 WHERE id = $1 AND status = $2`,
+		run.ID, string(expectedStatus), string(run.Status), run.StartedAt, run.FinishedAt, run.DeadlineAt, run.Error, run.FencingToken,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update job run: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("job run %s is no longer %s: %w", run.ID, expectedStatus, domain.ErrInvalidJobRunTransition)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit job run: %w", err)
+	}
+	return nil
+}
+
+// ListJobRuns returns the most recent runs of a job, newest first.
+func (r *PostgresJobRunRepository) ListJobRuns(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+jobRunColumns+`
+		 FROM job_runs
+		 WHERE job_name = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2`,
+		jobName, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", err)
+	}
+	runs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.JobRun, error) {
+		return scanJobRun(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// FailAbandonedJobRuns marks the runs of a job still running past deadlineBefore as failed.
+func (r *PostgresJobRunRepository) FailAbandonedJobRuns(ctx context.Context, jobName string, deadlineBefore time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE job_runs
+		 SET status = 'failed', finished_at = now(), error = 'abandoned: the scheduler stopped before the run finished'
+		 WHERE job_name = $1 AND status = 'running' AND deadline_at < $2`,
+		jobName, deadlineBefore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail abandoned job runs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanJobRun(row pgx.Row) (*domain.JobRun, error) {
+	var run domain.JobRun
+	var trigger, status string
+	err := row.Scan(
+		&run.ID, &run.JobName, &trigger, &run.TriggeredBy, &run.ScheduledAt, &status, &run.CreatedAt,
+		&run.StartedAt, &run.FinishedAt, &run.DeadlineAt, &run.Error, &run.FencingToken,
+	)
+	if err != nil {
+		return nil, err
+	}
+	run.Trigger = domain.JobRunTrigger(trigger)
+	run.Status = domain.JobRunStatus(status)
+	return &run, nil
+}
@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+)
+
+// RedisJobLocker is a domain.JobLocker holding one infrastructure.LeaseLock per job.
+type RedisJobLocker struct {
+	client   *redis.Client
+	leaseTTL time.Duration
+}
+
+// NewRedisJobLocker creates a new RedisJobLocker whose leases expire leaseTTL after their last renewal.
+func NewRedisJobLocker(client *redis.Client, leaseTTL time.Duration) *RedisJobLocker {
+	return &RedisJobLocker{client: client, leaseTTL: leaseTTL}
+}
+
+// TryLock runs fn while holding the lease of the job.
+func (l *RedisJobLocker) TryLock(ctx context.Context, jobName string, fn func(ctx context.Context, fencingToken int64) error) error {
+	lock := infrastructure.NewLeaseLock(l.client, "job:"+jobName, l.leaseTTL)
+	err := lock.TryRun(ctx, func(ctx context.Context, lease *infrastructure.Lease) error {
+		return fn(ctx, lease.FencingToken)
+	})
+	if errors.Is(err, infrastructure.ErrLockHeld) {
+		return domain.ErrJobRunning
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
+
+// CancelAccountDeletionLink returns the link sent to users to cancel a pending deletion.
+func CancelAccountDeletionLink(appURL, recoveryToken string) string {
+	return fmt.Sprintf("%s/cancel-account-deletion?token=%s", appURL, recoveryToken)
+}
+
+// CancelAccountDeletion is a use case for cancelling a pending account deletion with its recovery token.
+type CancelAccountDeletion struct {
+	UserRepository              domain.UserRepository
+	UserDeletionRepository      domain.UserDeletionRepository
+	UserDeletionCycleRepository domain.UserDeletionCycleRepository
+	ActionLogRepository         domain.ActionLogRepository
+	EventBus                    domain.EventBus
+	Policy                      domain.DeletionPolicy
+}
+
+// NewCancelAccountDeletion creates a new CancelAccountDeletion use case.
+func NewCancelAccountDeletion(userRepository domain.UserRepository, userDeletionRepository domain.UserDeletionRepository, userDeletionCycleRepository domain.UserDeletionCycleRepository, actionLogRepository domain.ActionLogRepository, eventBus domain.EventBus, policy domain.DeletionPolicy) *CancelAccountDeletion {
+	return &CancelAccountDeletion{
+		UserRepository:              userRepository,
+		UserDeletionRepository:      userDeletionRepository,
+		UserDeletionCycleRepository: userDeletionCycleRepository,
+		ActionLogRepository:         actionLogRepository,
+		EventBus:                    eventBus,
+		Policy:                      policy,
+	}
+}
+
+// Execute cancels the deletion that issued the recovery token and clears the user's deletion date.
+// The token is single-use: it is removed from the deletion once the cancellation is saved. The
+// reminders of the deletion that were not sent yet are cancelled.
+func (uc *CancelAccountDeletion) Execute(ctx context.Context, token string) error {
+	now := time.Now()
+
+	deletion, err := uc.UserDeletionRepository.GetUserDeletionByRecoveryToken(ctx, token)
+	if errors.Is(err, domain.ErrUserDeletionNotFound) {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if deletion.RecoveryTokenExpiresAt != nil && now.After(*deletion.RecoveryTokenExpiresAt) {
+		return domain.ErrInvalidToken
+	}
+	if deletion.Status.IsTerminal() {
+		return domain.ErrInvalidToken
+	}
+
+	previousStatus := deletion.Status
+	if err := deletion.TransitionTo(domain.DeletionStatusCancelled, now); err != nil {
+		return err
+	}
+	deletion.RecoveryToken = nil
+	deletion.RecoveryTokenExpiresAt = nil
+	if err := uc.UserDeletionRepository.UpdateUserDeletion(ctx, deletion, previousStatus); err != nil {
+		return err
+	}
+
+	user, err := uc.UserRepository.GetUserByID(ctx, deletion.UserID)
+	if err != nil {
+		return err
+	}
+	user.DeletionDueAt = nil
+	if err := uc.UserRepository.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	if err := uc.UserDeletionCycleRepository.IncrementUserDeletionCycle(ctx, user.ID, uc.Policy.Cycles.Window); err != nil {
+		fmt.Printf("Warning: failed to increment deletion cycle for user %s: %v\n", user.ID, err)
+	}
+
+	for _, offset := range uc.Policy.Reminders() {
+		cancelBytes, err := json.Marshal(events.EmailCancelRequest{
+			IdempotencyKey: deletionReminderIdempotencyKey(deletion.ID, offset),
+			CorrelationID:  util.RequestIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+		if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.cancel", Data: cancelBytes}); err != nil {
+			fmt.Printf("Warning: failed to cancel account deletion reminder for user %s: %v\n", user.ID, err)
+		}
+	}
+
+	actionLog := domain.NewActionLog(&user.ID, domain.ActionAccountDeletionCancelled, map[string]any{
+		"deletion_id":     deletion.ID.String(),
+		"previous_status": string(previousStatus),
+	})
+	if err := uc.ActionLogRepository.CreateActionLog(ctx, actionLog); err != nil {
+		fmt.Printf("Warning: failed to write action log for user %s: %v\n", user.ID, err)
+	}
+
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateDeletionCancelled, events.DeletionCancelledEmailData{Name: user.Name})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes}); err != nil {
+		fmt.Printf("Warning: failed to send deletion cancellation email to %s: %v\n", user.Email, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserDeletionCycleRepository is an in-memory implementation of domain.UserDeletionCycleRepository for testing
+type fakeUserDeletionCycleRepository struct {
+	cycles map[uuid.UUID]*domain.UserDeletionCycle
+}
+
+func (r *fakeUserDeletionCycleRepository) GetUserDeletionCycle(ctx context.Context, userID uuid.UUID) (*domain.UserDeletionCycle, error) {
+	if cycle, ok := r.cycles[userID]; ok {
+		return cycle, nil
+	}
+	return &domain.UserDeletionCycle{UserID: userID}, nil
+}
+
+func (r *fakeUserDeletionCycleRepository) IncrementUserDeletionCycle(ctx context.Context, userID uuid.UUID, window time.Duration) error {
+	if r.cycles == nil {
+		r.cycles = make(map[uuid.UUID]*domain.UserDeletionCycle)
+	}
+	now := time.Now()
+	cycle, ok := r.cycles[userID]
+	if !ok || now.Sub(*cycle.LastCycleAt) > window {
+		cycle = &domain.UserDeletionCycle{UserID: userID}
+		r.cycles[userID] = cycle
+	}
+	cycle.Cycles++
+	cycle.LastCycleAt = &now
+	return nil
+}
+
+// fakeActionLogRepository records action logs for testing
+type fakeActionLogRepository struct {
+	logs []*domain.ActionLog
+}
+
+func (r *fakeActionLogRepository) CreateActionLog(ctx context.Context, actionLog *domain.ActionLog) error {
+	r.logs = append(r.logs, actionLog)
+	return nil
+}
+
+func TestCancelAccountDeletion_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	scheduledDate := time.Now().Add(30 * 24 * time.Hour)
+	user.DeletionDueAt = &scheduledDate
+	recoveryToken := "recovery-token"
+	deletion := &domain.UserDeletion{
+		ID:                     uuid.New(),
+		UserID:                 user.ID,
+		ScheduledDate:          scheduledDate,
+		Status:                 domain.DeletionStatusWarned,
+		RecoveryToken:          &recoveryToken,
+		RecoveryTokenExpiresAt: &scheduledDate,
+	}
+	scheduledDay := scheduledDate.Truncate(24 * time.Hour)
+	deletionRepo := &fakeUserDeletionRepository{deletions: []*domain.UserDeletion{deletion}, capacity: map[time.Time]int{scheduledDay: 1}}
+	cycleRepo := &fakeUserDeletionCycleRepository{}
+	actionLogRepo := &fakeActionLogRepository{}
+	eventBus := &fakeEventBus{}
+	uc := NewCancelAccountDeletion(newFakeUserRepository(user), deletionRepo, cycleRepo, actionLogRepo, eventBus, domain.DeletionPolicy{})
+
+	// Test case 1: Unknown token
+	assert.ErrorIs(t, uc.Execute(ctx, "unknown-token"), domain.ErrInvalidToken)
+
+	// Test case 2: Valid token cancels the deletion and releases its day's capacity
+	require.NoError(t, uc.Execute(ctx, recoveryToken))
+	assert.Equal(t, domain.DeletionStatusCancelled, deletion.Status)
+	assert.NotNil(t, deletion.CancelledAt)
+	assert.Equal(t, 0, deletionRepo.capacity[scheduledDay])
+	assert.Nil(t, user.DeletionDueAt)
+	assert.Equal(t, 1, cycleRepo.cycles[user.ID].Cycles)
+	require.Len(t, actionLogRepo.logs, 1)
+	assert.Equal(t, domain.ActionAccountDeletionCancelled, actionLogRepo.logs[0].Action)
+	assert.Equal(t, []string{"email.send"}, eventBus.subjects())
+
+	// Test case 3: The token cannot be used twice
+	assert.ErrorIs(t, uc.Execute(ctx, recoveryToken), domain.ErrInvalidToken)
+	assert.Equal(t, 1, cycleRepo.cycles[user.ID].Cycles)
+	assert.Equal(t, 0, deletionRepo.capacity[scheduledDay])
+}
+
+func TestCancelAccountDeletion_Execute_ExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	recoveryToken := "recovery-token"
+	expiredAt := time.Now().Add(-time.Hour)
+	deletion := &domain.UserDeletion{
+		ID:                     uuid.New(),
+		UserID:                 user.ID,
+		ScheduledDate:          expiredAt,
+		Status:                 domain.DeletionStatusScheduled,
+		RecoveryToken:          &recoveryToken,
+		RecoveryTokenExpiresAt: &expiredAt,
+	}
+	deletionRepo := &fakeUserDeletionRepository{deletions: []*domain.UserDeletion{deletion}}
+	uc := NewCancelAccountDeletion(newFakeUserRepository(user), deletionRepo, &fakeUserDeletionCycleRepository{}, &fakeActionLogRepository{}, &fakeEventBus{}, domain.DeletionPolicy{})
+
+	assert.ErrorIs(t, uc.Execute(ctx, recoveryToken), domain.ErrInvalidToken)
+	assert.Equal(t, domain.DeletionStatusScheduled, deletion.Status)
+}
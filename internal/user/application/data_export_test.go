@@ -0,0 +1,117 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserDataExportRepository returns a fixed export for testing
+type fakeUserDataExportRepository struct {
+	export *domain.UserDataExport
+}
+
+func (r *fakeUserDataExportRepository) GetUserDataExport(ctx context.Context, userID uuid.UUID) (*domain.UserDataExport, error) {
+	if r.export == nil || r.export.Profile.ID != userID {
+		return nil, domain.ErrUserNotFound
+	}
+	return r.export, nil
+}
+
+func TestRequestDataExport_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	eventBus := &fakeEventBus{}
+	actionLogRepo := &fakeActionLogRepository{}
+	uc := NewRequestDataExport(newFakeUserRepository(user), newFakeTokenRepository(), actionLogRepo, eventBus, 24*time.Hour)
+
+	require.NoError(t, uc.Execute(ctx, user.ID))
+	require.Equal(t, []string{"user.data_export.requested"}, eventBus.subjects())
+	var event events.DataExportRequestedEvent
+	require.NoError(t, json.Unmarshal(eventBus.events[0].Data, &event))
+	assert.Equal(t, user.ID.String(), event.UserID)
+	require.Len(t, actionLogRepo.logs, 1)
+	assert.Equal(t, domain.ActionDataExportRequested, actionLogRepo.logs[0].Action)
+
+	// Test case 2: A second request within the cooldown is rejected
+	assert.ErrorIs(t, uc.Execute(ctx, user.ID), domain.ErrDataExportTooSoon)
+	assert.Len(t, eventBus.events, 1)
+}
+
+func TestExportUserData_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	deletionToken, recoveryToken := "deletion-token", "recovery-token"
+	export := &domain.UserDataExport{
+		Profile:    user,
+		Sessions:   []*domain.RefreshToken{{ID: uuid.New(), UserID: user.ID, Token: "refresh-token", IPAddress: "203.0.113.1"}},
+		Logins:     []*domain.UserLogin{{ID: uuid.New(), UserID: user.ID, IPAddress: "203.0.113.1", Success: true}},
+		EmailSends: []*domain.Email{{ID: uuid.New(), UserID: user.ID, Type: "verification"}},
+		ActionLogs: []*domain.ActionLog{domain.NewActionLog(&user.ID, domain.ActionAccountDeletionCancelled, nil)},
+		Deletions:  []*domain.UserDeletion{{ID: uuid.New(), UserID: user.ID, Status: domain.DeletionStatusCancelled, Token: &deletionToken, RecoveryToken: &recoveryToken}},
+	}
+	blobStore := &fakeBlobStore{}
+	eventBus := &fakeEventBus{}
+	actionLogRepo := &fakeActionLogRepository{}
+	uc := NewExportUserData(&fakeUserDataExportRepository{export: export}, blobStore, actionLogRepo, eventBus, 7*24*time.Hour)
+
+	exportID := uuid.New()
+	require.NoError(t, uc.Execute(ctx, events.DataExportRequestedEvent{ExportID: exportID.String(), UserID: user.ID.String()}))
+
+	key := domain.DataExportBlobKey(user.ID, exportID)
+	require.Contains(t, blobStore.objects, key)
+	archive, err := zip.NewReader(bytes.NewReader(blobStore.objects[key]), int64(len(blobStore.objects[key])))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[file.Name] = string(content)
+	}
+	assert.ElementsMatch(t, []string{"profile.json", "sessions.json", "login_history.json", "email_sends.json", "action_logs.json", "deletion_history.json"}, mapKeys(files))
+	assert.Contains(t, files["profile.json"], user.Email)
+	assert.NotContains(t, files["profile.json"], user.PasswordHash)
+	assert.Contains(t, files["sessions.json"], "203.0.113.1")
+	assert.NotContains(t, files["sessions.json"], "refresh-token")
+	assert.NotContains(t, files["deletion_history.json"], deletionToken)
+	assert.NotContains(t, files["deletion_history.json"], recoveryToken)
+
+	require.Equal(t, []string{"email.send"}, eventBus.subjects())
+	var emailRequest events.EmailSendRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[0].Data, &emailRequest))
+	assert.Equal(t, user.Email, emailRequest.Recipient)
+	assert.Equal(t, events.EmailTemplateDataExportReady, emailRequest.TemplateName)
+	var ready events.DataExportReadyEmailData
+	require.NoError(t, json.Unmarshal(emailRequest.TemplateData, &ready))
+	assert.Contains(t, ready.DownloadLink, "https://blobs.example.com/"+key)
+	require.Len(t, actionLogRepo.logs, 1)
+	assert.Equal(t, domain.ActionDataExported, actionLogRepo.logs[0].Action)
+
+	// Test case 2: A new export replaces the previous archive
+	nextExportID := uuid.New()
+	require.NoError(t, uc.Execute(ctx, events.DataExportRequestedEvent{ExportID: nextExportID.String(), UserID: user.ID.String()}))
+	assert.NotContains(t, blobStore.objects, key)
+	assert.Contains(t, blobStore.objects, domain.DataExportBlobKey(user.ID, nextExportID))
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
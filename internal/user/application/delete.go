@@ -3,56 +3,82 @@ package application
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
 )
 
-const maxDeletionsPerDay = 10
-
 // DeleteUser is a use case for deleting a user.
 type DeleteUser struct {
-	UserRepository             domain.UserRepository
-	UserDeletionRepository     domain.UserDeletionRepository
-	DeletionCapacityRepository domain.DeletionCapacityRepository
-	EventBus                   domain.EventBus
+	UserRepository              domain.UserRepository
+	UserDeletionRepository      domain.UserDeletionRepository
+	EventBus                    domain.EventBus
+	AppURL                      string
+	UserDeletionCycleRepository domain.UserDeletionCycleRepository
+	Policy                      domain.DeletionPolicy
 }
 
 // NewDeleteUser creates a new DeleteUser use case.
-func NewDeleteUser(userRepository domain.UserRepository, userDeletionRepository domain.UserDeletionRepository, deletionCapacityRepository domain.DeletionCapacityRepository, eventBus domain.EventBus) *DeleteUser {
+func NewDeleteUser(userRepository domain.UserRepository, userDeletionRepository domain.UserDeletionRepository, eventBus domain.EventBus, appURL string, userDeletionCycleRepository domain.UserDeletionCycleRepository, policy domain.DeletionPolicy) *DeleteUser {
 	return &DeleteUser{
-		UserRepository:             userRepository,
-		UserDeletionRepository:     userDeletionRepository,
-		DeletionCapacityRepository: deletionCapacityRepository,
-		EventBus:                   eventBus,
+		UserRepository:              userRepository,
+		UserDeletionRepository:      userDeletionRepository,
+		EventBus:                    eventBus,
+		AppURL:                      appURL,
+		UserDeletionCycleRepository: userDeletionCycleRepository,
+		Policy:                      policy,
 	}
 }
 
-// Execute schedules a user for deletion.
+// Execute schedules a user for deletion. The user receives a recovery link that cancels the
+// deletion until the scheduled date, and the reminders of the warning schedule are scheduled with
+// the notification worker. Users who recently cancelled a deletion, or cancelled too many, are
+// rejected according to the cycle limits of the DeletionPolicy, and repeated requests with
+// domain.ErrDeletionAlreadyPending until the pending deletion is cancelled.
 func (uc *DeleteUser) Execute(ctx context.Context, id uuid.UUID) error {
-	today := time.Now().Truncate(24 * time.Hour)
-	capacity, err := uc.DeletionCapacityRepository.GetDeletionCapacity(ctx, today)
+	user, err := uc.UserRepository.GetUserByID(ctx, id)
 	if err != nil {
-		// If there is no entry for today, we can assume the count is 0
-		capacity = &domain.DeletionCapacity{Count: 0, MaxLimit: maxDeletionsPerDay}
+		return err
+	}
+	if user.IsDeleted {
+		return domain.ErrUserDeleted
 	}
 
-	if capacity.Count >= capacity.MaxLimit {
-		return errors.New("deletion limit exceeded")
+	cycle, err := uc.UserDeletionCycleRepository.GetUserDeletionCycle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.Policy.Cycles.Check(cycle, time.Now()); err != nil {
+		return err
 	}
 
-	scheduledDate := time.Now().Add(90 * 24 * time.Hour)
+	recoveryToken, err := util.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
 
 	userDeletion := &domain.UserDeletion{
 		ID:            uuid.New(),
 		UserID:        id,
-		ScheduledDate: scheduledDate,
-		Status:        "scheduled",
+		ScheduledDate: time.Now().Add(uc.Policy.GracePeriod),
+		Status:        domain.DeletionStatusQueued,
+		RecoveryToken: &recoveryToken,
 	}
+	// The recovery token expires on the scheduled date, which may be pushed back when the
+	// requested day has no deletion capacity left
+	userDeletion.RecoveryTokenExpiresAt = &userDeletion.ScheduledDate
 
-	if err := uc.UserDeletionRepository.CreateUserDeletion(ctx, userDeletion); err != nil {
+	if err := uc.UserDeletionRepository.ScheduleUserDeletion(ctx, userDeletion, uc.Policy.DailyCapacity); err != nil {
+		return err
+	}
+	scheduledDate := userDeletion.ScheduledDate
+
+	user.DeletionDueAt = &scheduledDate
+	if err := uc.UserRepository.UpdateUser(ctx, user); err != nil {
 		return err
 	}
 
@@ -65,5 +91,63 @@ func (uc *DeleteUser) Execute(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	return uc.DeletionCapacityRepository.IncrementDeletionCapacity(ctx, today)
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateDeletionScheduled, events.DeletionScheduledEmailData{
+		Name:         user.Name,
+		DeletionDate: scheduledDate,
+		CancelLink:   CancelAccountDeletionLink(uc.AppURL, recoveryToken),
+	})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes}); err != nil {
+		fmt.Printf("Warning: failed to send account deletion email to %s: %v\n", user.Email, err)
+	}
+
+	if err := uc.scheduleReminders(ctx, user, userDeletion); err != nil {
+		fmt.Printf("Warning: failed to schedule account deletion reminders for %s: %v\n", user.Email, err)
+	}
+
+	return nil
+}
+
+// scheduleReminders schedules a reminder email at each offset of the warning schedule before the
+// deletion date, skipping the reminders already due, as the confirmation email has the date.
+func (uc *DeleteUser) scheduleReminders(ctx context.Context, user *domain.User, deletion *domain.UserDeletion) error {
+	now := time.Now()
+	data := events.DeletionWarningEmailData{
+		Name:         user.Name,
+		DeletionDate: deletion.ScheduledDate,
+		CancelLink:   CancelAccountDeletionLink(uc.AppURL, *deletion.RecoveryToken),
+		SignInLink:   uc.AppURL,
+	}
+	for _, offset := range uc.Policy.Reminders() {
+		sendAt := deletion.ScheduledDate.Add(-offset)
+		if !sendAt.After(now) {
+			continue
+		}
+		emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateDeletionWarning, data)
+		if err != nil {
+			return err
+		}
+		emailRequest.IdempotencyKey = deletionReminderIdempotencyKey(deletion.ID, offset)
+		emailRequest.SendAt = sendAt
+		emailDataBytes, err := json.Marshal(emailRequest)
+		if err != nil {
+			return err
+		}
+		if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletionReminderIdempotencyKey identifies the reminder of a deletion sent offset before its
+// date, which cancelling the deletion cancels.
+func deletionReminderIdempotencyKey(deletionID uuid.UUID, offset time.Duration) string {
+	return "deletion_warning:" + deletionID.String() + ":" + offset.String()
 }
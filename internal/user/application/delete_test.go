@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteUser_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	deletionRepo := &fakeUserDeletionRepository{}
+	cycleRepo := &fakeUserDeletionCycleRepository{}
+	eventBus := &fakeEventBus{}
+	policy := domain.DeletionPolicy{
+		GracePeriod:     90 * 24 * time.Hour,
+		WarningSchedule: []time.Duration{120 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour},
+		DailyCapacity:   10,
+		Cycles:          domain.DeletionCyclePolicy{MaxCycles: 1, Window: 30 * 24 * time.Hour, Cooldown: 24 * time.Hour},
+	}
+	uc := NewDeleteUser(newFakeUserRepository(user), deletionRepo, eventBus, "http://localhost:8080", cycleRepo, policy)
+
+	// Test case 1: The deletion is queued with a recovery link
+	require.NoError(t, uc.Execute(ctx, user.ID))
+	require.Len(t, deletionRepo.deletions, 1)
+	deletion := deletionRepo.deletions[0]
+	assert.Equal(t, domain.DeletionStatusQueued, deletion.Status)
+	require.NotNil(t, deletion.RecoveryToken)
+	assert.Equal(t, deletion.ScheduledDate, *user.DeletionDueAt)
+	assert.Equal(t, deletion.ScheduledDate, *deletion.RecoveryTokenExpiresAt)
+	// The reminders are scheduled, except the one that would be due before the deletion request
+	assert.Equal(t, []string{"user.delete", "email.send", "email.send", "email.send"}, eventBus.subjects())
+	var reminder events.EmailSendRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[3].Data, &reminder))
+	assert.Equal(t, events.EmailTemplateDeletionWarning, reminder.TemplateName)
+	assert.Equal(t, "deletion_warning:"+deletion.ID.String()+":24h0m0s", reminder.IdempotencyKey)
+	assert.True(t, deletion.ScheduledDate.Add(-24*time.Hour).Equal(reminder.SendAt))
+
+	// Test case 2: A repeated request is rejected without a second deletion or reminders
+	assert.ErrorIs(t, uc.Execute(ctx, user.ID), domain.ErrDeletionAlreadyPending)
+	assert.Len(t, deletionRepo.deletions, 1)
+	assert.Len(t, eventBus.events, 4)
+
+	// Test case 3: Cancelling the deletion cancels its reminders
+	cancel := NewCancelAccountDeletion(newFakeUserRepository(user), deletionRepo, cycleRepo, &fakeActionLogRepository{}, eventBus, policy)
+	require.NoError(t, cancel.Execute(ctx, *deletion.RecoveryToken))
+	var cancelRequest events.EmailCancelRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[6].Data, &cancelRequest))
+	assert.Equal(t, "email.cancel", eventBus.events[6].Subject)
+	assert.Equal(t, reminder.IdempotencyKey, cancelRequest.IdempotencyKey)
+
+	// Test case 4: A new request right after a cancellation is rejected
+	err := uc.Execute(ctx, user.ID)
+	assert.ErrorIs(t, err, domain.ErrDeletionCooldown)
+	var cycleErr *domain.DeletionCycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Len(t, deletionRepo.deletions, 1)
+}
+
+func TestDeleteUser_Execute_DeletedUser(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	user.IsDeleted = true
+	deletionRepo := &fakeUserDeletionRepository{}
+	policy := domain.DeletionPolicy{GracePeriod: 90 * 24 * time.Hour, DailyCapacity: 1}
+	uc := NewDeleteUser(newFakeUserRepository(user), deletionRepo, &fakeEventBus{}, "", &fakeUserDeletionCycleRepository{}, policy)
+
+	assert.ErrorIs(t, uc.Execute(ctx, user.ID), domain.ErrUserDeleted)
+	assert.Empty(t, deletionRepo.deletions)
+}
+
+func TestDeleteUser_Execute_CapacityOverflow(t *testing.T) {
+	ctx := context.Background()
+	first := newVerifiedUser(t, "password123")
+	second := newVerifiedUser(t, "password123")
+	deletionRepo := &fakeUserDeletionRepository{}
+	policy := domain.DeletionPolicy{GracePeriod: 90 * 24 * time.Hour, DailyCapacity: 1}
+	uc := NewDeleteUser(newFakeUserRepository(first, second), deletionRepo, &fakeEventBus{}, "", &fakeUserDeletionCycleRepository{}, policy)
+
+	require.NoError(t, uc.Execute(ctx, first.ID))
+	require.NoError(t, uc.Execute(ctx, second.ID))
+
+	// The second request is queued for the next day instead of being rejected
+	require.Len(t, deletionRepo.deletions, 2)
+	assert.Equal(t, deletionRepo.deletions[0].ScheduledDate.AddDate(0, 0, 1), deletionRepo.deletions[1].ScheduledDate)
+	assert.Equal(t, deletionRepo.deletions[1].ScheduledDate, *second.DeletionDueAt)
+}
@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
+
+// RequestEmailChange is a use case for changing the email address of a user. The new address
+// only replaces the current one once it is confirmed with the link sent to it.
+type RequestEmailChange struct {
+	UserRepository  domain.UserRepository
+	TokenRepository infrastructure.TokenRepository
+	EventBus        domain.EventBus
+	AppURL          string
+}
+
+// NewRequestEmailChange creates a new RequestEmailChange use case.
+func NewRequestEmailChange(userRepository domain.UserRepository, tokenRepository infrastructure.TokenRepository, eventBus domain.EventBus, appURL string) *RequestEmailChange {
+	return &RequestEmailChange{
+		UserRepository:  userRepository,
+		TokenRepository: tokenRepository,
+		EventBus:        eventBus,
+		AppURL:          appURL,
+	}
+}
+
+// Execute sends a verification email to newEmail with a link that confirms the change.
+func (uc *RequestEmailChange) Execute(ctx context.Context, userID uuid.UUID, newEmail string) error {
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := uc.UserRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsDeleted {
+		return domain.ErrUserDeleted
+	}
+	if err := ensureEmailAvailable(ctx, uc.UserRepository, newEmail); err != nil {
+		return err
+	}
+
+	token, err := util.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("email-change:%s", token), user.ID.String()+":"+newEmail, verificationTokenTTL); err != nil {
+		return err
+	}
+
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), newEmail, user.Locale, events.EmailTemplateVerification, events.VerificationEmailData{
+		Name:             user.Name,
+		VerificationLink: fmt.Sprintf("%s/confirm-email-change?token=%s", uc.AppURL, token),
+		ExpiresInMinutes: int(verificationTokenTTL.Minutes()),
+	})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+
+	return uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes})
+}
+
+// ConfirmEmailChange is a use case for confirming an email change with the emailed token.
+type ConfirmEmailChange struct {
+	UserRepository  domain.UserRepository
+	TokenRepository infrastructure.TokenRepository
+}
+
+// NewConfirmEmailChange creates a new ConfirmEmailChange use case.
+func NewConfirmEmailChange(userRepository domain.UserRepository, tokenRepository infrastructure.TokenRepository) *ConfirmEmailChange {
+	return &ConfirmEmailChange{
+		UserRepository:  userRepository,
+		TokenRepository: tokenRepository,
+	}
+}
+
+// Execute replaces the email address of the user the token belongs to. The token is single use.
+func (uc *ConfirmEmailChange) Execute(ctx context.Context, token string) error {
+	key := fmt.Sprintf("email-change:%s", token)
+	value, err := uc.TokenRepository.Get(ctx, key)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	userIDString, newEmail, found := strings.Cut(value, ":")
+	if !found {
+		return domain.ErrInvalidToken
+	}
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	if err := uc.TokenRepository.Del(ctx, key); err != nil {
+		return err
+	}
+
+	user, err := uc.UserRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsDeleted {
+		return domain.ErrUserDeleted
+	}
+	// The address may have been registered since the change was requested.
+	if err := ensureEmailAvailable(ctx, uc.UserRepository, newEmail); err != nil {
+		return err
+	}
+
+	user.Email = newEmail
+	user.IsEmailVerified = true
+	return uc.UserRepository.UpdateUser(ctx, user)
+}
+
+// ensureEmailAvailable returns domain.ErrEmailAlreadyInUse if a user already has email.
+func ensureEmailAvailable(ctx context.Context, userRepository domain.UserRepository, email string) error {
+	_, err := userRepository.GetUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return domain.ErrEmailAlreadyInUse
+	case errors.Is(err, domain.ErrUserNotFound):
+		return nil
+	default:
+		return err
+	}
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChange_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	other := newVerifiedUser(t, "password123")
+	other.Email = "taken@example.com"
+	userRepo := newFakeUserRepository(user, other)
+	tokenRepo := newFakeTokenRepository()
+	eventBus := &fakeEventBus{}
+	request := NewRequestEmailChange(userRepo, tokenRepo, eventBus, "https://app.example.com")
+	confirm := NewConfirmEmailChange(userRepo, tokenRepo)
+
+	// Test case 1: An address used by another account is rejected
+	assert.ErrorIs(t, request.Execute(ctx, user.ID, "taken@example.com"), domain.ErrEmailAlreadyInUse)
+	assert.Empty(t, eventBus.events)
+
+	// Test case 2: The confirmation link is sent to the new address and the email is unchanged
+	require.NoError(t, request.Execute(ctx, user.ID, " new@example.com "))
+	require.Equal(t, []string{"email.send"}, eventBus.subjects())
+	var emailRequest events.EmailSendRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[0].Data, &emailRequest))
+	assert.Equal(t, "new@example.com", emailRequest.Recipient)
+	assert.Equal(t, "test@example.com", user.Email)
+
+	var token string
+	for key := range tokenRepo.values {
+		token = strings.TrimPrefix(key, "email-change:")
+	}
+	require.NotEmpty(t, token)
+
+	// Test case 3: Confirming replaces the email and consumes the token
+	require.NoError(t, confirm.Execute(ctx, token))
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.True(t, user.IsEmailVerified)
+	assert.ErrorIs(t, confirm.Execute(ctx, token), domain.ErrInvalidToken)
+
+	// Test case 4: A change to an address registered after the request is rejected
+	tokenRepo.values["email-change:late"] = user.ID.String() + ":taken@example.com"
+	assert.ErrorIs(t, confirm.Execute(ctx, "late"), domain.ErrEmailAlreadyInUse)
+	assert.Equal(t, "new@example.com", user.Email)
+
+	// Test case 5: Unknown users cannot request a change
+	assert.ErrorIs(t, request.Execute(ctx, uuid.New(), "another@example.com"), domain.ErrUserNotFound)
+}
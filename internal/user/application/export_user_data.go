@@ -0,0 +1,132 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// ExportUserData is a use case that assembles the personal data of a user into a ZIP archive of
+// JSON files, stores it in the blob store and emails the user a time-limited download link.
+type ExportUserData struct {
+	UserDataExportRepository domain.UserDataExportRepository
+	BlobStore                domain.BlobStore
+	ActionLogRepository      domain.ActionLogRepository
+	EventBus                 domain.EventBus
+	LinkTTL                  time.Duration
+}
+
+// NewExportUserData creates a new ExportUserData use case.
+func NewExportUserData(userDataExportRepository domain.UserDataExportRepository, blobStore domain.BlobStore, actionLogRepository domain.ActionLogRepository, eventBus domain.EventBus, linkTTL time.Duration) *ExportUserData {
+	return &ExportUserData{
+		UserDataExportRepository: userDataExportRepository,
+		BlobStore:                blobStore,
+		ActionLogRepository:      actionLogRepository,
+		EventBus:                 eventBus,
+		LinkTTL:                  linkTTL,
+	}
+}
+
+// Execute handles a user.data_export.requested event. Earlier exports of the user are removed,
+// so that only the latest archive is kept.
+func (uc *ExportUserData) Execute(ctx context.Context, event events.DataExportRequestedEvent) error {
+	userID, err := uuid.Parse(event.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	exportID, err := uuid.Parse(event.ExportID)
+	if err != nil {
+		return fmt.Errorf("invalid export ID: %w", err)
+	}
+
+	export, err := uc.UserDataExportRepository.GetUserDataExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if export.Profile.IsDeleted {
+		return domain.ErrUserDeleted
+	}
+	export.Redact()
+
+	archive, err := BuildDataExportArchive(export, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build data export: %w", err)
+	}
+
+	if err := uc.BlobStore.DeletePrefix(ctx, domain.DataExportBlobPrefix(userID)); err != nil {
+		return fmt.Errorf("failed to delete previous data exports: %w", err)
+	}
+	key := domain.DataExportBlobKey(userID, exportID)
+	if err := uc.BlobStore.Put(ctx, key, bytes.NewReader(archive), "application/zip"); err != nil {
+		return fmt.Errorf("failed to store data export: %w", err)
+	}
+	downloadURL, err := uc.BlobStore.SignedURL(ctx, key, uc.LinkTTL)
+	if err != nil {
+		return fmt.Errorf("failed to sign data export link: %w", err)
+	}
+
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, userID.String(), export.Profile.Email, export.Profile.Locale, events.EmailTemplateDataExportReady, events.DataExportReadyEmailData{
+		Name:         export.Profile.Name,
+		DownloadLink: downloadURL,
+		ExpiresAt:    time.Now().Add(uc.LinkTTL).UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes}); err != nil {
+		return fmt.Errorf("failed to send data export email: %w", err)
+	}
+
+	actionLog := domain.NewActionLog(&userID, domain.ActionDataExported, map[string]any{"export_id": exportID})
+	if err := uc.ActionLogRepository.CreateActionLog(ctx, actionLog); err != nil {
+		fmt.Printf("Warning: failed to record data export for user %s: %v\n", userID, err)
+	}
+
+	return nil
+}
+
+// BuildDataExportArchive returns a ZIP archive holding one indented JSON file per kind of data.
+func BuildDataExportArchive(export *domain.UserDataExport, createdAt time.Time) ([]byte, error) {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"sessions.json", export.Sessions},
+		{"login_history.json", export.Logins},
+		{"email_sends.json", export.EmailSends},
+		{"action_logs.json", export.ActionLogs},
+		{"deletion_history.json", export.Deletions},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: createdAt})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
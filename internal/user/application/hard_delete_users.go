@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// hardDeleteBatchSize is the number of users fetched per batch.
+const hardDeleteBatchSize = 100
+
+// HardDeleteUsers is a use case that permanently removes soft-deleted users once their retention
+// period has ended: avatar and data export blobs are removed, dependent rows are deleted or anonymized, and
+// user.hard_deleted is published.
+type HardDeleteUsers struct {
+	UserHardDeleteRepository domain.UserHardDeleteRepository
+	BlobStore                domain.BlobStore
+	EventBus                 domain.EventBus
+	AnonymizationKey         []byte
+	BatchSize                int
+}
+
+// NewHardDeleteUsers creates a new HardDeleteUsers use case.
+func NewHardDeleteUsers(userHardDeleteRepository domain.UserHardDeleteRepository, blobStore domain.BlobStore, eventBus domain.EventBus, anonymizationKey []byte) *HardDeleteUsers {
+	return &HardDeleteUsers{
+		UserHardDeleteRepository: userHardDeleteRepository,
+		BlobStore:                blobStore,
+		EventBus:                 eventBus,
+		AnonymizationKey:         anonymizationKey,
+		BatchSize:                hardDeleteBatchSize,
+	}
+}
+
+// Execute hard-deletes every user due by now, processing batches until one makes no progress.
+// Users that fail stay soft-deleted and are retried on the next run.
+func (uc *HardDeleteUsers) Execute(ctx context.Context, now time.Time) error {
+	var errs []error
+	for {
+		users, err := uc.UserHardDeleteRepository.GetUsersDueForHardDeletion(ctx, now, uc.BatchSize)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		deleted := 0
+		for _, user := range users {
+			if err := uc.hardDelete(ctx, user, now); err != nil {
+				errs = append(errs, fmt.Errorf("user %s: %w", user.ID, err))
+				continue
+			}
+			deleted++
+		}
+		if deleted == 0 || ctx.Err() != nil {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func (uc *HardDeleteUsers) hardDelete(ctx context.Context, user *domain.User, now time.Time) error {
+	// Blobs cannot take part in the database transaction; deleting them first means a failed run
+	// is retried until both are gone, and deleting missing blobs is a no-op.
+	if err := uc.BlobStore.DeletePrefix(ctx, domain.AvatarBlobPrefix(user.ID)); err != nil {
+		return fmt.Errorf("failed to delete avatar: %w", err)
+	}
+	if err := uc.BlobStore.DeletePrefix(ctx, domain.DataExportBlobPrefix(user.ID)); err != nil {
+		return fmt.Errorf("failed to delete data exports: %w", err)
+	}
+
+	anonymizedRef := domain.AnonymizedUserRef(uc.AnonymizationKey, user.ID)
+	auditLog := domain.NewActionLog(nil, domain.ActionAccountHardDeleted, map[string]any{
+		"deleted_at": user.DeletedAt,
+	})
+	auditLog.AnonymizedUserRef = anonymizedRef
+
+	err := uc.UserHardDeleteRepository.HardDeleteUser(ctx, user.ID, auditLog)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		// Already hard-deleted by another worker
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	eventBytes, err := json.Marshal(events.UserHardDeletedEvent{
+		UserID:            user.ID.String(),
+		AnonymizedUserRef: anonymizedRef,
+		Timestamp:         now,
+	})
+	if err != nil {
+		return err
+	}
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "user.hard_deleted", Data: eventBytes}); err != nil {
+		fmt.Printf("Warning: Failed to publish user.hard_deleted event: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserHardDeleteRepository is an in-memory implementation of domain.UserHardDeleteRepository for testing
+type fakeUserHardDeleteRepository struct {
+	users     map[uuid.UUID]*domain.User
+	auditLogs []*domain.ActionLog
+}
+
+func (r *fakeUserHardDeleteRepository) GetUsersDueForHardDeletion(ctx context.Context, dueBy time.Time, limit int) ([]*domain.User, error) {
+	var users []*domain.User
+	for _, user := range r.users {
+		if len(users) < limit && user.IsDeleted && user.DeletionDueAt != nil && !user.DeletionDueAt.After(dueBy) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserHardDeleteRepository) HardDeleteUser(ctx context.Context, userID uuid.UUID, auditLog *domain.ActionLog) error {
+	if _, ok := r.users[userID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, userID)
+	r.auditLogs = append(r.auditLogs, auditLog)
+	return nil
+}
+
+// fakeBlobStore is an in-memory implementation of domain.BlobStore recording deleted prefixes for testing
+type fakeBlobStore struct {
+	objects         map[string][]byte
+	deletedPrefixes []string
+}
+
+func (s *fakeBlobStore) Put(ctx context.Context, key string, content io.Reader, contentType string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if s.objects == nil {
+		s.objects = make(map[string][]byte)
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *fakeBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *fakeBlobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.deletedPrefixes = append(s.deletedPrefixes, prefix)
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.objects, key)
+		}
+	}
+	return nil
+}
+
+func (s *fakeBlobStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://blobs.example.com/" + key + "?signature=test", nil
+}
+
+func TestHardDeleteUsers_Execute(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	due := newVerifiedUser(t, "password123")
+	due.IsDeleted, due.DeletionDueAt = true, &past
+	notDue := newVerifiedUser(t, "password123")
+	notDue.IsDeleted, notDue.DeletionDueAt = true, &future
+	active := newVerifiedUser(t, "password123")
+
+	repo := &fakeUserHardDeleteRepository{users: map[uuid.UUID]*domain.User{due.ID: due, notDue.ID: notDue, active.ID: active}}
+	blobStore := &fakeBlobStore{}
+	eventBus := &fakeEventBus{}
+	uc := NewHardDeleteUsers(repo, blobStore, eventBus, []byte("test-key"))
+
+	require.NoError(t, uc.Execute(ctx, now))
+
+	assert.NotContains(t, repo.users, due.ID)
+	assert.Contains(t, repo.users, notDue.ID)
+	assert.Contains(t, repo.users, active.ID)
+	assert.Equal(t, []string{domain.AvatarBlobPrefix(due.ID), domain.DataExportBlobPrefix(due.ID)}, blobStore.deletedPrefixes)
+
+	anonymizedRef := domain.AnonymizedUserRef([]byte("test-key"), due.ID)
+	require.Len(t, repo.auditLogs, 1)
+	assert.Equal(t, domain.ActionAccountHardDeleted, repo.auditLogs[0].Action)
+	assert.Nil(t, repo.auditLogs[0].UserID)
+	assert.Equal(t, anonymizedRef, repo.auditLogs[0].AnonymizedUserRef)
+
+	require.Equal(t, []string{"user.hard_deleted"}, eventBus.subjects())
+	var event events.UserHardDeletedEvent
+	require.NoError(t, json.Unmarshal(eventBus.events[0].Data, &event))
+	assert.Equal(t, due.ID.String(), event.UserID)
+	assert.Equal(t, anonymizedRef, event.AnonymizedUserRef)
+
+	// Test case 2: Running again is a no-op
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Len(t, eventBus.events, 1)
+}
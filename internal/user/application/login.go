@@ -2,28 +2,56 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
-	
+	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// revokeSessionsTokenTTL is how long the "this wasn't me" link in a new-device alert stays valid.
+	revokeSessionsTokenTTL = 7 * 24 * time.Hour
+	// loginVerificationTokenTTL is how long a step-up verification link for a flagged login stays valid.
+	loginVerificationTokenTTL = 15 * time.Minute
+)
+
 // LoginUser is a use case for logging in a user.
 type LoginUser struct {
 	UserRepository         domain.UserRepository
 	RefreshTokenRepository domain.RefreshTokenRepository
+	UserLoginRepository    domain.UserLoginRepository
+	TokenRepository        infrastructure.TokenRepository
 	TokenService           domain.TokenService
+	EventBus               domain.EventBus
+	GeoIPResolver          domain.GeoIPResolver
+	TravelDetector         *domain.ImpossibleTravelDetector
+	AppURL                 string
 }
 
 // NewLoginUser creates a new LoginUser use case.
-func NewLoginUser(userRepository domain.UserRepository, refreshTokenRepository domain.RefreshTokenRepository, tokenService domain.TokenService) *LoginUser {
+// geoIPResolver may be nil, in which case logins are not geolocated and impossible travel is not checked.
+func NewLoginUser(userRepository domain.UserRepository, refreshTokenRepository domain.RefreshTokenRepository, userLoginRepository domain.UserLoginRepository, tokenRepository infrastructure.TokenRepository, tokenService domain.TokenService, eventBus domain.EventBus, geoIPResolver domain.GeoIPResolver, travelDetector *domain.ImpossibleTravelDetector, appURL string) *LoginUser {
+	if travelDetector == nil {
+		travelDetector = domain.NewImpossibleTravelDetector(domain.DefaultMaxTravelSpeedKmh)
+	}
 	return &LoginUser{
 		UserRepository:         userRepository,
 		RefreshTokenRepository: refreshTokenRepository,
+		UserLoginRepository:    userLoginRepository,
+		TokenRepository:        tokenRepository,
 		TokenService:           tokenService,
+		EventBus:               eventBus,
+		GeoIPResolver:          geoIPResolver,
+		TravelDetector:         travelDetector,
+		AppURL:                 appURL,
 	}
 }
 
@@ -33,49 +61,273 @@ type LoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// loginChallenge is stored while a flagged login waits for step-up verification by email.
+type loginChallenge struct {
+	UserID    string              `json:"user_id"`
+	IPAddress string              `json:"ip_address"`
+	UserAgent string              `json:"user_agent"`
+	Location  *domain.GeoLocation `json:"location,omitempty"`
+}
+
 // Execute logs in a user and returns an access token and a refresh token.
+// If the login implies impossible travel, no tokens are issued: a verification email is sent
+// and domain.ErrLoginVerificationRequired is returned.
 func (uc *LoginUser) Execute(ctx context.Context, email, password, ipAddress, userAgent string) (*LoginResponse, error) {
 	user, err := uc.UserRepository.GetUserByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidCredentials
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, err
+		recordLogin(ctx, uc.UserLoginRepository, newUserLogin(user.ID, ipAddress, userAgent, nil, false))
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	if !user.IsEmailVerified {
-		return nil, errors.New("email not verified")
+		return nil, domain.ErrEmailNotVerified
 	}
 
 	if user.IsDeleted {
-		return nil, errors.New("user is deleted")
+		return nil, domain.ErrUserDeleted
+	}
+
+	location := uc.lookupLocation(ipAddress)
+
+	if uc.isImpossibleTravel(ctx, user.ID, location) {
+		login := newUserLogin(user.ID, ipAddress, userAgent, location, false)
+		login.Suspicious = true
+		recordLogin(ctx, uc.UserLoginRepository, login)
+
+		if err := uc.requestLoginVerification(ctx, user, ipAddress, userAgent, location); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrLoginVerificationRequired
 	}
 
-	accessToken, err := uc.TokenService.CreateAccessToken(ctx, user)
+	newDevice, err := uc.isNewDevice(ctx, user.ID, ipAddress, userAgent)
+	if err != nil {
+		// Log the error but don't fail the login process
+		fmt.Printf("Warning: Failed to check login history: %v\n", err)
+	}
+
+	response, err := issueLoginTokens(ctx, uc.TokenService, uc.RefreshTokenRepository, user, domain.NewAuthentication(domain.AuthMethodPassword), ipAddress, userAgent, location)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := uc.TokenService.CreateRefreshToken(ctx, user)
+	recordLogin(ctx, uc.UserLoginRepository, newUserLogin(user.ID, ipAddress, userAgent, location, true))
+
+	if newDevice {
+		if err := uc.notifyNewDevice(ctx, user, ipAddress, userAgent, location); err != nil {
+			// Log the error but don't fail the login process
+			fmt.Printf("Warning: Failed to send new device alert: %v\n", err)
+		}
+	}
+
+	return response, nil
+}
+
+// lookupLocation resolves the IP address to a location, returning nil when unavailable.
+func (uc *LoginUser) lookupLocation(ipAddress string) *domain.GeoLocation {
+	if uc.GeoIPResolver == nil {
+		return nil
+	}
+	location, err := uc.GeoIPResolver.Lookup(ipAddress)
+	if err != nil {
+		if !errors.Is(err, domain.ErrLocationNotFound) {
+			fmt.Printf("Warning: Failed to geolocate IP address %s: %v\n", ipAddress, err)
+		}
+		return nil
+	}
+	return location
+}
+
+// isImpossibleTravel compares the location with the user's last successful login.
+func (uc *LoginUser) isImpossibleTravel(ctx context.Context, userID uuid.UUID, location *domain.GeoLocation) bool {
+	if location == nil {
+		return false
+	}
+
+	lastLogin, err := uc.UserLoginRepository.GetLastSuccessfulLogin(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrUserLoginNotFound) {
+			fmt.Printf("Warning: Failed to get last login: %v\n", err)
+		}
+		return false
+	}
+
+	return uc.TravelDetector.IsImpossible(lastLogin.Location(), lastLogin.CreatedAt, location, time.Now())
+}
+
+// isNewDevice reports whether the IP/user-agent pair was never used by the user before.
+// The very first login of an account is not considered a new device.
+func (uc *LoginUser) isNewDevice(ctx context.Context, userID uuid.UUID, ipAddress, userAgent string) (bool, error) {
+	hasLogin, err := uc.UserLoginRepository.HasSuccessfulLogin(ctx, userID)
+	if err != nil || !hasLogin {
+		return false, err
+	}
+
+	knownDevice, err := uc.UserLoginRepository.HasSuccessfulLoginFromDevice(ctx, userID, ipAddress, userAgent)
+	if err != nil {
+		return false, err
+	}
+	return !knownDevice, nil
+}
+
+// requestLoginVerification stores a login challenge and emails the user a link to complete the login.
+func (uc *LoginUser) requestLoginVerification(ctx context.Context, user *domain.User, ipAddress, userAgent string, location *domain.GeoLocation) error {
+	token, err := util.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	challengeBytes, err := json.Marshal(loginChallenge{
+		UserID:    user.ID.String(),
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Location:  location,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("login-verification:%s", token), string(challengeBytes), loginVerificationTokenTTL); err != nil {
+		return err
+	}
+
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateLoginVerification, events.LoginVerificationEmailData{
+		SignIn:      signInDetails(ipAddress, userAgent, location),
+		ConfirmLink: fmt.Sprintf("%s/verify-login?token=%s", uc.AppURL, token),
+	})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+
+	return uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes})
+}
+
+// notifyNewDevice publishes the new device event and sends the security alert email.
+func (uc *LoginUser) notifyNewDevice(ctx context.Context, user *domain.User, ipAddress, userAgent string, location *domain.GeoLocation) error {
+	now := time.Now()
+
+	newDeviceEvent := events.UserLoginNewDeviceEvent{
+		UserID:    user.ID.String(),
+		Email:     user.Email,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Timestamp: now,
+	}
+	if location != nil {
+		newDeviceEvent.Country = location.Country
+		newDeviceEvent.City = location.City
+	}
+	newDeviceEventBytes, err := json.Marshal(newDeviceEvent)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "user.login.new_device", Data: newDeviceEventBytes}); err != nil {
+		return err
+	}
+
+	token, err := util.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("revoke-sessions:%s", token), user.ID.String(), revokeSessionsTokenTTL); err != nil {
+		return err
+	}
+
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateNewSignIn, events.NewSignInEmailData{
+		SignIn:             signInDetails(ipAddress, userAgent, location),
+		SignedInAt:         now.UTC(),
+		RevokeSessionsLink: fmt.Sprintf("%s/revoke-sessions?token=%s", uc.AppURL, token),
+	})
+	if err != nil {
+		return err
+	}
+	emailDataBytes, err := json.Marshal(emailRequest)
+	if err != nil {
+		return err
+	}
+
+	return uc.EventBus.Publish(ctx, &domain.Event{Subject: "email.send", Data: emailDataBytes})
+}
+
+// issueLoginTokens creates an access token and a refresh token session for the user.
+func issueLoginTokens(ctx context.Context, tokenService domain.TokenService, refreshTokenRepository domain.RefreshTokenRepository, user *domain.User, authentication domain.Authentication, ipAddress, userAgent string, location *domain.GeoLocation) (*LoginResponse, error) {
+	accessToken, err := tokenService.CreateAccessToken(ctx, user, authentication)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := tokenService.CreateRefreshToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
 	refreshTokenEntity := &domain.RefreshToken{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Token:           refreshToken,
+		ExpiresAt:       time.Now().Add(7 * 24 * time.Hour),
+		CreatedAt:       time.Now(),
+		Revoked:         false,
+		IPAddress:       ipAddress,
+		UserAgent:       userAgent,
+		AuthenticatedAt: authentication.Time,
+		AuthMethods:     authentication.Methods,
+	}
+	if location != nil {
+		refreshTokenEntity.Country = location.Country
+		refreshTokenEntity.City = location.City
+	}
+
+	if err := refreshTokenRepository.CreateRefreshToken(ctx, refreshTokenEntity); err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// newUserLogin builds a login history entry.
+func newUserLogin(userID uuid.UUID, ipAddress, userAgent string, location *domain.GeoLocation, success bool) *domain.UserLogin {
+	login := &domain.UserLogin{
 		ID:        uuid.New(),
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
-		CreatedAt: time.Now(),
-		Revoked:   false,
+		UserID:    userID,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+		Success:   success,
 	}
+	login.SetLocation(location)
+	return login
+}
 
-	if err := uc.RefreshTokenRepository.CreateRefreshToken(ctx, refreshTokenEntity); err != nil {
-		return nil, err
+// recordLogin stores the login attempt in the login history.
+func recordLogin(ctx context.Context, userLoginRepository domain.UserLoginRepository, login *domain.UserLogin) {
+	if err := userLoginRepository.CreateUserLogin(ctx, login); err != nil {
+		// Log the error but don't fail the login process
+		fmt.Printf("Warning: Failed to record user login: %v\n", err)
 	}
+}
 
-	return &LoginResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+// signInDetails describes a sign-in for emails.
+func signInDetails(ipAddress, userAgent string, location *domain.GeoLocation) events.SignInDetails {
+	details := events.SignInDetails{Device: userAgent, IPAddress: ipAddress}
+	if location != nil {
+		details.Location = location.Country
+		if location.City != "" {
+			details.Location = fmt.Sprintf("%s, %s", location.City, location.Country)
+		}
+	}
+	return details
 }
@@ -0,0 +1,365 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserRepository is an in-memory implementation of domain.UserRepository for testing
+type fakeUserRepository struct {
+	users map[uuid.UUID]*domain.User
+}
+
+func newFakeUserRepository(users ...*domain.User) *fakeUserRepository {
+	r := &fakeUserRepository{users: make(map[uuid.UUID]*domain.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *fakeUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	delete(r.users, id)
+	return nil
+}
+
+// fakeRefreshTokenRepository is an in-memory implementation of domain.RefreshTokenRepository for testing
+type fakeRefreshTokenRepository struct {
+	tokens []*domain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) GetRefreshTokenByToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+	for _, t := range r.tokens {
+		if t.Token == token {
+			return t, nil
+		}
+	}
+	return nil, domain.ErrRefreshTokenNotFound
+}
+
+func (r *fakeRefreshTokenRepository) UpdateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenID uuid.UUID) error {
+	for _, t := range r.tokens {
+		if t.ID == tokenID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	for _, t := range r.tokens {
+		if t.UserID == userID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+// fakeUserLoginRepository is an in-memory implementation of domain.UserLoginRepository for testing
+type fakeUserLoginRepository struct {
+	logins []*domain.UserLogin
+}
+
+func (r *fakeUserLoginRepository) CreateUserLogin(ctx context.Context, login *domain.UserLogin) error {
+	r.logins = append(r.logins, login)
+	return nil
+}
+
+func (r *fakeUserLoginRepository) GetUserLoginsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.UserLogin, error) {
+	var result []*domain.UserLogin
+	for _, l := range r.logins {
+		if l.UserID == userID {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeUserLoginRepository) GetLastSuccessfulLogin(ctx context.Context, userID uuid.UUID) (*domain.UserLogin, error) {
+	for i := len(r.logins) - 1; i >= 0; i-- {
+		if r.logins[i].UserID == userID && r.logins[i].Success {
+			return r.logins[i], nil
+		}
+	}
+	return nil, domain.ErrUserLoginNotFound
+}
+
+func (r *fakeUserLoginRepository) CountUserLoginsByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	logins, _ := r.GetUserLoginsByUserID(ctx, userID, 0, 0)
+	return len(logins), nil
+}
+
+func (r *fakeUserLoginRepository) HasSuccessfulLogin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	for _, l := range r.logins {
+		if l.UserID == userID && l.Success {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeUserLoginRepository) HasSuccessfulLoginFromDevice(ctx context.Context, userID uuid.UUID, ipAddress, userAgent string) (bool, error) {
+	for _, l := range r.logins {
+		if l.UserID == userID && l.Success && l.IPAddress == ipAddress && l.UserAgent == userAgent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeGeoIPResolver resolves IP addresses from a fixed table for testing
+type fakeGeoIPResolver map[string]*domain.GeoLocation
+
+func (r fakeGeoIPResolver) Lookup(ipAddress string) (*domain.GeoLocation, error) {
+	if location, ok := r[ipAddress]; ok {
+		return location, nil
+	}
+	return nil, domain.ErrLocationNotFound
+}
+
+// fakeTokenRepository is an in-memory implementation of infrastructure.TokenRepository for testing
+type fakeTokenRepository struct {
+	values map[string]string
+}
+
+func newFakeTokenRepository() *fakeTokenRepository {
+	return &fakeTokenRepository{values: make(map[string]string)}
+}
+
+func (r *fakeTokenRepository) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	r.values[key] = value.(string)
+	return nil
+}
+
+func (r *fakeTokenRepository) Get(ctx context.Context, key string) (string, error) {
+	if v, ok := r.values[key]; ok {
+		return v, nil
+	}
+	return "", errors.New("key not found")
+}
+
+func (r *fakeTokenRepository) Del(ctx context.Context, key string) error {
+	delete(r.values, key)
+	return nil
+}
+
+// fakeTokenService is a stub implementation of domain.TokenService for testing
+type fakeTokenService struct{}
+
+func (s *fakeTokenService) CreateAccessToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return "access-" + user.ID.String(), nil
+}
+
+func (s *fakeTokenService) CreateElevatedToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return "elevated-" + user.ID.String(), nil
+}
+
+func (s *fakeTokenService) CreateRefreshToken(ctx context.Context, user *domain.User) (string, error) {
+	return "refresh-" + uuid.NewString(), nil
+}
+
+func (s *fakeTokenService) VerifyToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	return uuid.Nil, errors.New("not implemented")
+}
+
+// fakeEventBus records published events for testing
+type fakeEventBus struct {
+	events []*domain.Event
+}
+
+func (b *fakeEventBus) Publish(ctx context.Context, event *domain.Event) error {
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *fakeEventBus) subjects() []string {
+	var subjects []string
+	for _, e := range b.events {
+		subjects = append(subjects, e.Subject)
+	}
+	return subjects
+}
+
+func newVerifiedUser(t *testing.T, password string) *domain.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &domain.User{
+		ID:              uuid.New(),
+		Name:            "Test User",
+		Email:           "test@example.com",
+		PasswordHash:    string(hash),
+		IsEmailVerified: true,
+	}
+}
+
+func TestLoginUser_NewDeviceAlert(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	loginRepo := &fakeUserLoginRepository{}
+	tokenRepo := newFakeTokenRepository()
+	eventBus := &fakeEventBus{}
+	uc := NewLoginUser(newFakeUserRepository(user), &fakeRefreshTokenRepository{}, loginRepo, tokenRepo, &fakeTokenService{}, eventBus, nil, nil, "http://localhost:8080")
+
+	// Test case 1: First login ever does not trigger an alert
+	_, err := uc.Execute(ctx, user.Email, "password123", "10.0.0.1", "Firefox")
+	require.NoError(t, err)
+	assert.Empty(t, eventBus.events)
+	assert.Len(t, loginRepo.logins, 1)
+
+	// Test case 2: Login from a known device does not trigger an alert
+	_, err = uc.Execute(ctx, user.Email, "password123", "10.0.0.1", "Firefox")
+	require.NoError(t, err)
+	assert.Empty(t, eventBus.events)
+
+	// Test case 3: Login from a new device publishes the event and the security email
+	_, err = uc.Execute(ctx, user.Email, "password123", "10.0.0.2", "Chrome")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user.login.new_device", "email.send"}, eventBus.subjects())
+
+	var emailRequest events.EmailSendRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[1].Data, &emailRequest))
+	assert.Equal(t, user.Email, emailRequest.Recipient)
+	assert.Equal(t, events.EmailTemplateNewSignIn, emailRequest.TemplateName)
+	var alert events.NewSignInEmailData
+	require.NoError(t, json.Unmarshal(emailRequest.TemplateData, &alert))
+	assert.Equal(t, events.SignInDetails{Device: "Chrome", IPAddress: "10.0.0.2"}, alert.SignIn)
+	assert.Contains(t, alert.RevokeSessionsLink, "/revoke-sessions?token=")
+	assert.Len(t, tokenRepo.values, 1)
+}
+
+func TestLoginUser_FailedLoginIsRecorded(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	loginRepo := &fakeUserLoginRepository{}
+	uc := NewLoginUser(newFakeUserRepository(user), &fakeRefreshTokenRepository{}, loginRepo, newFakeTokenRepository(), &fakeTokenService{}, &fakeEventBus{}, nil, nil, "")
+
+	_, err := uc.Execute(ctx, user.Email, "wrong-password", "10.0.0.1", "Firefox")
+	assert.Error(t, err)
+	require.Len(t, loginRepo.logins, 1)
+	assert.False(t, loginRepo.logins[0].Success)
+}
+
+func TestRevokeSessions_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	refreshTokenRepo := &fakeRefreshTokenRepository{tokens: []*domain.RefreshToken{{ID: uuid.New(), UserID: userID, Token: "t1"}}}
+	tokenRepo := newFakeTokenRepository()
+	tokenRepo.values["revoke-sessions:abc"] = userID.String()
+	uc := NewRevokeSessions(refreshTokenRepo, tokenRepo)
+
+	// Test case 1: Valid token revokes all sessions and is consumed
+	require.NoError(t, uc.Execute(ctx, "abc"))
+	assert.True(t, refreshTokenRepo.tokens[0].Revoked)
+	assert.Empty(t, tokenRepo.values)
+
+	// Test case 2: Token cannot be reused
+	assert.Error(t, uc.Execute(ctx, "abc"))
+}
+
+func TestLoginUser_ImpossibleTravelRequiresVerification(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	userRepo := newFakeUserRepository(user)
+	refreshTokenRepo := &fakeRefreshTokenRepository{}
+	loginRepo := &fakeUserLoginRepository{}
+	tokenRepo := newFakeTokenRepository()
+	eventBus := &fakeEventBus{}
+	geoIP := fakeGeoIPResolver{
+		"200.0.0.1": {Country: "BR", City: "São Paulo", Latitude: -23.55, Longitude: -46.63},
+		"81.0.0.1":  {Country: "DE", City: "Berlin", Latitude: 52.52, Longitude: 13.40},
+	}
+	uc := NewLoginUser(userRepo, refreshTokenRepo, loginRepo, tokenRepo, &fakeTokenService{}, eventBus, geoIP, nil, "http://localhost:8080")
+
+	// Test case 1: Login from São Paulo is enriched with its location
+	_, err := uc.Execute(ctx, user.Email, "password123", "200.0.0.1", "Firefox")
+	require.NoError(t, err)
+	require.Len(t, loginRepo.logins, 1)
+	assert.Equal(t, "BR", loginRepo.logins[0].Country)
+	assert.Equal(t, "São Paulo", refreshTokenRepo.tokens[0].City)
+
+	// Test case 2: Login from Berlin minutes later is held for verification
+	response, err := uc.Execute(ctx, user.Email, "password123", "81.0.0.1", "Firefox")
+	assert.ErrorIs(t, err, domain.ErrLoginVerificationRequired)
+	assert.Nil(t, response)
+	assert.Len(t, refreshTokenRepo.tokens, 1)
+	require.Len(t, loginRepo.logins, 2)
+	assert.True(t, loginRepo.logins[1].Suspicious)
+	assert.False(t, loginRepo.logins[1].Success)
+	assert.Equal(t, []string{"email.send"}, eventBus.subjects())
+
+	// Test case 3: The emailed token completes the login
+	var token string
+	for key := range tokenRepo.values {
+		token = key[len("login-verification:"):]
+	}
+	verify := NewVerifyLogin(userRepo, refreshTokenRepo, loginRepo, tokenRepo, &fakeTokenService{})
+	response, verifiedUser, err := verify.Execute(ctx, token)
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.AccessToken)
+	assert.Equal(t, user.ID, verifiedUser.ID)
+	assert.Equal(t, "DE", refreshTokenRepo.tokens[1].Country)
+
+	// Test case 4: The token cannot be reused
+	_, _, err = verify.Execute(ctx, token)
+	assert.Error(t, err)
+}
+
+func TestReauthenticate_Execute(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	uc := NewReauthenticate(newFakeUserRepository(user), &fakeTokenService{})
+
+	// Test case 1: Correct password returns an elevated token
+	token, err := uc.Execute(ctx, user.ID, "password123")
+	require.NoError(t, err)
+	assert.Equal(t, "elevated-"+user.ID.String(), token)
+
+	// Test case 2: Wrong password is rejected
+	_, err = uc.Execute(ctx, user.ID, "wrong-password")
+	assert.Error(t, err)
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/jefersonprimer/chatear-backend/shared/util"
 )
 
+// passwordResetTokenTTL is how long a password reset link stays valid.
+const passwordResetTokenTTL = 15 * time.Minute
+
 // PasswordRecovery is a use case for recovering a user's password.
 type PasswordRecovery struct {
 	UserRepository  domain.UserRepository
@@ -42,14 +45,17 @@ func (uc *PasswordRecovery) Execute(ctx context.Context, email string) error {
 		return err
 	}
 
-	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("password-reset:%s", token), user.ID.String(), 15*time.Minute); err != nil {
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("password-reset:%s", token), user.ID.String(), passwordResetTokenTTL); err != nil {
 		return err
 	}
 
-	emailRequest := events.EmailSendRequest{
-		Recipient: user.Email,
-		Subject:   "Password Reset",
-		Body:      fmt.Sprintf("Click here to reset your password: %s/reset-password?token=%s", uc.AppURL, token),
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplatePasswordReset, events.PasswordResetEmailData{
+		Name:             user.Name,
+		ResetLink:        fmt.Sprintf("%s/reset-password?token=%s", uc.AppURL, token),
+		ExpiresInMinutes: int(passwordResetTokenTTL / time.Minute),
+	})
+	if err != nil {
+		return err
 	}
 	emailDataBytes, err := json.Marshal(emailRequest)
 	if err != nil {
@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// deletionBatchSize is the number of deletions locked and processed per batch.
+const deletionBatchSize = 100
+
+// ProcessUserDeletions is a use case that advances due user deletions through their states:
+// pending deletions are warned at each offset of the warning schedule, deletions past their date
+// are scheduled, and scheduled deletions are executed by soft-deleting the user.
+type ProcessUserDeletions struct {
+	UserDeletionRepository domain.UserDeletionRepository
+	EventBus               domain.EventBus
+	Policy                 domain.DeletionPolicy
+	BatchSize              int
+}
+
+// NewProcessUserDeletions creates a new ProcessUserDeletions use case.
+func NewProcessUserDeletions(userDeletionRepository domain.UserDeletionRepository, eventBus domain.EventBus, policy domain.DeletionPolicy) *ProcessUserDeletions {
+	return &ProcessUserDeletions{
+		UserDeletionRepository: userDeletionRepository,
+		EventBus:               eventBus,
+		Policy:                 policy,
+		BatchSize:              deletionBatchSize,
+	}
+}
+
+// Execute runs every transition once, processing batches until no due deletion is left.
+// Reminders are processed from the earliest to the latest, so a deletion whose earlier reminders
+// were missed (e.g. while the worker was down) is only warned once per run.
+func (uc *ProcessUserDeletions) Execute(ctx context.Context, now time.Time) error {
+	pending := []domain.DeletionStatus{domain.DeletionStatusQueued, domain.DeletionStatusWarned}
+
+	var errs []error
+	for _, offset := range uc.Policy.Reminders() {
+		filter := domain.UserDeletionFilter{Statuses: pending, ScheduledBy: now.Add(offset), NotWarnedWithin: offset}
+		if err := uc.processAll(ctx, filter, uc.warn(now)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to warn user deletions: %w", err))
+		}
+	}
+	if err := uc.processAll(ctx, domain.UserDeletionFilter{Statuses: pending, ScheduledBy: now}, uc.schedule(now)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to schedule user deletions: %w", err))
+	}
+	executable := []domain.DeletionStatus{domain.DeletionStatusScheduled}
+	if err := uc.processAll(ctx, domain.UserDeletionFilter{Statuses: executable, ScheduledBy: now}, uc.execute(now)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to execute user deletions: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// processAll processes batches of deletions selected by filter until a batch makes no progress.
+// Deletions whose handler fails stay in their status and are retried on the next run.
+func (uc *ProcessUserDeletions) processAll(ctx context.Context, filter domain.UserDeletionFilter, handle domain.UserDeletionHandler) error {
+	var errs []error
+	for {
+		processed, err := uc.UserDeletionRepository.ProcessUserDeletions(ctx, filter, uc.BatchSize, handle)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if processed == 0 || ctx.Err() != nil {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// warn records that a reminder of a pending deletion is due. The reminder emails themselves are
+// scheduled with the notification worker when the deletion is requested.
+func (uc *ProcessUserDeletions) warn(now time.Time) domain.UserDeletionHandler {
+	return func(ctx context.Context, deletion *domain.UserDeletion, users domain.UserRepository) error {
+		return deletion.Warn(now)
+	}
+}
+
+// schedule marks a pending deletion whose date has passed as ready to be executed.
+func (uc *ProcessUserDeletions) schedule(now time.Time) domain.UserDeletionHandler {
+	return func(ctx context.Context, deletion *domain.UserDeletion, users domain.UserRepository) error {
+		return deletion.TransitionTo(domain.DeletionStatusScheduled, now)
+	}
+}
+
+// execute soft-deletes the user of a scheduled deletion, in the transaction that saves the deletion.
+// Users that are already deleted are not updated again, so a retried execution has no further effect.
+func (uc *ProcessUserDeletions) execute(now time.Time) domain.UserDeletionHandler {
+	return func(ctx context.Context, deletion *domain.UserDeletion, users domain.UserRepository) error {
+		user, err := users.GetUserByID(ctx, deletion.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return deletion.TransitionTo(domain.DeletionStatusCancelled, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !user.IsDeleted {
+			deletionDueAt := now.Add(uc.Policy.HardDeleteRetention)
+			user.IsDeleted = true
+			user.DeletedAt = &now
+			user.DeletionDueAt = &deletionDueAt
+			if err := users.UpdateUser(ctx, user); err != nil {
+				return err
+			}
+
+			deletedEventBytes, err := json.Marshal(events.UserDeletedEvent{
+				UserID:        user.ID.String(),
+				DeletionID:    deletion.ID.String(),
+				DeletionDueAt: deletionDueAt,
+				Timestamp:     now,
+			})
+			if err != nil {
+				return err
+			}
+			if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "user.deleted", Data: deletedEventBytes}); err != nil {
+				fmt.Printf("Warning: Failed to publish user.deleted event: %v\n", err)
+			}
+		}
+
+		return deletion.TransitionTo(domain.DeletionStatusExecuted, now)
+	}
+}
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserDeletionRepository is an in-memory implementation of domain.UserDeletionRepository for testing
+type fakeUserDeletionRepository struct {
+	deletions []*domain.UserDeletion
+	capacity  map[time.Time]int
+	users     domain.UserRepository
+}
+
+func (r *fakeUserDeletionRepository) CreateUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion) error {
+	r.deletions = append(r.deletions, userDeletion)
+	return nil
+}
+
+func (r *fakeUserDeletionRepository) ScheduleUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion, dailyCapacity int) error {
+	for _, d := range r.deletions {
+		if d.UserID == userDeletion.UserID && !d.Status.IsTerminal() {
+			return domain.ErrDeletionAlreadyPending
+		}
+	}
+	if r.capacity == nil {
+		r.capacity = make(map[time.Time]int)
+	}
+	day := userDeletion.ScheduledDate.Truncate(24 * time.Hour)
+	for r.capacity[day] >= dailyCapacity {
+		day = day.AddDate(0, 0, 1)
+		if day.Sub(userDeletion.ScheduledDate) > domain.MaxDeletionScheduleDelay {
+			return domain.ErrDeletionLimitExceeded
+		}
+	}
+	r.capacity[day]++
+	userDeletion.ScheduledDate = day
+	return r.CreateUserDeletion(ctx, userDeletion)
+}
+
+func (r *fakeUserDeletionRepository) GetUserDeletionsByDate(ctx context.Context, date time.Time) ([]*domain.UserDeletion, error) {
+	return r.deletions, nil
+}
+
+func (r *fakeUserDeletionRepository) GetUserDeletionByRecoveryToken(ctx context.Context, token string) (*domain.UserDeletion, error) {
+	for _, d := range r.deletions {
+		if d.RecoveryToken != nil && *d.RecoveryToken == token {
+			deletion := *d
+			return &deletion, nil
+		}
+	}
+	return nil, domain.ErrUserDeletionNotFound
+}
+
+func (r *fakeUserDeletionRepository) UpdateUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion, expectedStatus domain.DeletionStatus) error {
+	for _, d := range r.deletions {
+		if d.ID == userDeletion.ID {
+			if d.Status != expectedStatus {
+				return domain.ErrInvalidDeletionTransition
+			}
+			if userDeletion.Status == domain.DeletionStatusCancelled && expectedStatus != domain.DeletionStatusCancelled {
+				if day := d.ScheduledDate.Truncate(24 * time.Hour); r.capacity[day] > 0 {
+					r.capacity[day]--
+				}
+			}
+			*d = *userDeletion
+			return nil
+		}
+	}
+	return domain.ErrUserDeletionNotFound
+}
+
+func (r *fakeUserDeletionRepository) ProcessUserDeletions(ctx context.Context, filter domain.UserDeletionFilter, limit int, handle domain.UserDeletionHandler) (int, error) {
+	processed := 0
+	for _, d := range r.deletions {
+		if processed == limit || !filter.Matches(d) {
+			continue
+		}
+		// Handlers work on a copy, as the Postgres repository only saves successful ones
+		deletion := *d
+		if err := handle(ctx, &deletion, r.users); err != nil {
+			return processed, err
+		}
+		*d = deletion
+		processed++
+	}
+	return processed, nil
+}
+
+func TestProcessUserDeletions_Execute(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	user := newVerifiedUser(t, "password123")
+	deletion := &domain.UserDeletion{ID: uuid.New(), UserID: user.ID, ScheduledDate: now.Add(30 * 24 * time.Hour), Status: domain.DeletionStatusQueued}
+	deletionRepo := &fakeUserDeletionRepository{deletions: []*domain.UserDeletion{deletion}, users: newFakeUserRepository(user)}
+	eventBus := &fakeEventBus{}
+	policy := domain.DeletionPolicy{
+		WarningSchedule:     []time.Duration{24 * time.Hour, 7 * 24 * time.Hour},
+		HardDeleteRetention: 30 * 24 * time.Hour,
+	}
+	uc := NewProcessUserDeletions(deletionRepo, eventBus, policy)
+
+	// Test case 1: Deletions outside the warning period are left alone
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Equal(t, domain.DeletionStatusQueued, deletion.Status)
+	assert.Empty(t, eventBus.events)
+
+	// Test case 2: Within the warning period the deletion is warned once, without an email, as
+	// the reminders were scheduled when the deletion was requested
+	now = deletion.ScheduledDate.Add(-2 * 24 * time.Hour)
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Equal(t, domain.DeletionStatusWarned, deletion.Status)
+	require.NotNil(t, deletion.WarnedAt)
+	warnedAt := *deletion.WarnedAt
+	require.NoError(t, uc.Execute(ctx, now.Add(time.Hour)))
+	assert.Equal(t, warnedAt, *deletion.WarnedAt)
+
+	// Test case 3: The deletion is warned again at the last reminder, the day before
+	now = deletion.ScheduledDate.Add(-12 * time.Hour)
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Equal(t, now, *deletion.WarnedAt)
+	assert.Empty(t, eventBus.events)
+
+	// Test case 4: Past the scheduled date the user is soft-deleted
+	now = deletion.ScheduledDate.Add(time.Hour)
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Equal(t, domain.DeletionStatusExecuted, deletion.Status)
+	assert.True(t, deletion.Executed)
+	assert.True(t, user.IsDeleted)
+	require.NotNil(t, user.DeletionDueAt)
+	assert.Equal(t, now.Add(30*24*time.Hour), *user.DeletionDueAt)
+	assert.Equal(t, []string{"user.deleted"}, eventBus.subjects())
+
+	// Test case 5: Running again is a no-op
+	require.NoError(t, uc.Execute(ctx, now.Add(time.Hour)))
+	assert.Len(t, eventBus.events, 1)
+}
+
+func TestProcessUserDeletions_MissingUserIsCancelled(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	deletion := &domain.UserDeletion{ID: uuid.New(), UserID: uuid.New(), ScheduledDate: now, Status: domain.DeletionStatusQueued}
+	uc := NewProcessUserDeletions(&fakeUserDeletionRepository{deletions: []*domain.UserDeletion{deletion}, users: newFakeUserRepository()}, &fakeEventBus{}, domain.DeletionPolicy{WarningSchedule: []time.Duration{7 * 24 * time.Hour}})
+
+	require.NoError(t, uc.Execute(ctx, now))
+	assert.Equal(t, domain.DeletionStatusCancelled, deletion.Status)
+}
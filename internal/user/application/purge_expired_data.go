@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// PurgeExpiredTokens is a use case that deletes expired refresh tokens and magic links.
+type PurgeExpiredTokens struct {
+	DataRetentionRepository domain.DataRetentionRepository
+}
+
+// NewPurgeExpiredTokens creates a new PurgeExpiredTokens use case.
+func NewPurgeExpiredTokens(dataRetentionRepository domain.DataRetentionRepository) *PurgeExpiredTokens {
+	return &PurgeExpiredTokens{DataRetentionRepository: dataRetentionRepository}
+}
+
+// Execute deletes the tokens expired by now and returns how many were deleted.
+func (uc *PurgeExpiredTokens) Execute(ctx context.Context, now time.Time) (int64, error) {
+	return uc.DataRetentionRepository.DeleteExpiredTokens(ctx, now)
+}
+
+// DeleteOldLogs is a use case that deletes login history and action logs past their retention period.
+type DeleteOldLogs struct {
+	DataRetentionRepository domain.DataRetentionRepository
+	RetentionPeriod         time.Duration
+}
+
+// NewDeleteOldLogs creates a new DeleteOldLogs use case.
+func NewDeleteOldLogs(dataRetentionRepository domain.DataRetentionRepository, retentionPeriod time.Duration) *DeleteOldLogs {
+	return &DeleteOldLogs{
+		DataRetentionRepository: dataRetentionRepository,
+		RetentionPeriod:         retentionPeriod,
+	}
+}
+
+// Execute deletes the logs created more than the retention period before now and returns how many were deleted.
+func (uc *DeleteOldLogs) Execute(ctx context.Context, now time.Time) (int64, error) {
+	return uc.DataRetentionRepository.DeleteOldLogs(ctx, now.Add(-uc.RetentionPeriod))
+}
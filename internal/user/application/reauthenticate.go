@@ -0,0 +1,43 @@
+package application
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Reauthenticate is a use case for confirming the password of an already signed-in user
+// before a sensitive operation.
+type Reauthenticate struct {
+	UserRepository domain.UserRepository
+	TokenService   domain.TokenService
+}
+
+// NewReauthenticate creates a new Reauthenticate use case.
+func NewReauthenticate(userRepository domain.UserRepository, tokenService domain.TokenService) *Reauthenticate {
+	return &Reauthenticate{
+		UserRepository: userRepository,
+		TokenService:   tokenService,
+	}
+}
+
+// Execute checks the password of the user and returns a short-lived elevated access token
+// whose auth_time is now.
+func (uc *Reauthenticate) Execute(ctx context.Context, userID uuid.UUID, password string) (string, error) {
+	user, err := uc.UserRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if user.IsDeleted {
+		return "", domain.ErrUserDeleted
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return "", domain.ErrInvalidCredentials
+	}
+
+	return uc.TokenService.CreateElevatedToken(ctx, user, domain.NewAuthentication(domain.AuthMethodPassword))
+}
@@ -3,7 +3,6 @@ package application
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
@@ -11,11 +10,13 @@ import (
 	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
 	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
 	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
 	"github.com/jefersonprimer/chatear-backend/shared/util"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const defaultMaxEmailsPerDay = 2
+// verificationTokenTTL is how long an email verification link stays valid.
+const verificationTokenTTL = 15 * time.Minute
 
 // RegisterUser is a use case for registering a new user.
 type RegisterUser struct {
@@ -24,26 +25,22 @@ type RegisterUser struct {
 	TokenRepository infrastructure.TokenRepository
 	EventBus        domain.EventBus
 	AppURL          string
-	MaxEmailsPerDay int
 }
 
 // NewRegisterUser creates a new RegisterUser use case.
-func NewRegisterUser(userRepository domain.UserRepository, emailRepository domain.EmailRepository, tokenRepository infrastructure.TokenRepository, eventBus domain.EventBus, appURL string, maxEmailsPerDay int) *RegisterUser {
-	if maxEmailsPerDay == 0 {
-		maxEmailsPerDay = defaultMaxEmailsPerDay
-	}
+func NewRegisterUser(userRepository domain.UserRepository, emailRepository domain.EmailRepository, tokenRepository infrastructure.TokenRepository, eventBus domain.EventBus, appURL string) *RegisterUser {
 	return &RegisterUser{
 		UserRepository:  userRepository,
 		EmailRepository: emailRepository,
 		TokenRepository: tokenRepository,
 		EventBus:        eventBus,
 		AppURL:          appURL,
-		MaxEmailsPerDay: maxEmailsPerDay,
 	}
 }
 
-// Execute registers a new user and sends a verification email.
-func (uc *RegisterUser) Execute(ctx context.Context, name, email, password string) (*domain.User, error) {
+// Execute registers a new user with a locale preference, defaulting to i18n.DefaultLocale, and
+// sends a verification email.
+func (uc *RegisterUser) Execute(ctx context.Context, name, email, password, locale string) (*domain.User, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -54,34 +51,29 @@ func (uc *RegisterUser) Execute(ctx context.Context, name, email, password strin
 		Name:         name,
 		Email:        email,
 		PasswordHash: string(hashedPassword),
+		Locale:       i18n.Resolve(locale),
 	}
 
 	if err := uc.UserRepository.CreateUser(ctx, user); err != nil {
 		return nil, err
 	}
 
-	emails, err := uc.EmailRepository.GetEmailsByUserIDAndType(ctx, user.ID, "verification")
-	if err != nil {
-		return nil, err
-	}
-
-	if len(emails) >= uc.MaxEmailsPerDay {
-		return nil, errors.New("email limit exceeded")
-	}
-
 	token, err := util.GenerateRandomToken()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("verification:%s", token), user.ID.String(), 15*time.Minute); err != nil {
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("verification:%s", token), user.ID.String(), verificationTokenTTL); err != nil {
 		return nil, err
 	}
 
-	emailRequest := events.EmailSendRequest{
-		Recipient: user.Email,
-		Subject:   "Email Verification",
-		Body:      fmt.Sprintf("Click here to verify your email: %s/verify-email?token=%s", uc.AppURL, token),
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateVerification, events.VerificationEmailData{
+		Name:             user.Name,
+		VerificationLink: fmt.Sprintf("%s/verify-email?token=%s", uc.AppURL, token),
+		ExpiresInMinutes: int(verificationTokenTTL / time.Minute),
+	})
+	if err != nil {
+		return nil, err
 	}
 	emailDataBytes, err := json.Marshal(emailRequest)
 	if err != nil {
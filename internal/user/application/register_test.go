@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmailRepository is an in-memory implementation of domain.EmailRepository for testing
+type fakeEmailRepository struct {
+	emails []*domain.Email
+}
+
+func (r *fakeEmailRepository) CreateEmail(ctx context.Context, email *domain.Email) error {
+	r.emails = append(r.emails, email)
+	return nil
+}
+
+func TestRegisterUser_LocalePreference(t *testing.T) {
+	ctx := context.Background()
+	eventBus := &fakeEventBus{}
+	uc := NewRegisterUser(newFakeUserRepository(), &fakeEmailRepository{}, newFakeTokenRepository(), eventBus, "https://chatear.app")
+
+	// Test case 1: The locale preference is stored and used for the verification email
+	user, err := uc.Execute(ctx, "Ana", "ana@example.com", "password123", i18n.English)
+	require.NoError(t, err)
+	assert.Equal(t, i18n.English, user.Locale)
+
+	require.Equal(t, []string{"email.send", "user.registered"}, eventBus.subjects())
+	var emailRequest events.EmailSendRequest
+	require.NoError(t, json.Unmarshal(eventBus.events[0].Data, &emailRequest))
+	assert.Equal(t, i18n.English, emailRequest.Locale)
+	assert.Equal(t, events.EmailTemplateVerification, emailRequest.TemplateName)
+	var verification events.VerificationEmailData
+	require.NoError(t, json.Unmarshal(emailRequest.TemplateData, &verification))
+	assert.Equal(t, "Ana", verification.Name)
+	assert.Contains(t, verification.VerificationLink, "https://chatear.app/verify-email?token=")
+
+	// Test case 2: Without a preference the user gets the default locale
+	user, err = uc.Execute(ctx, "João", "joao@example.com", "password123", "")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.DefaultLocale, user.Locale)
+}
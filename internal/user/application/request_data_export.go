@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/shared/events"
+)
+
+// RequestDataExport is a use case for requesting an export of the personal data of a user.
+// The export is assembled asynchronously by the data export worker.
+type RequestDataExport struct {
+	UserRepository      domain.UserRepository
+	TokenRepository     infrastructure.TokenRepository
+	ActionLogRepository domain.ActionLogRepository
+	EventBus            domain.EventBus
+	Cooldown            time.Duration
+}
+
+// NewRequestDataExport creates a new RequestDataExport use case.
+func NewRequestDataExport(userRepository domain.UserRepository, tokenRepository infrastructure.TokenRepository, actionLogRepository domain.ActionLogRepository, eventBus domain.EventBus, cooldown time.Duration) *RequestDataExport {
+	return &RequestDataExport{
+		UserRepository:      userRepository,
+		TokenRepository:     tokenRepository,
+		ActionLogRepository: actionLogRepository,
+		EventBus:            eventBus,
+		Cooldown:            cooldown,
+	}
+}
+
+// Execute publishes a user.data_export.requested event for the user. Only one export may be
+// requested per cooldown, as each one reads the user's whole history.
+func (uc *RequestDataExport) Execute(ctx context.Context, userID uuid.UUID) error {
+	user, err := uc.UserRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsDeleted {
+		return domain.ErrUserDeleted
+	}
+
+	key := fmt.Sprintf("data-export:%s", userID)
+	if _, err := uc.TokenRepository.Get(ctx, key); err == nil {
+		return domain.ErrDataExportTooSoon
+	}
+
+	exportID := uuid.New()
+	eventBytes, err := json.Marshal(events.DataExportRequestedEvent{
+		ExportID:  exportID.String(),
+		UserID:    userID.String(),
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := uc.EventBus.Publish(ctx, &domain.Event{Subject: "user.data_export.requested", Data: eventBytes}); err != nil {
+		return fmt.Errorf("failed to request data export: %w", err)
+	}
+
+	if err := uc.TokenRepository.Set(ctx, key, exportID.String(), uc.Cooldown); err != nil {
+		fmt.Printf("Warning: failed to record data export request for user %s: %v\n", userID, err)
+	}
+
+	actionLog := domain.NewActionLog(&userID, domain.ActionDataExportRequested, map[string]any{"export_id": exportID})
+	if err := uc.ActionLogRepository.CreateActionLog(ctx, actionLog); err != nil {
+		fmt.Printf("Warning: failed to record data export request for user %s: %v\n", userID, err)
+	}
+
+	return nil
+}
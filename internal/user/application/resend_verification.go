@@ -3,7 +3,6 @@ package application
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
@@ -21,21 +20,16 @@ type ResendVerificationEmail struct {
 	TokenRepository infrastructure.TokenRepository
 	EventBus        domain.EventBus
 	AppURL          string
-	MaxEmailsPerDay int
 }
 
 // NewResendVerificationEmail creates a new ResendVerificationEmail use case.
-func NewResendVerificationEmail(userRepository domain.UserRepository, emailRepository domain.EmailRepository, tokenRepository infrastructure.TokenRepository, eventBus domain.EventBus, appURL string, maxEmailsPerDay int) *ResendVerificationEmail {
-	if maxEmailsPerDay == 0 {
-		maxEmailsPerDay = defaultMaxEmailsPerDay
-	}
+func NewResendVerificationEmail(userRepository domain.UserRepository, emailRepository domain.EmailRepository, tokenRepository infrastructure.TokenRepository, eventBus domain.EventBus, appURL string) *ResendVerificationEmail {
 	return &ResendVerificationEmail{
 		UserRepository:  userRepository,
 		EmailRepository: emailRepository,
 		TokenRepository: tokenRepository,
 		EventBus:        eventBus,
 		AppURL:          appURL,
-		MaxEmailsPerDay: maxEmailsPerDay,
 	}
 }
 
@@ -43,20 +37,11 @@ func NewResendVerificationEmail(userRepository domain.UserRepository, emailRepos
 func (uc *ResendVerificationEmail) Execute(ctx context.Context, email string) error {
 	user, err := uc.UserRepository.GetUserByEmail(ctx, email)
 	if err != nil {
-		return errors.New("user not found")
+		return domain.ErrUserNotFound
 	}
 
 	if user.IsEmailVerified {
-		return errors.New("email already verified")
-	}
-
-	emails, err := uc.EmailRepository.GetEmailsByUserIDAndType(ctx, user.ID, "verification")
-	if err != nil {
-		return err
-	}
-
-	if len(emails) >= uc.MaxEmailsPerDay {
-		return errors.New("email limit exceeded")
+		return domain.ErrEmailAlreadyVerified
 	}
 
 	token, err := util.GenerateRandomToken()
@@ -64,14 +49,17 @@ func (uc *ResendVerificationEmail) Execute(ctx context.Context, email string) er
 		return err
 	}
 
-	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("verification:%s", token), user.ID.String(), 15*time.Minute); err != nil {
+	if err := uc.TokenRepository.Set(ctx, fmt.Sprintf("verification:%s", token), user.ID.String(), verificationTokenTTL); err != nil {
 		return err
 	}
 
-	emailRequest := events.EmailSendRequest{
-		Recipient: user.Email,
-		Subject:   "Email Verification",
-		Body:      fmt.Sprintf("Click here to verify your email: %s/verify-email?token=%s", uc.AppURL, token),
+	emailRequest, err := events.NewTemplatedEmailSendRequest(ctx, user.ID.String(), user.Email, user.Locale, events.EmailTemplateVerification, events.VerificationEmailData{
+		Name:             user.Name,
+		VerificationLink: fmt.Sprintf("%s/verify-email?token=%s", uc.AppURL, token),
+		ExpiresInMinutes: int(verificationTokenTTL / time.Minute),
+	})
+	if err != nil {
+		return err
 	}
 	emailDataBytes, err := json.Marshal(emailRequest)
 	if err != nil {
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+)
+
+// RevokeSessions is a use case for revoking every session of a user from a "this wasn't me" link.
+type RevokeSessions struct {
+	RefreshTokenRepository domain.RefreshTokenRepository
+	TokenRepository        infrastructure.TokenRepository
+}
+
+// NewRevokeSessions creates a new RevokeSessions use case.
+func NewRevokeSessions(refreshTokenRepository domain.RefreshTokenRepository, tokenRepository infrastructure.TokenRepository) *RevokeSessions {
+	return &RevokeSessions{
+		RefreshTokenRepository: refreshTokenRepository,
+		TokenRepository:        tokenRepository,
+	}
+}
+
+// Execute validates the token and revokes all refresh tokens of the user it belongs to.
+func (uc *RevokeSessions) Execute(ctx context.Context, token string) error {
+	key := fmt.Sprintf("revoke-sessions:%s", token)
+	userIDString, err := uc.TokenRepository.Get(ctx, key)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	if err := uc.RefreshTokenRepository.RevokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.TokenRepository.Del(ctx, key)
+}
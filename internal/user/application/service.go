@@ -8,24 +8,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
 	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
-	
 )
 
 // UserApplicationService encapsulates user-related application logic.
 type UserApplicationService struct {
-	userRepo           domain.UserRepository
-	refreshTokenRepo   domain.RefreshTokenRepository
-	blacklistRepo      domain.BlacklistRepository
-	eventBus           domain.EventBus
-	tokenRepo          infrastructure.TokenRepository
-	emailRepo          domain.EmailRepository
-	tokenService       domain.TokenService
-	accessTokenDuration  time.Duration
-	refreshTokenDuration time.Duration
-	appURL             string
-	maxEmailsPerDay    int
-	userDeletionRepo   domain.UserDeletionRepository
-	deletionCapacityRepo domain.DeletionCapacityRepository
+	userRepo              domain.UserRepository
+	refreshTokenRepo      domain.RefreshTokenRepository
+	blacklistRepo         domain.BlacklistRepository
+	eventBus              domain.EventBus
+	tokenRepo             infrastructure.TokenRepository
+	emailRepo             domain.EmailRepository
+	tokenService          domain.TokenService
+	accessTokenDuration   time.Duration
+	refreshTokenDuration  time.Duration
+	appURL                string
+	userDeletionRepo      domain.UserDeletionRepository
+	userLoginRepo         domain.UserLoginRepository
+	geoIPResolver         domain.GeoIPResolver
+	travelDetector        *domain.ImpossibleTravelDetector
+	userDeletionCycleRepo domain.UserDeletionCycleRepository
+	actionLogRepo         domain.ActionLogRepository
+	deletionPolicy        domain.DeletionPolicy
+	dataExportCooldown    time.Duration
 }
 
 // NewUserApplicationService creates a new UserApplicationService.
@@ -40,35 +44,46 @@ func NewUserApplicationService(
 	accessTokenDuration time.Duration,
 	refreshTokenDuration time.Duration,
 	appURL string,
-	maxEmailsPerDay int,
 	userDeletionRepo domain.UserDeletionRepository,
-	deletionCapacityRepo domain.DeletionCapacityRepository,
+	userLoginRepo domain.UserLoginRepository,
+	geoIPResolver domain.GeoIPResolver,
+	travelDetector *domain.ImpossibleTravelDetector,
+	userDeletionCycleRepo domain.UserDeletionCycleRepository,
+	actionLogRepo domain.ActionLogRepository,
+	deletionPolicy domain.DeletionPolicy,
+	dataExportCooldown time.Duration,
 ) *UserApplicationService {
 	return &UserApplicationService{
-		userRepo:           userRepo,
-		refreshTokenRepo:   refreshTokenRepo,
-		blacklistRepo:      blacklistRepo,
-		eventBus:           eventBus,
-		tokenRepo:          tokenRepo,
-		emailRepo:          emailRepo,
-		tokenService:       tokenService,
-		accessTokenDuration:  accessTokenDuration,
-		refreshTokenDuration: refreshTokenDuration,
-		appURL:             appURL,
-		maxEmailsPerDay:    maxEmailsPerDay,
-		userDeletionRepo:   userDeletionRepo,
-		deletionCapacityRepo: deletionCapacityRepo,
-	}
-}
-
-func (s *UserApplicationService) Register(ctx context.Context, name, email, password string) (*AuthTokens, *domain.User, error) {
-	registerUserUseCase := NewRegisterUser(s.userRepo, s.emailRepo, s.tokenRepo, s.eventBus, s.appURL, s.maxEmailsPerDay)
-	user, err := registerUserUseCase.Execute(ctx, name, email, password)
+		userRepo:              userRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		blacklistRepo:         blacklistRepo,
+		eventBus:              eventBus,
+		tokenRepo:             tokenRepo,
+		emailRepo:             emailRepo,
+		tokenService:          tokenService,
+		accessTokenDuration:   accessTokenDuration,
+		refreshTokenDuration:  refreshTokenDuration,
+		appURL:                appURL,
+		userDeletionRepo:      userDeletionRepo,
+		userLoginRepo:         userLoginRepo,
+		geoIPResolver:         geoIPResolver,
+		travelDetector:        travelDetector,
+		userDeletionCycleRepo: userDeletionCycleRepo,
+		actionLogRepo:         actionLogRepo,
+		deletionPolicy:        deletionPolicy,
+		dataExportCooldown:    dataExportCooldown,
+	}
+}
+
+func (s *UserApplicationService) Register(ctx context.Context, name, email, password, locale string) (*AuthTokens, *domain.User, error) {
+	registerUserUseCase := NewRegisterUser(s.userRepo, s.emailRepo, s.tokenRepo, s.eventBus, s.appURL)
+	user, err := registerUserUseCase.Execute(ctx, name, email, password, locale)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to register user: %w", err)
 	}
 
-	accessToken, err := s.tokenService.CreateAccessToken(ctx, user)
+	authentication := domain.NewAuthentication(domain.AuthMethodPassword)
+	accessToken, err := s.tokenService.CreateAccessToken(ctx, user, authentication)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -79,11 +94,13 @@ func (s *UserApplicationService) Register(ctx context.Context, name, email, pass
 	}
 
 	refreshToken := &domain.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		Token:     refreshTokenString,
-		ExpiresAt: time.Now().Add(s.refreshTokenDuration),
-		CreatedAt: time.Now(),
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Token:           refreshTokenString,
+		ExpiresAt:       time.Now().Add(s.refreshTokenDuration),
+		CreatedAt:       time.Now(),
+		AuthenticatedAt: authentication.Time,
+		AuthMethods:     authentication.Methods,
 	}
 
 	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, refreshToken); err != nil {
@@ -95,10 +112,41 @@ func (s *UserApplicationService) Register(ctx context.Context, name, email, pass
 
 // Login logs in a user.
 func (s *UserApplicationService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*LoginResponse, error) {
-	loginUseCase := NewLoginUser(s.userRepo, s.refreshTokenRepo, s.tokenService)
+	loginUseCase := NewLoginUser(s.userRepo, s.refreshTokenRepo, s.userLoginRepo, s.tokenRepo, s.tokenService, s.eventBus, s.geoIPResolver, s.travelDetector, s.appURL)
 	return loginUseCase.Execute(ctx, email, password, ipAddress, userAgent)
 }
 
+// VerifyLogin completes a login held for step-up verification.
+func (s *UserApplicationService) VerifyLogin(ctx context.Context, token string) (*AuthTokens, *domain.User, error) {
+	verifyLoginUseCase := NewVerifyLogin(s.userRepo, s.refreshTokenRepo, s.userLoginRepo, s.tokenRepo, s.tokenService)
+	loginResponse, user, err := verifyLoginUseCase.Execute(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &AuthTokens{AccessToken: loginResponse.AccessToken, RefreshToken: loginResponse.RefreshToken}, user, nil
+}
+
+// RevokeSessions revokes all sessions of a user using a token from a new device alert.
+func (s *UserApplicationService) RevokeSessions(ctx context.Context, token string) error {
+	revokeSessionsUseCase := NewRevokeSessions(s.refreshTokenRepo, s.tokenRepo)
+	return revokeSessionsUseCase.Execute(ctx, token)
+}
+
+// GetLoginHistory returns a page of the user's login history and the total number of entries.
+func (s *UserApplicationService) GetLoginHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.UserLogin, int, error) {
+	logins, err := s.userLoginRepo.GetUserLoginsByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get login history: %w", err)
+	}
+
+	total, err := s.userLoginRepo.CountUserLoginsByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count login history: %w", err)
+	}
+
+	return logins, total, nil
+}
+
 func (s *UserApplicationService) Logout(ctx context.Context, accessToken string, refreshToken string) error {
 	// Blacklist the access token
 	if accessToken != "" {
@@ -122,11 +170,46 @@ func (s *UserApplicationService) RecoverPassword(ctx context.Context, email stri
 	return recoverPasswordUseCase.Execute(ctx, email)
 }
 
+// Reauthenticate confirms the password of a signed-in user and returns a short-lived elevated access token.
+func (s *UserApplicationService) Reauthenticate(ctx context.Context, userID uuid.UUID, password string) (string, error) {
+	reauthenticateUseCase := NewReauthenticate(s.userRepo, s.tokenService)
+	return reauthenticateUseCase.Execute(ctx, userID, password)
+}
+
+// RevokeAllSessions revokes every refresh token of a signed-in user.
+func (s *UserApplicationService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID)
+}
+
 func (s *UserApplicationService) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
-	deleteUserUseCase := NewDeleteUser(s.userRepo, s.userDeletionRepo, s.deletionCapacityRepo, s.eventBus)
+	deleteUserUseCase := NewDeleteUser(s.userRepo, s.userDeletionRepo, s.eventBus, s.appURL, s.userDeletionCycleRepo, s.deletionPolicy)
 	return deleteUserUseCase.Execute(ctx, userID)
 }
 
+// CancelAccountDeletion cancels a pending account deletion with the recovery token sent to the user.
+func (s *UserApplicationService) CancelAccountDeletion(ctx context.Context, token string) error {
+	cancelAccountDeletionUseCase := NewCancelAccountDeletion(s.userRepo, s.userDeletionRepo, s.userDeletionCycleRepo, s.actionLogRepo, s.eventBus, s.deletionPolicy)
+	return cancelAccountDeletionUseCase.Execute(ctx, token)
+}
+
+// RequestDataExport requests an export of the personal data of a signed-in user, sent to them by email.
+func (s *UserApplicationService) RequestDataExport(ctx context.Context, userID uuid.UUID) error {
+	requestDataExportUseCase := NewRequestDataExport(s.userRepo, s.tokenRepo, s.actionLogRepo, s.eventBus, s.dataExportCooldown)
+	return requestDataExportUseCase.Execute(ctx, userID)
+}
+
+// RequestEmailChange sends a link confirming the change of the user's email to newEmail.
+func (s *UserApplicationService) RequestEmailChange(ctx context.Context, userID uuid.UUID, newEmail string) error {
+	requestEmailChangeUseCase := NewRequestEmailChange(s.userRepo, s.tokenRepo, s.eventBus, s.appURL)
+	return requestEmailChangeUseCase.Execute(ctx, userID, newEmail)
+}
+
+// ConfirmEmailChange replaces the user's email with the address an email change token was sent to.
+func (s *UserApplicationService) ConfirmEmailChange(ctx context.Context, token string) error {
+	confirmEmailChangeUseCase := NewConfirmEmailChange(s.userRepo, s.tokenRepo)
+	return confirmEmailChangeUseCase.Execute(ctx, token)
+}
+
 // RecoverAccount recovers a user account with a token and new password.
 func (s *UserApplicationService) RecoverAccount(ctx context.Context, token, newPassword string) (*AuthTokens, *domain.User, error) {
 	recoverAccountUseCase := NewVerifyTokenAndResetPassword(s.userRepo, s.tokenRepo)
@@ -140,7 +223,8 @@ func (s *UserApplicationService) RecoverAccount(ctx context.Context, token, newP
 		return nil, nil, fmt.Errorf("failed to revoke all refresh tokens for user: %w", err)
 	}
 
-	accessToken, err := s.tokenService.CreateAccessToken(ctx, user)
+	authentication := domain.NewAuthentication(domain.AuthMethodPassword, domain.AuthMethodEmailLink)
+	accessToken, err := s.tokenService.CreateAccessToken(ctx, user, authentication)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -151,11 +235,13 @@ func (s *UserApplicationService) RecoverAccount(ctx context.Context, token, newP
 	}
 
 	refreshToken := &domain.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		Token:     refreshTokenString,
-		ExpiresAt: time.Now().Add(s.refreshTokenDuration),
-		CreatedAt: time.Now(),
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Token:           refreshTokenString,
+		ExpiresAt:       time.Now().Add(s.refreshTokenDuration),
+		CreatedAt:       time.Now(),
+		AuthenticatedAt: authentication.Time,
+		AuthMethods:     authentication.Methods,
 	}
 
 	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, refreshToken); err != nil {
@@ -183,11 +269,11 @@ func (s *UserApplicationService) RefreshToken(ctx context.Context, refreshTokenS
 	}
 
 	if refreshToken.Revoked {
-		return nil, nil, fmt.Errorf("refresh token has been revoked")
+		return nil, nil, domain.ErrRefreshTokenRevoked
 	}
 
 	if refreshToken.ExpiresAt.Before(time.Now()) {
-		return nil, nil, fmt.Errorf("refresh token has expired")
+		return nil, nil, domain.ErrRefreshTokenExpired
 	}
 
 	// Get the user associated with the refresh token
@@ -196,13 +282,18 @@ func (s *UserApplicationService) RefreshToken(ctx context.Context, refreshTokenS
 		return nil, nil, fmt.Errorf("user not found for refresh token: %w", err)
 	}
 
+	if user.IsDeleted {
+		return nil, nil, domain.ErrUserDeleted
+	}
+
 	// Revoke the old refresh token
 	if err := s.refreshTokenRepo.RevokeRefreshToken(ctx, refreshToken.ID); err != nil {
 		return nil, nil, fmt.Errorf("failed to revoke old refresh token: %w", err)
 	}
 
-	// Generate a new access token
-	newAccessToken, err := s.tokenService.CreateAccessToken(ctx, user)
+	// Generate a new access token, keeping the auth_time of the original sign-in
+	authentication := refreshToken.Authentication()
+	newAccessToken, err := s.tokenService.CreateAccessToken(ctx, user, authentication)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate new access token: %w", err)
 	}
@@ -214,11 +305,17 @@ func (s *UserApplicationService) RefreshToken(ctx context.Context, refreshTokenS
 	}
 
 	newRefreshToken := &domain.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		Token:     newRefreshTokenString,
-		ExpiresAt: time.Now().Add(s.refreshTokenDuration),
-		CreatedAt: time.Now(),
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Token:           newRefreshTokenString,
+		ExpiresAt:       time.Now().Add(s.refreshTokenDuration),
+		CreatedAt:       time.Now(),
+		IPAddress:       refreshToken.IPAddress,
+		UserAgent:       refreshToken.UserAgent,
+		Country:         refreshToken.Country,
+		City:            refreshToken.City,
+		AuthenticatedAt: authentication.Time,
+		AuthMethods:     authentication.Methods,
 	}
 
 	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, newRefreshToken); err != nil {
@@ -236,7 +333,7 @@ func (s *UserApplicationService) VerifyEmail(ctx context.Context, token string)
 
 // ResendVerificationEmail resends the verification email.
 func (s *UserApplicationService) ResendVerificationEmail(ctx context.Context, email string) error {
-	resendVerificationEmailUseCase := NewResendVerificationEmail(s.userRepo, s.emailRepo, s.tokenRepo, s.eventBus, s.appURL, s.maxEmailsPerDay)
+	resendVerificationEmailUseCase := NewResendVerificationEmail(s.userRepo, s.emailRepo, s.tokenRepo, s.eventBus, s.appURL)
 	return resendVerificationEmailUseCase.Execute(ctx, email)
 }
 
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserApplicationService_LoginThenRefresh(t *testing.T) {
+	ctx := context.Background()
+	user := newVerifiedUser(t, "password123")
+	refreshTokenRepo := &fakeRefreshTokenRepository{}
+	tokenService := auth.NewTokenService(refreshTokenRepo, "test-secret", 5*time.Minute)
+	geoIP := fakeGeoIPResolver{"200.0.0.1": {Country: "BR", City: "São Paulo"}}
+	service := NewUserApplicationService(
+		newFakeUserRepository(user), refreshTokenRepo, nil, &fakeEventBus{}, newFakeTokenRepository(), nil, tokenService,
+		15*time.Minute, 7*24*time.Hour, "http://localhost:3000", nil, &fakeUserLoginRepository{}, geoIP, nil, nil, nil,
+		domain.DeletionPolicy{}, 24*time.Hour,
+	)
+
+	// Test case 1: Login stores the session with its location and authentication
+	login, err := service.Login(ctx, user.Email, "password123", "200.0.0.1", "Firefox")
+	require.NoError(t, err)
+	require.Len(t, refreshTokenRepo.tokens, 1)
+	session := refreshTokenRepo.tokens[0]
+	assert.Equal(t, login.RefreshToken, session.Token)
+	assert.Equal(t, "BR", session.Country)
+	assert.Equal(t, []string{domain.AuthMethodPassword}, session.AuthMethods)
+	validated, err := tokenService.ValidateRefreshToken(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, validated.UserID)
+
+	// Test case 2: Refreshing rotates the token and keeps the original sign-in
+	tokens, refreshedUser, err := service.RefreshToken(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, refreshedUser.ID)
+	assert.True(t, session.Revoked)
+	require.Len(t, refreshTokenRepo.tokens, 2)
+	rotated := refreshTokenRepo.tokens[1]
+	assert.Equal(t, tokens.RefreshToken, rotated.Token)
+	assert.Equal(t, "São Paulo", rotated.City)
+	assert.True(t, session.AuthenticatedAt.Equal(rotated.AuthenticatedAt))
+
+	claims, err := tokenService.ParseAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, session.AuthenticatedAt.Unix(), claims.AuthTime.Unix())
+
+	// Test case 3: The rotated-out token cannot be used again
+	_, _, err = service.RefreshToken(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenRevoked)
+}
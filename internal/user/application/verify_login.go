@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
+)
+
+// VerifyLogin is a use case for completing a login that was held for step-up verification.
+type VerifyLogin struct {
+	UserRepository         domain.UserRepository
+	RefreshTokenRepository domain.RefreshTokenRepository
+	UserLoginRepository    domain.UserLoginRepository
+	TokenRepository        infrastructure.TokenRepository
+	TokenService           domain.TokenService
+}
+
+// NewVerifyLogin creates a new VerifyLogin use case.
+func NewVerifyLogin(userRepository domain.UserRepository, refreshTokenRepository domain.RefreshTokenRepository, userLoginRepository domain.UserLoginRepository, tokenRepository infrastructure.TokenRepository, tokenService domain.TokenService) *VerifyLogin {
+	return &VerifyLogin{
+		UserRepository:         userRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		UserLoginRepository:    userLoginRepository,
+		TokenRepository:        tokenRepository,
+		TokenService:           tokenService,
+	}
+}
+
+// Execute validates the verification token and issues the tokens of the held login.
+func (uc *VerifyLogin) Execute(ctx context.Context, token string) (*LoginResponse, *domain.User, error) {
+	key := fmt.Sprintf("login-verification:%s", token)
+	challengeString, err := uc.TokenRepository.Get(ctx, key)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	var challenge loginChallenge
+	if err := json.Unmarshal([]byte(challengeString), &challenge); err != nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(challenge.UserID)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	user, err := uc.UserRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.IsDeleted {
+		return nil, nil, domain.ErrUserDeleted
+	}
+
+	// Consume the token before issuing tokens so the link can only be used once
+	if err := uc.TokenRepository.Del(ctx, key); err != nil {
+		return nil, nil, err
+	}
+
+	response, err := issueLoginTokens(ctx, uc.TokenService, uc.RefreshTokenRepository, user, domain.NewAuthentication(domain.AuthMethodPassword, domain.AuthMethodEmailLink), challenge.IPAddress, challenge.UserAgent, challenge.Location)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordLogin(ctx, uc.UserLoginRepository, newUserLogin(user.ID, challenge.IPAddress, challenge.UserAgent, challenge.Location, true))
+
+	return response, user, nil
+}
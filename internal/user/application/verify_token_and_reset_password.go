@@ -2,7 +2,6 @@ package application
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -29,12 +28,12 @@ func NewVerifyTokenAndResetPassword(userRepository domain.UserRepository, tokenR
 func (uc *VerifyTokenAndResetPassword) Execute(ctx context.Context, token, newPassword string) (*domain.User, error) {
 	userIDString, err := uc.TokenRepository.Get(ctx, fmt.Sprintf("password-reset:%s", token))
 	if err != nil {
-		return nil, errors.New("invalid or expired token")
+		return nil, domain.ErrInvalidToken
 	}
 
 	userID, err := uuid.Parse(userIDString)
 	if err != nil {
-		return nil, errors.New("invalid user ID in token")
+		return nil, domain.ErrInvalidToken
 	}
 
 	user, err := uc.UserRepository.GetUserByID(ctx, userID)
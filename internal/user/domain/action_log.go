@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the action log.
+const (
+	ActionAccountDeletionCancelled = "account_deletion_cancelled"
+	ActionAccountHardDeleted       = "account_hard_deleted"
+	ActionDataExportRequested      = "data_export_requested"
+	ActionDataExported             = "data_exported"
+)
+
+// ActionLog represents an audit log entry in the system.
+type ActionLog struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    *uuid.UUID     `json:"user_id,omitempty"`
+	Action    string         `json:"action"`
+	CreatedAt time.Time      `json:"created_at"`
+	Meta      map[string]any `json:"meta,omitempty"`
+	// AnonymizedUserRef replaces UserID once the user has been hard-deleted.
+	AnonymizedUserRef string `json:"anonymized_user_ref,omitempty"`
+}
+
+// NewActionLog creates a new action log entry.
+func NewActionLog(userID *uuid.UUID, action string, meta map[string]any) *ActionLog {
+	return &ActionLog{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Action:    action,
+		CreatedAt: time.Now(),
+		Meta:      meta,
+	}
+}
+
+// ActionLogRepository defines the interface for writing audit log entries.
+type ActionLogRepository interface {
+	CreateActionLog(ctx context.Context, actionLog *ActionLog) error
+}
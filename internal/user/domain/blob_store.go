@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores binary objects such as avatars under slash-separated keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+	// SignedURL returns a URL that downloads the object of key without authentication until ttl has passed.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// AvatarBlobPrefix returns the key prefix under which the avatars of a user are stored.
+func AvatarBlobPrefix(userID uuid.UUID) string {
+	return fmt.Sprintf("avatars/%s/", userID)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var ErrDataExportTooSoon = errors.New("a data export was requested too recently")
+
+// UserDataExport is the personal data of a user included in a data export (GDPR art. 15 and 20, LGPD art. 18).
+type UserDataExport struct {
+	Profile    *User
+	Sessions   []*RefreshToken
+	Logins     []*UserLogin
+	EmailSends []*Email
+	ActionLogs []*ActionLog
+	Deletions  []*UserDeletion
+}
+
+// Redact clears the credentials held in the export, which would let anyone holding the archive
+// act on behalf of the user: session refresh tokens and deletion confirmation and recovery tokens.
+func (e *UserDataExport) Redact() {
+	for _, session := range e.Sessions {
+		session.Token = ""
+	}
+	for _, deletion := range e.Deletions {
+		deletion.Token = nil
+		deletion.RecoveryToken = nil
+	}
+}
+
+// UserDataExportRepository defines the interface for reading the personal data of a user.
+type UserDataExportRepository interface {
+	// GetUserDataExport reads every piece of personal data of a user from a single consistent
+	// snapshot. It returns ErrUserNotFound if the user does not exist.
+	GetUserDataExport(ctx context.Context, userID uuid.UUID) (*UserDataExport, error)
+}
+
+// DataExportBlobPrefix returns the key prefix under which the data exports of a user are stored.
+func DataExportBlobPrefix(userID uuid.UUID) string {
+	return fmt.Sprintf("exports/%s/", userID)
+}
+
+// DataExportBlobKey returns the key of the archive of a data export.
+func DataExportBlobKey(userID, exportID uuid.UUID) string {
+	return fmt.Sprintf("%s%s.zip", DataExportBlobPrefix(userID), exportID)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DataRetentionRepository defines the interface for purging data past its retention period.
+type DataRetentionRepository interface {
+	// DeleteExpiredTokens deletes the refresh tokens and magic links that expired before the given
+	// time and returns how many were deleted.
+	DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error)
+	// DeleteOldLogs deletes the login history and action logs created before olderThan and returns
+	// how many were deleted. Anonymized action logs of hard-deleted users are kept.
+	DeleteOldLogs(ctx context.Context, olderThan time.Time) (int64, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// DeletionPolicy configures the account deletion lifecycle, from the request to the hard deletion.
+type DeletionPolicy struct {
+	// GracePeriod is the time between a deletion request and the soft deletion of the account.
+	GracePeriod time.Duration
+	// WarningSchedule lists how long before the scheduled date reminder emails are sent, e.g. 30d, 7d and 1d.
+	WarningSchedule []time.Duration
+	// DailyCapacity is the maximum number of deletions scheduled on the same day; further requests
+	// are scheduled on the next day with capacity left.
+	DailyCapacity int
+	// HardDeleteRetention is the time between the soft deletion and the hard deletion of the account.
+	HardDeleteRetention time.Duration
+	// Cycles limits how often a user may request and cancel a deletion.
+	Cycles DeletionCyclePolicy
+}
+
+// Reminders returns the positive offsets of the warning schedule without duplicates, from the
+// earliest reminder (largest offset) to the latest one.
+func (p DeletionPolicy) Reminders() []time.Duration {
+	reminders := make([]time.Duration, 0, len(p.WarningSchedule))
+	for _, offset := range p.WarningSchedule {
+		if offset > 0 {
+			reminders = append(reminders, offset)
+		}
+	}
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i] > reminders[j] })
+
+	unique := reminders[:0]
+	for i, offset := range reminders {
+		if i == 0 || offset != reminders[i-1] {
+			unique = append(unique, offset)
+		}
+	}
+	return unique
+}
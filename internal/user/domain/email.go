@@ -18,5 +18,4 @@ type Email struct {
 // EmailRepository defines the interface for interacting with email data.
 type EmailRepository interface {
 	CreateEmail(ctx context.Context, email *Email) error
-	GetEmailsByUserIDAndType(ctx context.Context, userID uuid.UUID, emailType string) ([]*Email, error)
 }
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrLocationNotFound is returned when an IP address has no entry in the GeoIP database.
+var ErrLocationNotFound = errors.New("location not found")
+
+// GeoLocation is the approximate location of an IP address.
+type GeoLocation struct {
+	Country   string  `json:"country"`
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// GeoIPResolver defines the interface for resolving IP addresses to locations.
+type GeoIPResolver interface {
+	Lookup(ipAddress string) (*GeoLocation, error)
+}
+
+const (
+	earthRadiusKm = 6371.0
+
+	// DefaultMaxTravelSpeedKmh is roughly the cruising speed of a commercial airliner.
+	DefaultMaxTravelSpeedKmh = 1000.0
+	// DefaultMinTravelDistanceKm ignores short hops, which are within the accuracy of GeoIP data.
+	DefaultMinTravelDistanceKm = 500.0
+)
+
+// ImpossibleTravelDetector flags consecutive logins whose distance and elapsed time
+// imply a travel speed no user could reach.
+type ImpossibleTravelDetector struct {
+	MaxSpeedKmh   float64
+	MinDistanceKm float64
+}
+
+// NewImpossibleTravelDetector creates a new ImpossibleTravelDetector.
+func NewImpossibleTravelDetector(maxSpeedKmh float64) *ImpossibleTravelDetector {
+	if maxSpeedKmh <= 0 {
+		maxSpeedKmh = DefaultMaxTravelSpeedKmh
+	}
+	return &ImpossibleTravelDetector{
+		MaxSpeedKmh:   maxSpeedKmh,
+		MinDistanceKm: DefaultMinTravelDistanceKm,
+	}
+}
+
+// IsImpossible reports whether travelling from the previous location to the current one
+// between the two timestamps is impossible.
+func (d *ImpossibleTravelDetector) IsImpossible(previous *GeoLocation, previousAt time.Time, current *GeoLocation, currentAt time.Time) bool {
+	if previous == nil || current == nil {
+		return false
+	}
+
+	distance := DistanceKm(previous, current)
+	if distance < d.MinDistanceKm {
+		return false
+	}
+
+	elapsed := currentAt.Sub(previousAt).Hours()
+	if elapsed <= 0 {
+		return true
+	}
+
+	return distance/elapsed > d.MaxSpeedKmh
+}
+
+// DistanceKm returns the great-circle distance between two locations using the haversine formula.
+func DistanceKm(a, b *GeoLocation) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceKm(t *testing.T) {
+	saoPaulo := &GeoLocation{Latitude: -23.55, Longitude: -46.63}
+	rio := &GeoLocation{Latitude: -22.91, Longitude: -43.17}
+
+	assert.InDelta(t, 360, DistanceKm(saoPaulo, rio), 10)
+	assert.Zero(t, DistanceKm(saoPaulo, saoPaulo))
+}
+
+func TestImpossibleTravelDetector_IsImpossible(t *testing.T) {
+	detector := NewImpossibleTravelDetector(0)
+	saoPaulo := &GeoLocation{Latitude: -23.55, Longitude: -46.63}
+	rio := &GeoLocation{Latitude: -22.91, Longitude: -43.17}
+	berlin := &GeoLocation{Latitude: 52.52, Longitude: 13.40}
+	now := time.Now()
+
+	// Test case 1: Short hops are ignored regardless of time
+	assert.False(t, detector.IsImpossible(saoPaulo, now.Add(-time.Minute), rio, now))
+
+	// Test case 2: Crossing the Atlantic in an hour is impossible
+	assert.True(t, detector.IsImpossible(saoPaulo, now.Add(-time.Hour), berlin, now))
+
+	// Test case 3: Crossing the Atlantic in a day is possible
+	assert.False(t, detector.IsImpossible(saoPaulo, now.Add(-24*time.Hour), berlin, now))
+
+	// Test case 4: Missing locations are never flagged
+	assert.False(t, detector.IsImpossible(nil, now.Add(-time.Minute), berlin, now))
+}
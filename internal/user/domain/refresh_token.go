@@ -8,7 +8,14 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token has been revoked")
+	ErrRefreshTokenExpired  = errors.New("refresh token has expired")
+	// ErrInvalidToken is returned for unknown, expired or malformed one-time tokens
+	// such as email verification, recovery and login verification links.
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
 
 // RefreshToken represents a refresh token in the system.
 type RefreshToken struct {
@@ -21,6 +28,21 @@ type RefreshToken struct {
 	Revoked   bool       `json:"revoked"`
 	IPAddress string     `json:"ip_address"`
 	UserAgent string     `json:"user_agent"`
+	Country   string     `json:"country,omitempty"`
+	City      string     `json:"city,omitempty"`
+	// AuthenticatedAt and AuthMethods are carried over on rotation so refreshed
+	// access tokens keep the auth_time and amr of the original sign-in.
+	AuthenticatedAt time.Time `json:"authenticated_at"`
+	AuthMethods     []string  `json:"auth_methods,omitempty"`
+}
+
+// Authentication returns when and how the session was authenticated.
+func (t *RefreshToken) Authentication() Authentication {
+	authenticatedAt := t.AuthenticatedAt
+	if authenticatedAt.IsZero() {
+		authenticatedAt = t.CreatedAt
+	}
+	return Authentication{Time: authenticatedAt, Methods: t.AuthMethods}
 }
 
 // RefreshTokenRepository defines the interface for managing refresh tokens.
@@ -2,13 +2,32 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Authentication method references, as carried in the amr claim of access tokens.
+const (
+	AuthMethodPassword  = "pwd"
+	AuthMethodEmailLink = "email"
+)
+
+// Authentication describes when and how a user last proved their identity.
+type Authentication struct {
+	Time    time.Time
+	Methods []string
+}
+
+// NewAuthentication creates an Authentication that happened now with the given methods.
+func NewAuthentication(methods ...string) Authentication {
+	return Authentication{Time: time.Now(), Methods: methods}
+}
+
 // TokenService defines the interface for creating and validating tokens.
 type TokenService interface {
-	CreateAccessToken(ctx context.Context, user *User) (string, error)
+	CreateAccessToken(ctx context.Context, user *User, authentication Authentication) (string, error)
+	CreateElevatedToken(ctx context.Context, user *User, authentication Authentication) (string, error)
 	CreateRefreshToken(ctx context.Context, user *User) (string, error)
 	VerifyToken(ctx context.Context, tokenString string) (uuid.UUID, error)
 }
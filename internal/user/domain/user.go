@@ -2,25 +2,38 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrEmailNotVerified      = errors.New("email not verified")
+	ErrEmailAlreadyVerified  = errors.New("email already verified")
+	ErrUserDeleted           = errors.New("user is deleted")
+	ErrDeletionLimitExceeded = errors.New("deletion limit exceeded")
+	ErrEmailAlreadyInUse     = errors.New("email already in use")
+)
+
 // User represents a user in the system.
 type User struct {
-	ID                uuid.UUID  `json:"id"`
-	Name              string     `json:"name"`
-	Email             string     `json:"email"`
-	PasswordHash      string     `json:"-"`
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
-	IsEmailVerified   bool       `json:"is_email_verified"`
-	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
-	AvatarURL         *string    `json:"avatar_url,omitempty"`
-	DeletionDueAt     *time.Time `json:"deletion_due_at,omitempty"`
-	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
-	IsDeleted         bool       `json:"is_deleted"`
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	PasswordHash    string     `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	IsEmailVerified bool       `json:"is_email_verified"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	AvatarURL       *string    `json:"avatar_url,omitempty"`
+	DeletionDueAt   *time.Time `json:"deletion_due_at,omitempty"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
+	IsDeleted       bool       `json:"is_deleted"`
+	// Locale is the language of the emails sent to the user, one of i18n.Locales.
+	Locale string `json:"locale"`
 }
 
 // UserRepository defines the interface for interacting with user data.
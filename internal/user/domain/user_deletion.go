@@ -2,27 +2,166 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// DeletionStatus is the state of a user deletion request, matching the user_deletions.status CHECK constraint.
+//
+// A request moves queued → warned → scheduled → executed, and can be cancelled from any
+// non-terminal state. Queued requests move directly to scheduled when no reminder was sent:
+//   - queued: requested, in the grace period
+//   - warned: at least one reminder email was sent because the deletion date is near
+//   - scheduled: the deletion date has passed and the deletion waits to be executed
+//   - executed: the account was soft-deleted and awaits hard deletion
+//   - cancelled: the user kept their account
+type DeletionStatus string
+
+const (
+	DeletionStatusQueued    DeletionStatus = "queued"
+	DeletionStatusWarned    DeletionStatus = "warned"
+	DeletionStatusScheduled DeletionStatus = "scheduled"
+	DeletionStatusExecuted  DeletionStatus = "executed"
+	DeletionStatusCancelled DeletionStatus = "cancelled"
+)
+
+// MaxDeletionScheduleDelay is how far past the requested date a deletion may be pushed when the
+// daily deletion capacity is exhausted.
+const MaxDeletionScheduleDelay = 365 * 24 * time.Hour
+
+var (
+	ErrUserDeletionNotFound      = errors.New("user deletion not found")
+	ErrInvalidDeletionTransition = errors.New("invalid user deletion status transition")
+	// ErrDeletionAlreadyPending is returned when a deletion is requested for a user who already
+	// has a queued, warned or scheduled deletion.
+	ErrDeletionAlreadyPending = errors.New("user deletion already pending")
+)
+
+// deletionTransitions lists the states each state may move to.
+var deletionTransitions = map[DeletionStatus][]DeletionStatus{
+	DeletionStatusQueued:    {DeletionStatusWarned, DeletionStatusScheduled, DeletionStatusCancelled},
+	DeletionStatusWarned:    {DeletionStatusScheduled, DeletionStatusCancelled},
+	DeletionStatusScheduled: {DeletionStatusExecuted, DeletionStatusCancelled},
+}
+
+// CanTransitionTo reports whether a deletion in status s may move to next.
+func (s DeletionStatus) CanTransitionTo(next DeletionStatus) bool {
+	for _, allowed := range deletionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further transitions are possible from s.
+func (s DeletionStatus) IsTerminal() bool {
+	return len(deletionTransitions[s]) == 0
+}
+
 // UserDeletion represents a user deletion request.
 type UserDeletion struct {
-	ID                     uuid.UUID  `json:"id"`
-	UserID                 uuid.UUID  `json:"user_id"`
-	ScheduledDate          time.Time  `json:"scheduled_date"`
-	Executed               bool       `json:"executed"`
-	CreatedAt              time.Time  `json:"created_at"`
-	Status                 string     `json:"status"`
-	Token                  *string    `json:"token,omitempty"`
-	TokenExpiresAt         *time.Time `json:"token_expires_at,omitempty"`
-	RecoveryToken          *string    `json:"recovery_token,omitempty"`
-	RecoveryTokenExpiresAt *time.Time `json:"recovery_token_expires_at,omitempty"`
+	ID                     uuid.UUID      `json:"id"`
+	UserID                 uuid.UUID      `json:"user_id"`
+	ScheduledDate          time.Time      `json:"scheduled_date"`
+	Executed               bool           `json:"executed"`
+	CreatedAt              time.Time      `json:"created_at"`
+	Status                 DeletionStatus `json:"status"`
+	Token                  *string        `json:"token,omitempty"`
+	TokenExpiresAt         *time.Time     `json:"token_expires_at,omitempty"`
+	RecoveryToken          *string        `json:"recovery_token,omitempty"`
+	RecoveryTokenExpiresAt *time.Time     `json:"recovery_token_expires_at,omitempty"`
+	WarnedAt               *time.Time     `json:"warned_at,omitempty"`
+	ExecutedAt             *time.Time     `json:"executed_at,omitempty"`
+	CancelledAt            *time.Time     `json:"cancelled_at,omitempty"`
+}
+
+// TransitionTo moves the deletion to next, recording when it happened.
+// Moving to the current status is a no-op so that retried transitions are idempotent.
+func (d *UserDeletion) TransitionTo(next DeletionStatus, at time.Time) error {
+	if d.Status == next {
+		return nil
+	}
+	if !d.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidDeletionTransition, d.Status, next)
+	}
+
+	switch next {
+	case DeletionStatusWarned:
+		d.WarnedAt = &at
+	case DeletionStatusExecuted:
+		d.Executed = true
+		d.ExecutedAt = &at
+	case DeletionStatusCancelled:
+		d.CancelledAt = &at
+	}
+	d.Status = next
+	return nil
+}
+
+// Warn records a reminder sent at the given time. Unlike TransitionTo, it updates WarnedAt on
+// deletions that were already warned, so that each reminder of the warning schedule is sent once.
+func (d *UserDeletion) Warn(at time.Time) error {
+	if err := d.TransitionTo(DeletionStatusWarned, at); err != nil {
+		return err
+	}
+	d.WarnedAt = &at
+	return nil
 }
 
+// UserDeletionFilter selects the deletions locked by UserDeletionRepository.ProcessUserDeletions.
+type UserDeletionFilter struct {
+	// Statuses are the statuses to select.
+	Statuses []DeletionStatus
+	// ScheduledBy selects deletions whose scheduled date is on or before it.
+	ScheduledBy time.Time
+	// NotWarnedWithin, when non-zero, skips deletions warned at or after NotWarnedWithin before
+	// their scheduled date, i.e. deletions that already got the reminder for that offset.
+	NotWarnedWithin time.Duration
+}
+
+// Matches reports whether the deletion is selected by the filter.
+func (f UserDeletionFilter) Matches(d *UserDeletion) bool {
+	if d.ScheduledDate.After(f.ScheduledBy) {
+		return false
+	}
+	if f.NotWarnedWithin > 0 && d.WarnedAt != nil && !d.WarnedAt.Before(d.ScheduledDate.Add(-f.NotWarnedWithin)) {
+		return false
+	}
+	for _, status := range f.Statuses {
+		if d.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// UserDeletionHandler processes a single deletion locked by UserDeletionRepository.ProcessUserDeletions.
+// Status changes made to the deletion through TransitionTo are saved when the handler returns nil.
+// users works in the transaction holding the deletion, so user changes made through it are
+// committed together with the deletion, or not at all.
+type UserDeletionHandler func(ctx context.Context, deletion *UserDeletion, users UserRepository) error
+
 // UserDeletionRepository defines the interface for interacting with user deletion data.
 type UserDeletionRepository interface {
 	CreateUserDeletion(ctx context.Context, userDeletion *UserDeletion) error
+	// ScheduleUserDeletion reserves one unit of deletion capacity on the first day, from the scheduled
+	// date on, whose count is below dailyCapacity, moves
+	// ScheduledDate to that day and creates the deletion, all in one transaction. It returns
+	// ErrDeletionLimitExceeded when no day within MaxDeletionScheduleDelay has capacity left, and
+	// ErrDeletionAlreadyPending, reserving nothing, when the user already has a non-terminal deletion.
+	ScheduleUserDeletion(ctx context.Context, userDeletion *UserDeletion, dailyCapacity int) error
 	GetUserDeletionsByDate(ctx context.Context, date time.Time) ([]*UserDeletion, error)
+	GetUserDeletionByRecoveryToken(ctx context.Context, token string) (*UserDeletion, error)
+	// UpdateUserDeletion saves the deletion only if its stored status is still expectedStatus,
+	// and returns ErrInvalidDeletionTransition otherwise. Cancelling a deletion releases the unit of
+	// deletion capacity reserved for its scheduled date.
+	UpdateUserDeletion(ctx context.Context, userDeletion *UserDeletion, expectedStatus DeletionStatus) error
+	// ProcessUserDeletions locks up to limit deletions selected by filter, skipping rows locked by
+	// other workers, and calls handle for each of them.
+	// It returns the number of deletions that were handled successfully.
+	ProcessUserDeletions(ctx context.Context, filter UserDeletionFilter, limit int, handle UserDeletionHandler) (int, error)
 }
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDeletionCooldown           = errors.New("account deletion was cancelled too recently")
+	ErrDeletionCycleLimitExceeded = errors.New("too many account deletion cycles")
+)
+
+// UserDeletionCycle counts how many times a user requested and then cancelled the deletion of their account.
+// Cycles only counts the current window: the count restarts once a window passes without a cycle.
+type UserDeletionCycle struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Cycles      int        `json:"cycles"`
+	LastCycleAt *time.Time `json:"last_cycle_at,omitempty"`
+}
+
+// DeletionCyclePolicy limits how often a user may request and cancel the deletion of their account.
+type DeletionCyclePolicy struct {
+	// MaxCycles is the number of cancelled deletions allowed within Window; 0 disables the limit.
+	MaxCycles int
+	// Window is how long a cycle keeps counting towards MaxCycles after the last one.
+	Window time.Duration
+	// Cooldown is the minimum time between a cancellation and a new deletion request.
+	Cooldown time.Duration
+}
+
+// DeletionCycleError reports a deletion request rejected by a DeletionCyclePolicy.
+type DeletionCycleError struct {
+	Err     error
+	RetryAt time.Time
+}
+
+func (e *DeletionCycleError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.Err, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *DeletionCycleError) Unwrap() error {
+	return e.Err
+}
+
+// Check returns a *DeletionCycleError wrapping ErrDeletionCooldown or ErrDeletionCycleLimitExceeded
+// when a user with the given cycles may not request the deletion of their account at now.
+func (p DeletionCyclePolicy) Check(cycle *UserDeletionCycle, now time.Time) error {
+	if cycle == nil || cycle.LastCycleAt == nil {
+		return nil
+	}
+
+	if cooldownEnd := cycle.LastCycleAt.Add(p.Cooldown); now.Before(cooldownEnd) {
+		return &DeletionCycleError{Err: ErrDeletionCooldown, RetryAt: cooldownEnd}
+	}
+
+	windowEnd := cycle.LastCycleAt.Add(p.Window)
+	if p.MaxCycles > 0 && cycle.Cycles >= p.MaxCycles && now.Before(windowEnd) {
+		return &DeletionCycleError{Err: ErrDeletionCycleLimitExceeded, RetryAt: windowEnd}
+	}
+	return nil
+}
+
+// UserDeletionCycleRepository defines the interface for interacting with user deletion cycle data.
+type UserDeletionCycleRepository interface {
+	// GetUserDeletionCycle returns the cycles of a user, with zero cycles when none was recorded.
+	GetUserDeletionCycle(ctx context.Context, userID uuid.UUID) (*UserDeletionCycle, error)
+	// IncrementUserDeletionCycle records a cycle, restarting the count if the last one is older than window.
+	IncrementUserDeletionCycle(ctx context.Context, userID uuid.UUID, window time.Duration) error
+}
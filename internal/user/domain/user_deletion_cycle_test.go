@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDeletionCyclePolicy_Check(t *testing.T) {
+	policy := DeletionCyclePolicy{MaxCycles: 2, Window: 30 * 24 * time.Hour, Cooldown: 24 * time.Hour}
+	lastCycleAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		cycle   *UserDeletionCycle
+		now     time.Time
+		wantErr error
+		retryAt time.Time
+	}{
+		{name: "no cycles", cycle: &UserDeletionCycle{UserID: uuid.New()}, now: lastCycleAt},
+		{name: "within cooldown", cycle: &UserDeletionCycle{Cycles: 1, LastCycleAt: &lastCycleAt}, now: lastCycleAt.Add(time.Hour), wantErr: ErrDeletionCooldown, retryAt: lastCycleAt.Add(24 * time.Hour)},
+		{name: "after cooldown", cycle: &UserDeletionCycle{Cycles: 1, LastCycleAt: &lastCycleAt}, now: lastCycleAt.Add(25 * time.Hour)},
+		{name: "limit reached", cycle: &UserDeletionCycle{Cycles: 2, LastCycleAt: &lastCycleAt}, now: lastCycleAt.Add(48 * time.Hour), wantErr: ErrDeletionCycleLimitExceeded, retryAt: lastCycleAt.Add(30 * 24 * time.Hour)},
+		{name: "limit reached, window passed", cycle: &UserDeletionCycle{Cycles: 2, LastCycleAt: &lastCycleAt}, now: lastCycleAt.Add(31 * 24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.cycle, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			var cycleErr *DeletionCycleError
+			if tt.wantErr != nil && (!errors.As(err, &cycleErr) || !cycleErr.RetryAt.Equal(tt.retryAt)) {
+				t.Errorf("Check() error = %v, want retry at %s", err, tt.retryAt)
+			}
+		})
+	}
+}
+
+func TestDeletionCyclePolicy_Check_Unlimited(t *testing.T) {
+	lastCycleAt := time.Now().Add(-time.Hour)
+	cycle := &UserDeletionCycle{Cycles: 100, LastCycleAt: &lastCycleAt}
+
+	if err := (DeletionCyclePolicy{}).Check(cycle, time.Now()); err != nil {
+		t.Errorf("Check() with an empty policy = %v, want nil", err)
+	}
+}
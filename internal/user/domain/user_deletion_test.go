@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDeletion_TransitionTo(t *testing.T) {
+	now := time.Now()
+	deletion := &UserDeletion{Status: DeletionStatusQueued}
+
+	// Test case 1: Happy path through every state
+	require.NoError(t, deletion.TransitionTo(DeletionStatusWarned, now))
+	assert.Equal(t, &now, deletion.WarnedAt)
+	require.NoError(t, deletion.TransitionTo(DeletionStatusScheduled, now))
+	require.NoError(t, deletion.TransitionTo(DeletionStatusExecuted, now))
+	assert.True(t, deletion.Executed)
+	assert.True(t, deletion.Status.IsTerminal())
+
+	// Test case 2: Repeating a transition is a no-op
+	assert.NoError(t, deletion.TransitionTo(DeletionStatusExecuted, now))
+
+	// Test case 3: Terminal states cannot move on
+	err := deletion.TransitionTo(DeletionStatusCancelled, now)
+	assert.True(t, errors.Is(err, ErrInvalidDeletionTransition))
+
+	// Test case 4: States cannot be skipped
+	deletion = &UserDeletion{Status: DeletionStatusQueued}
+	assert.Error(t, deletion.TransitionTo(DeletionStatusExecuted, now))
+	assert.Equal(t, DeletionStatusQueued, deletion.Status)
+
+	// Test case 5: Any non-terminal state can be cancelled
+	require.NoError(t, deletion.TransitionTo(DeletionStatusCancelled, now))
+	assert.Equal(t, &now, deletion.CancelledAt)
+}
+
+func TestUserDeletionFilter_Matches(t *testing.T) {
+	scheduledDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	warnedAt := scheduledDate.Add(-10 * 24 * time.Hour)
+	deletion := &UserDeletion{Status: DeletionStatusWarned, ScheduledDate: scheduledDate, WarnedAt: &warnedAt}
+	filter := UserDeletionFilter{
+		Statuses:    []DeletionStatus{DeletionStatusQueued, DeletionStatusWarned},
+		ScheduledBy: scheduledDate,
+	}
+
+	assert.True(t, filter.Matches(deletion))
+
+	// A reminder 7 days out was not sent yet, one 30 days out was
+	filter.NotWarnedWithin = 7 * 24 * time.Hour
+	assert.True(t, filter.Matches(deletion))
+	filter.NotWarnedWithin = 30 * 24 * time.Hour
+	assert.False(t, filter.Matches(deletion))
+
+	filter.NotWarnedWithin = 0
+	filter.ScheduledBy = scheduledDate.Add(-time.Hour)
+	assert.False(t, filter.Matches(deletion))
+
+	filter.ScheduledBy = scheduledDate
+	filter.Statuses = []DeletionStatus{DeletionStatusScheduled}
+	assert.False(t, filter.Matches(deletion))
+}
+
+func TestDeletionPolicy_Reminders(t *testing.T) {
+	day := 24 * time.Hour
+	policy := DeletionPolicy{WarningSchedule: []time.Duration{day, 30 * day, 0, 7 * day, day}}
+
+	assert.Equal(t, []time.Duration{30 * day, 7 * day, day}, policy.Reminders())
+	assert.Empty(t, DeletionPolicy{}.Reminders())
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserHardDeleteRepository defines the interface for permanently removing soft-deleted users.
+type UserHardDeleteRepository interface {
+	// GetUsersDueForHardDeletion returns up to limit soft-deleted users whose deletion_due_at is on or before dueBy.
+	GetUsersDueForHardDeletion(ctx context.Context, dueBy time.Time, limit int) ([]*User, error)
+	// HardDeleteUser removes a soft-deleted user in one transaction: credentials, tokens, login
+	// history, email sends and deletion history are deleted, the user's action logs are detached
+	// from the user and tagged with auditLog.AnonymizedUserRef, and auditLog is recorded.
+	// It returns ErrUserNotFound if the user no longer exists or is not soft-deleted.
+	HardDeleteUser(ctx context.Context, userID uuid.UUID, auditLog *ActionLog) error
+}
+
+// AnonymizedUserRef returns a stable pseudonym for a user, so that the retained action logs of a
+// hard-deleted user can still be correlated with each other without identifying the user.
+func AnonymizedUserRef(key []byte, userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(userID[:])
+	return "anon_" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
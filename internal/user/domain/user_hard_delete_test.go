@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAnonymizedUserRef(t *testing.T) {
+	userID := uuid.New()
+	ref := AnonymizedUserRef([]byte("key"), userID)
+
+	if ref != AnonymizedUserRef([]byte("key"), userID) {
+		t.Error("AnonymizedUserRef() is not stable")
+	}
+	if ref == AnonymizedUserRef([]byte("other-key"), userID) || ref == AnonymizedUserRef([]byte("key"), uuid.New()) {
+		t.Error("AnonymizedUserRef() collides")
+	}
+}
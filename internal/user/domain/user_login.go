@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserLoginNotFound         = errors.New("user login not found")
+	ErrLoginVerificationRequired = errors.New("login verification required")
+)
+
+// UserLogin represents a login attempt made by a user.
+type UserLogin struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	Success    bool      `json:"success"`
+	Country    string    `json:"country,omitempty"`
+	City       string    `json:"city,omitempty"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	Suspicious bool      `json:"suspicious"`
+}
+
+// Location returns the login location, or nil if the login was not geolocated.
+func (l *UserLogin) Location() *GeoLocation {
+	if l.Latitude == nil || l.Longitude == nil {
+		return nil
+	}
+	return &GeoLocation{Country: l.Country, City: l.City, Latitude: *l.Latitude, Longitude: *l.Longitude}
+}
+
+// SetLocation stores the given location on the login.
+func (l *UserLogin) SetLocation(location *GeoLocation) {
+	if location == nil {
+		return
+	}
+	l.Country = location.Country
+	l.City = location.City
+	l.Latitude = &location.Latitude
+	l.Longitude = &location.Longitude
+}
+
+// UserLoginRepository defines the interface for interacting with login history data.
+type UserLoginRepository interface {
+	CreateUserLogin(ctx context.Context, login *UserLogin) error
+	GetUserLoginsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*UserLogin, error)
+	GetLastSuccessfulLogin(ctx context.Context, userID uuid.UUID) (*UserLogin, error)
+	CountUserLoginsByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	HasSuccessfulLogin(ctx context.Context, userID uuid.UUID) (bool, error)
+	HasSuccessfulLoginFromDevice(ctx context.Context, userID uuid.UUID, ipAddress, userAgent string) (bool, error)
+}
@@ -21,13 +21,37 @@ func NewJWTService(secretKey string) *JWTService {
 	return &JWTService{SecretKey: []byte(secretKey)}
 }
 
+// jwtClaims are the claims carried by access tokens issued by the JWTService.
+type jwtClaims struct {
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	Elevated bool             `json:"elevated,omitempty"`
+	jwt.RegisteredClaims
+}
+
 // CreateAccessToken creates a new access token for the given user.
-func (s *JWTService) CreateAccessToken(ctx context.Context, user *domain.User) (string, error) {
-	claims := &jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		NotBefore: jwt.NewNumericDate(time.Now()),
-		Subject:   user.ID.String(),
+func (s *JWTService) CreateAccessToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return s.createToken(user, authentication, 15*time.Minute, false)
+}
+
+// CreateElevatedToken creates a short-lived access token for a user who has just re-authenticated.
+func (s *JWTService) CreateElevatedToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return s.createToken(user, authentication, 5*time.Minute, true)
+}
+
+func (s *JWTService) createToken(user *domain.User, authentication domain.Authentication, ttl time.Duration, elevated bool) (string, error) {
+	claims := &jwtClaims{
+		AMR:      authentication.Methods,
+		Elevated: elevated,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   user.ID.String(),
+		},
+	}
+	if !authentication.Time.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(authentication.Time)
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -45,7 +69,7 @@ func (s *JWTService) CreateRefreshToken(ctx context.Context, user *domain.User)
 
 // VerifyToken verifies the given token and returns the user ID.
 func (s *JWTService) VerifyToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwtClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return s.SecretKey, nil
 	})
 
@@ -53,7 +77,7 @@ func (s *JWTService) VerifyToken(ctx context.Context, tokenString string) (uuid.
 		return uuid.Nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*jwtClaims); ok && token.Valid {
 		return uuid.Parse(claims.Subject)
 	} else {
 		return uuid.Nil, err
@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// LocalBlobStore is a domain.BlobStore keeping objects as files under a root directory.
+// Signed URLs point to publicURL, where the API serves objects through OpenSigned.
+type LocalBlobStore struct {
+	root       string
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocalBlobStore creates a new LocalBlobStore rooted at dir, creating it if needed.
+// Signed URLs are only available when signingKey is set.
+func NewLocalBlobStore(dir, publicURL string, signingKey []byte) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob storage directory: %w", err)
+	}
+	return &LocalBlobStore{root: dir, publicURL: strings.TrimSuffix(publicURL, "/"), signingKey: signingKey}, nil
+}
+
+// Put writes content to the file of key, replacing any existing object. The content type is not stored.
+func (s *LocalBlobStore) Put(ctx context.Context, key string, content io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the object of key. Deleting a missing object is not an error.
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object under prefix. Prefixes must end with a slash and map to a directory.
+func (s *LocalBlobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("blob prefix %q must end with a slash", prefix)
+	}
+	path, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to delete blobs: %w", err)
+	}
+	return nil
+}
+
+// SignedURL returns a URL of the form <publicURL>/<key>?expires=<unix time>&signature=<hmac>.
+func (s *LocalBlobStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if len(s.signingKey) == 0 {
+		return "", errors.New("blob signing key is not configured")
+	}
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return s.publicURL + (&url.URL{Path: "/" + key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// OpenSigned opens the object of key for a request to a URL returned by SignedURL. It returns
+// domain.ErrInvalidToken if the signature does not match or has expired, and domain.ErrBlobNotFound
+// if the object no longer exists.
+func (s *LocalBlobStore) OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+	if len(s.signingKey) == 0 {
+		return nil, domain.ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, domain.ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of key and its expiry.
+func (s *LocalBlobStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps key to a file under the root, rejecting keys that would escape it.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// MaxMindGeoIPResolver is an implementation of the domain.GeoIPResolver backed by a local
+// MaxMind-format (mmdb) City database, such as GeoLite2-City or DB-IP City Lite.
+type MaxMindGeoIPResolver struct {
+	reader *geoip2.Reader
+}
+
+// NewMaxMindGeoIPResolver opens the mmdb file at the given path.
+func NewMaxMindGeoIPResolver(path string) (*MaxMindGeoIPResolver, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database %s: %w", path, err)
+	}
+	return &MaxMindGeoIPResolver{reader: reader}, nil
+}
+
+// Lookup resolves an IP address to its approximate location.
+func (r *MaxMindGeoIPResolver) Lookup(ipAddress string) (*domain.GeoLocation, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address: %s", ipAddress)
+	}
+
+	record, err := r.reader.City(ip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up IP address %s: %w", ipAddress, err)
+	}
+
+	if record.Country.IsoCode == "" && record.Location.Latitude == 0 && record.Location.Longitude == 0 {
+		return nil, domain.ErrLocationNotFound
+	}
+
+	return &domain.GeoLocation{
+		Country:   record.Country.IsoCode,
+		City:      record.City.Names["en"],
+		Latitude:  record.Location.Latitude,
+		Longitude: record.Location.Longitude,
+	}, nil
+}
+
+// Close closes the underlying database file.
+func (r *MaxMindGeoIPResolver) Close() error {
+	return r.reader.Close()
+}
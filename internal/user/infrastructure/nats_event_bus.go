@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
-	"github.com/nats-io/nats.go"
 )
 
 const (
@@ -15,13 +14,18 @@ const (
 	retryInterval = 1 * time.Second
 )
 
+// NATSPublisher publishes messages to NATS subjects. It is implemented by *nats.Conn.
+type NATSPublisher interface {
+	Publish(subj string, data []byte) error
+}
+
 // NATSEventBus is a NATS implementation of the domain.EventBus.
 type NATSEventBus struct {
-	Conn *nats.Conn
+	Conn NATSPublisher
 }
 
 // NewNATSEventBus creates a new NATSEventBus.
-func NewNATSEventBus(conn *nats.Conn) *NATSEventBus {
+func NewNATSEventBus(conn NATSPublisher) *NATSEventBus {
 	return &NATSEventBus{Conn: conn}
 }
 
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// PostgresActionLogRepository is a PostgreSQL implementation of the domain.ActionLogRepository.
+type PostgresActionLogRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresActionLogRepository creates a new PostgresActionLogRepository.
+func NewPostgresActionLogRepository(pool *pgxpool.Pool) *PostgresActionLogRepository {
+	return &PostgresActionLogRepository{pool: pool}
+}
+
+// CreateActionLog records an audit log entry.
+func (r *PostgresActionLogRepository) CreateActionLog(ctx context.Context, actionLog *domain.ActionLog) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO action_logs (id, user_id, action, created_at, meta, anonymized_user_ref)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`,
+		actionLog.ID, actionLog.UserID, actionLog.Action, actionLog.CreatedAt, actionLog.Meta, actionLog.AnonymizedUserRef,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create action log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresDataRetentionRepository is a PostgreSQL implementation of the domain.DataRetentionRepository.
+type PostgresDataRetentionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDataRetentionRepository creates a new PostgresDataRetentionRepository.
+func NewPostgresDataRetentionRepository(pool *pgxpool.Pool) *PostgresDataRetentionRepository {
+	return &PostgresDataRetentionRepository{pool: pool}
+}
+
+// DeleteExpiredTokens deletes the refresh tokens and magic links that expired before the given time.
+func (r *PostgresDataRetentionRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	return r.execAll(ctx, before,
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`,
+		`DELETE FROM magic_links WHERE expires_at < $1`,
+	)
+}
+
+// DeleteOldLogs deletes the login history and action logs created before olderThan. The anonymized
+// audit records of hard-deleted users are retained.
+func (r *PostgresDataRetentionRepository) DeleteOldLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	return r.execAll(ctx, olderThan,
+		`DELETE FROM user_logins WHERE created_at < $1`,
+		`DELETE FROM action_logs WHERE created_at < $1 AND anonymized_user_ref IS NULL`,
+	)
+}
+
+// execAll runs each statement with t as its argument and returns the total number of rows affected.
+// The compared columns are timestamps without time zone holding UTC times.
+func (r *PostgresDataRetentionRepository) execAll(ctx context.Context, t time.Time, statements ...string) (int64, error) {
+	var affected int64
+	for _, statement := range statements {
+		tag, err := r.pool.Exec(ctx, statement, t.UTC())
+		if err != nil {
+			return affected, fmt.Errorf("failed to purge expired data: %w", err)
+		}
+		affected += tag.RowsAffected()
+	}
+	return affected, nil
+}
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// PostgresEmailRepository is a PostgreSQL implementation of the domain.EmailRepository, recording
+// the account emails sent to users in email_sends.
+type PostgresEmailRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresEmailRepository creates a new PostgresEmailRepository.
+func NewPostgresEmailRepository(pool *pgxpool.Pool) *PostgresEmailRepository {
+	return &PostgresEmailRepository{pool: pool}
+}
+
+// CreateEmail records an email sent to a user.
+func (r *PostgresEmailRepository) CreateEmail(ctx context.Context, email *domain.Email) error {
+	if email.SentAt.IsZero() {
+		email.SentAt = time.Now()
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO email_sends (id, user_id, type, sent_at) VALUES ($1, $2, $3, $4)`,
+		email.ID, email.UserID, email.Type, email.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create email send: %w", err)
+	}
+	return nil
+}
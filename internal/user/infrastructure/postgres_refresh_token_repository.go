@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+const refreshTokenColumns = `id, user_id, token, expires_at, created_at, COALESCE(revoked, false), COALESCE(ip_address, ''),
+	COALESCE(user_agent, ''), COALESCE(country, ''), COALESCE(city, ''), COALESCE(authenticated_at, created_at), auth_methods`
+
+// PostgresRefreshTokenRepository is a PostgreSQL implementation of the domain.RefreshTokenRepository.
+type PostgresRefreshTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRefreshTokenRepository creates a new PostgresRefreshTokenRepository.
+func NewPostgresRefreshTokenRepository(pool *pgxpool.Pool) *PostgresRefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{pool: pool}
+}
+
+// CreateRefreshToken stores a new refresh token with the device, location and authentication of its session.
+func (r *PostgresRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	authMethods := token.AuthMethods
+	if authMethods == nil {
+		authMethods = []string{}
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, token, expires_at, created_at, revoked, ip_address, user_agent,
+			country, city, authenticated_at, auth_methods)
+		 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11, $12)`,
+		token.ID, token.UserID, token.Token, token.ExpiresAt, token.CreatedAt, token.Revoked, token.IPAddress, token.UserAgent,
+		token.Country, token.City, token.Authentication().Time, authMethods,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshTokenByToken returns the refresh token with the given value.
+func (r *PostgresRefreshTokenRepository) GetRefreshTokenByToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE token = $1`, token)
+	refreshToken := &domain.RefreshToken{}
+	err := row.Scan(
+		&refreshToken.ID, &refreshToken.UserID, &refreshToken.Token, &refreshToken.ExpiresAt, &refreshToken.CreatedAt,
+		&refreshToken.Revoked, &refreshToken.IPAddress, &refreshToken.UserAgent, &refreshToken.Country, &refreshToken.City,
+		&refreshToken.AuthenticatedAt, &refreshToken.AuthMethods,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return refreshToken, nil
+}
+
+// UpdateRefreshToken saves the mutable fields of a refresh token.
+func (r *PostgresRefreshTokenRepository) UpdateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE refresh_tokens SET expires_at = $2, revoked = $3 WHERE id = $1`,
+		token.ID, token.ExpiresAt, token.Revoked,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update refresh token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRefreshTokenNotFound
+	}
+	return nil
+}
+
+// RevokeRefreshToken revokes a single refresh token.
+func (r *PostgresRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE refresh_tokens SET revoked = true WHERE id = $1`, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRefreshTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllUserTokens revokes every refresh token of a user.
+func (r *PostgresRefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `UPDATE refresh_tokens SET revoked = true WHERE user_id = $1 AND revoked IS NOT TRUE`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
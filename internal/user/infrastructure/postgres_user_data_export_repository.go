@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// PostgresUserDataExportRepository is a PostgreSQL implementation of the domain.UserDataExportRepository.
+type PostgresUserDataExportRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserDataExportRepository creates a new PostgresUserDataExportRepository.
+func NewPostgresUserDataExportRepository(pool *pgxpool.Pool) *PostgresUserDataExportRepository {
+	return &PostgresUserDataExportRepository{pool: pool}
+}
+
+// GetUserDataExport reads the personal data of a user in a read-only repeatable read transaction,
+// so that every table is read from the same snapshot. Refresh token values are not read.
+func (r *PostgresUserDataExportRepository) GetUserDataExport(ctx context.Context, userID uuid.UUID) (*domain.UserDataExport, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	export := &domain.UserDataExport{}
+
+	export.Profile, err = scanUser(tx.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	export.Sessions, err = collectUserRows(ctx, tx,
+		`SELECT id, user_id, expires_at, created_at, COALESCE(revoked, false), COALESCE(ip_address, ''), COALESCE(user_agent, ''),
+			COALESCE(country, ''), COALESCE(city, ''), COALESCE(authenticated_at, created_at), auth_methods
+		 FROM refresh_tokens WHERE user_id = $1 ORDER BY created_at`,
+		userID, func(row pgx.CollectableRow) (*domain.RefreshToken, error) {
+			session := &domain.RefreshToken{}
+			err := row.Scan(
+				&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt, &session.Revoked, &session.IPAddress,
+				&session.UserAgent, &session.Country, &session.City, &session.AuthenticatedAt, &session.AuthMethods,
+			)
+			return session, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	export.Logins, err = collectUserRows(ctx, tx,
+		`SELECT `+userLoginColumns+` FROM user_logins WHERE user_id = $1 ORDER BY created_at`,
+		userID, func(row pgx.CollectableRow) (*domain.UserLogin, error) {
+			return scanUserLogin(row)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login history: %w", err)
+	}
+
+	export.EmailSends, err = collectUserRows(ctx, tx,
+		`SELECT id, user_id, type, sent_at FROM email_sends WHERE user_id = $1 ORDER BY sent_at`,
+		userID, func(row pgx.CollectableRow) (*domain.Email, error) {
+			email := &domain.Email{}
+			err := row.Scan(&email.ID, &email.UserID, &email.Type, &email.SentAt)
+			return email, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email sends: %w", err)
+	}
+
+	export.ActionLogs, err = collectUserRows(ctx, tx,
+		`SELECT id, user_id, action, created_at, meta FROM action_logs WHERE user_id = $1 ORDER BY created_at`,
+		userID, func(row pgx.CollectableRow) (*domain.ActionLog, error) {
+			actionLog := &domain.ActionLog{}
+			err := row.Scan(&actionLog.ID, &actionLog.UserID, &actionLog.Action, &actionLog.CreatedAt, &actionLog.Meta)
+			return actionLog, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action logs: %w", err)
+	}
+
+	export.Deletions, err = collectUserRows(ctx, tx,
+		`SELECT `+userDeletionColumns+` FROM user_deletions WHERE user_id = $1 ORDER BY created_at`,
+		userID, func(row pgx.CollectableRow) (*domain.UserDeletion, error) {
+			return scanUserDeletion(row)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deletion history: %w", err)
+	}
+
+	return export, nil
+}
+
+// collectUserRows runs a query with a single user ID argument and scans every row with scan.
+func collectUserRows[T any](ctx context.Context, tx pgx.Tx, query string, userID uuid.UUID, scan pgx.RowToFunc[*T]) ([]*T, error) {
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scan)
+}
@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// PostgresUserDeletionCycleRepository is a PostgreSQL implementation of the domain.UserDeletionCycleRepository.
+type PostgresUserDeletionCycleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserDeletionCycleRepository creates a new PostgresUserDeletionCycleRepository.
+func NewPostgresUserDeletionCycleRepository(pool *pgxpool.Pool) *PostgresUserDeletionCycleRepository {
+	return &PostgresUserDeletionCycleRepository{pool: pool}
+}
+
+// GetUserDeletionCycle returns the deletion cycles of a user.
+func (r *PostgresUserDeletionCycleRepository) GetUserDeletionCycle(ctx context.Context, userID uuid.UUID) (*domain.UserDeletionCycle, error) {
+	cycle := &domain.UserDeletionCycle{UserID: userID}
+	err := r.pool.QueryRow(ctx,
+		`SELECT cycles, last_cycle_at FROM user_deletion_cycles WHERE user_id = $1`,
+		userID,
+	).Scan(&cycle.Cycles, &cycle.LastCycleAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return cycle, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user deletion cycle: %w", err)
+	}
+	return cycle, nil
+}
+
+// IncrementUserDeletionCycle records one more deletion cycle for a user. The count restarts at 1
+// when the previous cycle is older than window.
+func (r *PostgresUserDeletionCycleRepository) IncrementUserDeletionCycle(ctx context.Context, userID uuid.UUID, window time.Duration) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO user_deletion_cycles (user_id, cycles, last_cycle_at)
+		 VALUES ($1, 1, now())
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET cycles = CASE
+		         WHEN user_deletion_cycles.last_cycle_at < now() - $2::interval THEN 1
+		         ELSE user_deletion_cycles.cycles + 1
+		     END,
+		     last_cycle_at = now()`,
+		userID, window,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment user deletion cycle: %w", err)
+	}
+	return nil
+}
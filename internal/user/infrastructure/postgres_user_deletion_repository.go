@@ -0,0 +1,329 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+const userDeletionColumns = `id, user_id, scheduled_date, COALESCE(executed, false), created_at, status,
+	token, token_expires_at, recovery_token, recovery_token_expires_at, warned_at, executed_at, cancelled_at`
+
+// PostgresUserDeletionRepository is a PostgreSQL implementation of the domain.UserDeletionRepository.
+type PostgresUserDeletionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserDeletionRepository creates a new PostgresUserDeletionRepository.
+func NewPostgresUserDeletionRepository(pool *pgxpool.Pool) *PostgresUserDeletionRepository {
+	return &PostgresUserDeletionRepository{pool: pool}
+}
+
+// CreateUserDeletion records a new deletion request.
+func (r *PostgresUserDeletionRepository) CreateUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion) error {
+	return insertUserDeletion(ctx, r.pool, userDeletion)
+}
+
+// ScheduleUserDeletion reserves capacity in deletion_capacity and creates the deletion in the same
+// transaction, so the capacity count never drifts from the deletions actually created. The
+// reservation is a single conditional upsert, so concurrent requests cannot exceed a day's limit;
+// a request losing the race for the last unit moves on to the following days. A second pending
+// deletion of the same user violates user_deletions_active_user_id_key, which rolls the
+// reservation back. Days are checked against dailyCapacity rather than the stored max_limit, so a
+// change of the policy applies to days that already have reservations.
+func (r *PostgresUserDeletionRepository) ScheduleUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion, dailyCapacity int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	day := userDeletion.ScheduledDate
+	lastDay := day.Add(domain.MaxDeletionScheduleDelay)
+	for {
+		// Skip straight to the first day that still has capacity
+		err := tx.QueryRow(ctx,
+			`SELECT d::date
+			 FROM generate_series($1::date, $2::date, interval '1 day') AS d
+			 LEFT JOIN deletion_capacity c ON c.day = d::date
+			 WHERE c.day IS NULL OR c.count < $3
+			 ORDER BY d
+			 LIMIT 1`,
+			day, lastDay, dailyCapacity,
+		).Scan(&day)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrDeletionLimitExceeded
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find deletion capacity: %w", err)
+		}
+
+		var reserved time.Time
+		err = tx.QueryRow(ctx,
+			`INSERT INTO deletion_capacity (day, count, max_limit, updated_at)
+			 VALUES ($1::date, 1, $2, now())
+			 ON CONFLICT (day) DO UPDATE
+			 SET count = deletion_capacity.count + 1, max_limit = EXCLUDED.max_limit, updated_at = now()
+			 WHERE deletion_capacity.count < EXCLUDED.max_limit
+			 RETURNING day`,
+			day, dailyCapacity,
+		).Scan(&reserved)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Another request took the last unit of this day
+			day = day.AddDate(0, 0, 1)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reserve deletion capacity: %w", err)
+		}
+
+		userDeletion.ScheduledDate = reserved
+		break
+	}
+
+	if err := insertUserDeletion(ctx, tx, userDeletion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit user deletion: %w", err)
+	}
+	return nil
+}
+
+// GetUserDeletionsByDate returns the deletions scheduled for the given day.
+func (r *PostgresUserDeletionRepository) GetUserDeletionsByDate(ctx context.Context, date time.Time) ([]*domain.UserDeletion, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+userDeletionColumns+`
+		 FROM user_deletions
+		 WHERE scheduled_date = $1::date
+		 ORDER BY created_at`,
+		date,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var deletions []*domain.UserDeletion
+	for rows.Next() {
+		deletion, err := scanUserDeletion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user deletion: %w", err)
+		}
+		deletions = append(deletions, deletion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate user deletions: %w", err)
+	}
+	return deletions, nil
+}
+
+// GetUserDeletionByRecoveryToken returns the deletion that issued the given recovery token.
+func (r *PostgresUserDeletionRepository) GetUserDeletionByRecoveryToken(ctx context.Context, token string) (*domain.UserDeletion, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+userDeletionColumns+`
+		 FROM user_deletions
+		 WHERE recovery_token = $1`,
+		token,
+	)
+	deletion, err := scanUserDeletion(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserDeletionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user deletion by recovery token: %w", err)
+	}
+	return deletion, nil
+}
+
+// UpdateUserDeletion saves the deletion if its stored status is still expectedStatus, so that a
+// change racing with the deletion worker cannot overwrite a transition the worker already made.
+// Cancelling a deletion releases its unit of deletion_capacity in the same transaction.
+func (r *PostgresUserDeletionRepository) UpdateUserDeletion(ctx context.Context, userDeletion *domain.UserDeletion, expectedStatus domain.DeletionStatus) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE user_deletions
+		 SET status = $3, executed = $4, warned_at = $5, executed_at = $6, cancelled_at = $7,
+		     recovery_token = $8, recovery_token_expires_at = $9
+		 WHERE id = $1 AND status = $2`,
+		userDeletion.ID, string(expectedStatus), string(userDeletion.Status), userDeletion.Executed,
+		userDeletion.WarnedAt, userDeletion.ExecutedAt, userDeletion.CancelledAt,
+		userDeletion.RecoveryToken, userDeletion.RecoveryTokenExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user deletion: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user deletion %s is no longer %s: %w", userDeletion.ID, expectedStatus, domain.ErrInvalidDeletionTransition)
+	}
+	if userDeletion.Status == domain.DeletionStatusCancelled && expectedStatus != domain.DeletionStatusCancelled {
+		if err := releaseDeletionCapacity(ctx, tx, userDeletion.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit user deletion: %w", err)
+	}
+	return nil
+}
+
+// ProcessUserDeletions locks a batch of deletions selected by filter with FOR UPDATE SKIP LOCKED,
+// so several workers can run concurrently without picking the same rows, and hands each one to handle.
+// Each deletion is saved inside its own savepoint, together with the user changes the handler makes
+// through the repository it is given: a failing handler leaves its deletion and user unchanged and
+// the rest of the batch is still committed. Batches processed under a job lease are fenced, so
+// a worker whose lease was taken over commits nothing.
+func (r *PostgresUserDeletionRepository) ProcessUserDeletions(ctx context.Context, filter domain.UserDeletionFilter, limit int, handle domain.UserDeletionHandler) (int, error) {
+	statuses := make([]string, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		statuses[i] = string(status)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := infrastructure.CheckFence(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+userDeletionColumns+`
+		 FROM user_deletions
+		 WHERE status = ANY($1) AND scheduled_date <= $2::date
+		   AND ($3::interval = interval '0' OR warned_at IS NULL OR warned_at < scheduled_date - $3::interval)
+		 ORDER BY scheduled_date, created_at
+		 LIMIT $4
+		 FOR UPDATE SKIP LOCKED`,
+		statuses, filter.ScheduledBy, filter.NotWarnedWithin, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock user deletions: %w", err)
+	}
+	deletions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.UserDeletion, error) {
+		return scanUserDeletion(row)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan user deletions: %w", err)
+	}
+
+	processed := 0
+	var errs []error
+	for _, deletion := range deletions {
+		if err := r.handleUserDeletion(ctx, tx, deletion, handle); err != nil {
+			errs = append(errs, fmt.Errorf("user deletion %s: %w", deletion.ID, err))
+			continue
+		}
+		processed++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit user deletions: %w", err)
+	}
+	return processed, errors.Join(errs...)
+}
+
+func (r *PostgresUserDeletionRepository) handleUserDeletion(ctx context.Context, tx pgx.Tx, deletion *domain.UserDeletion, handle domain.UserDeletionHandler) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer savepoint.Rollback(ctx)
+
+	previousStatus := deletion.Status
+	if err := handle(ctx, deletion, &PostgresUserRepository{db: savepoint}); err != nil {
+		return err
+	}
+
+	_, err = savepoint.Exec(ctx,
+		`UPDATE user_deletions
+		 SET status = $2, executed = $3, warned_at = $4, executed_at = $5, cancelled_at = $6
+		 WHERE id = $1`,
+		deletion.ID, string(deletion.Status), deletion.Executed, deletion.WarnedAt, deletion.ExecutedAt, deletion.CancelledAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user deletion: %w", err)
+	}
+	if deletion.Status == domain.DeletionStatusCancelled && previousStatus != domain.DeletionStatusCancelled {
+		if err := releaseDeletionCapacity(ctx, savepoint, deletion.ID); err != nil {
+			return err
+		}
+	}
+	return savepoint.Commit(ctx)
+}
+
+// releaseDeletionCapacity gives back the unit of deletion_capacity reserved for the scheduled day
+// of a deletion that was cancelled.
+func releaseDeletionCapacity(ctx context.Context, db execer, deletionID uuid.UUID) error {
+	_, err := db.Exec(ctx,
+		`UPDATE deletion_capacity
+		 SET count = count - 1, updated_at = now()
+		 WHERE day = (SELECT scheduled_date FROM user_deletions WHERE id = $1) AND count > 0`,
+		deletionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release deletion capacity: %w", err)
+	}
+	return nil
+}
+
+// uniqueViolation is the SQLSTATE of unique constraint violations.
+const uniqueViolation = "23505"
+
+// execer is implemented by both *pgxpool.Pool and pgx.Tx.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// insertUserDeletion inserts a deletion through the pool or a transaction.
+func insertUserDeletion(ctx context.Context, db execer, userDeletion *domain.UserDeletion) error {
+	if userDeletion.Status == "" {
+		userDeletion.Status = domain.DeletionStatusQueued
+	}
+	_, err := db.Exec(ctx,
+		`INSERT INTO user_deletions (id, user_id, scheduled_date, executed, created_at, status, token, token_expires_at, recovery_token, recovery_token_expires_at)
+		 VALUES ($1, $2, $3, $4, now(), $5, $6, $7, $8, $9)`,
+		userDeletion.ID, userDeletion.UserID, userDeletion.ScheduledDate, userDeletion.Executed, string(userDeletion.Status),
+		userDeletion.Token, userDeletion.TokenExpiresAt, userDeletion.RecoveryToken, userDeletion.RecoveryTokenExpiresAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "user_deletions_active_user_id_key" {
+		return domain.ErrDeletionAlreadyPending
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create user deletion: %w", err)
+	}
+	return nil
+}
+
+func scanUserDeletion(row pgx.Row) (*domain.UserDeletion, error) {
+	var deletion domain.UserDeletion
+	var status string
+	err := row.Scan(
+		&deletion.ID, &deletion.UserID, &deletion.ScheduledDate, &deletion.Executed, &deletion.CreatedAt, &status,
+		&deletion.Token, &deletion.TokenExpiresAt, &deletion.RecoveryToken, &deletion.RecoveryTokenExpiresAt,
+		&deletion.WarnedAt, &deletion.ExecutedAt, &deletion.CancelledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	deletion.Status = domain.DeletionStatus(status)
+	return &deletion, nil
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+const userLoginColumns = `id, user_id, COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at, COALESCE(success, true),
+	COALESCE(country, ''), COALESCE(city, ''), latitude, longitude, suspicious`
+
+// PostgresUserLoginRepository is a PostgreSQL implementation of the domain.UserLoginRepository.
+type PostgresUserLoginRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserLoginRepository creates a new PostgresUserLoginRepository.
+func NewPostgresUserLoginRepository(pool *pgxpool.Pool) *PostgresUserLoginRepository {
+	return &PostgresUserLoginRepository{pool: pool}
+}
+
+// CreateUserLogin records a login attempt.
+func (r *PostgresUserLoginRepository) CreateUserLogin(ctx context.Context, login *domain.UserLogin) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO user_logins (id, user_id, ip_address, user_agent, created_at, success, country, city, latitude, longitude, suspicious)
+		 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11)`,
+		login.ID, login.UserID, login.IPAddress, login.UserAgent, login.CreatedAt, login.Success,
+		login.Country, login.City, login.Latitude, login.Longitude, login.Suspicious,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user login: %w", err)
+	}
+	return nil
+}
+
+// GetUserLoginsByUserID returns the login history of a user, most recent first.
+func (r *PostgresUserLoginRepository) GetUserLoginsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.UserLogin, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+userLoginColumns+`
+		 FROM user_logins
+		 WHERE user_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user logins: %w", err)
+	}
+	defer rows.Close()
+
+	var logins []*domain.UserLogin
+	for rows.Next() {
+		login, err := scanUserLogin(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user login: %w", err)
+		}
+		logins = append(logins, login)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate user logins: %w", err)
+	}
+	return logins, nil
+}
+
+// GetLastSuccessfulLogin returns the most recent successful login of a user.
+func (r *PostgresUserLoginRepository) GetLastSuccessfulLogin(ctx context.Context, userID uuid.UUID) (*domain.UserLogin, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+userLoginColumns+`
+		 FROM user_logins
+		 WHERE user_id = $1 AND success = true
+		 ORDER BY created_at DESC
+		 LIMIT 1`,
+		userID,
+	)
+	login, err := scanUserLogin(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserLoginNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last successful login: %w", err)
+	}
+	return login, nil
+}
+
+// CountUserLoginsByUserID returns the number of recorded logins for a user.
+func (r *PostgresUserLoginRepository) CountUserLoginsByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_logins WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user logins: %w", err)
+	}
+	return count, nil
+}
+
+// HasSuccessfulLogin reports whether the user has ever logged in successfully.
+func (r *PostgresUserLoginRepository) HasSuccessfulLogin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_logins WHERE user_id = $1 AND success = true)`,
+		userID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user logins: %w", err)
+	}
+	return exists, nil
+}
+
+// HasSuccessfulLoginFromDevice reports whether the user has logged in successfully from the given IP/user-agent pair.
+func (r *PostgresUserLoginRepository) HasSuccessfulLoginFromDevice(ctx context.Context, userID uuid.UUID, ipAddress, userAgent string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM user_logins
+			WHERE user_id = $1 AND ip_address = $2 AND user_agent = $3 AND success = true
+		)`,
+		userID, ipAddress, userAgent,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user logins for device: %w", err)
+	}
+	return exists, nil
+}
+
+// scanUserLogin scans a row selected with userLoginColumns.
+func scanUserLogin(row pgx.Row) (*domain.UserLogin, error) {
+	login := &domain.UserLogin{}
+	err := row.Scan(
+		&login.ID, &login.UserID, &login.IPAddress, &login.UserAgent, &login.CreatedAt, &login.Success,
+		&login.Country, &login.City, &login.Latitude, &login.Longitude, &login.Suspicious,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return login, nil
+}
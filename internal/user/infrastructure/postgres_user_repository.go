@@ -0,0 +1,208 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+const userColumns = `id, name, email, password_hash, created_at, updated_at, is_email_verified,
+	deleted_at, avatar_url, deletion_due_at, last_login_at, COALESCE(is_deleted, false), locale`
+
+// querier is implemented by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	execer
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// PostgresUserRepository is a PostgreSQL implementation of the domain.UserRepository.
+type PostgresUserRepository struct {
+	db querier
+}
+
+// NewPostgresUserRepository creates a new PostgresUserRepository.
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{db: pool}
+}
+
+// CreateUser inserts a new user.
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO users (id, name, email, password_hash, is_email_verified, avatar_url, locale, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())`,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.IsEmailVerified, user.AvatarURL, user.Locale,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// GetUserByID returns the user with the given ID.
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+	user, err := scanUser(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByEmail returns the user with the given email.
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email)
+	user, err := scanUser(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateUser saves the mutable fields of a user.
+func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users
+		 SET name = $2, email = $3, password_hash = $4, is_email_verified = $5, deleted_at = $6,
+		     avatar_url = $7, deletion_due_at = $8, last_login_at = $9, is_deleted = $10, locale = $11, updated_at = now()
+		 WHERE id = $1`,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.IsEmailVerified, user.DeletedAt,
+		user.AvatarURL, user.DeletionDueAt, user.LastLoginAt, user.IsDeleted, user.Locale,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser permanently removes a user.
+func (r *PostgresUserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// hardDeleteStatements remove the rows referencing a user before the user itself. Rows keyed by
+// the email address (the delivery history in notifications and the normalized address in
+// email_suppressions) are matched through the user row, which is why it is deleted last. Action
+// logs are retained and anonymized separately.
+var hardDeleteStatements = []string{
+	`DELETE FROM refresh_tokens WHERE user_id = $1`,
+	`DELETE FROM magic_links WHERE user_id = $1`,
+	`DELETE FROM user_logins WHERE user_id = $1`,
+	`DELETE FROM email_sends WHERE user_id = $1`,
+	`DELETE FROM user_deletions WHERE user_id = $1`,
+	`DELETE FROM user_deletion_cycles WHERE user_id = $1`,
+	`DELETE FROM notification_preferences WHERE user_id = $1`,
+	`DELETE FROM notifications WHERE recipient = (SELECT email FROM users WHERE id = $1)`,
+	`DELETE FROM email_suppressions WHERE recipient = (SELECT lower(trim(email)) FROM users WHERE id = $1)`,
+	`DELETE FROM users WHERE id = $1`,
+}
+
+// GetUsersDueForHardDeletion returns soft-deleted users whose retention period has ended.
+func (r *PostgresUserRepository) GetUsersDueForHardDeletion(ctx context.Context, dueBy time.Time, limit int) ([]*domain.User, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+userColumns+`
+		 FROM users
+		 WHERE is_deleted AND deletion_due_at <= $1
+		 ORDER BY deletion_due_at
+		 LIMIT $2`,
+		dueBy, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users due for hard deletion: %w", err)
+	}
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.User, error) {
+		return scanUser(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan users due for hard deletion: %w", err)
+	}
+	return users, nil
+}
+
+// HardDeleteUser permanently removes a soft-deleted user and the rows referencing it in one
+// transaction. The user row is locked first, so concurrent workers cannot delete the same user twice,
+// and deletions made under a job lease are fenced.
+func (r *PostgresUserRepository) HardDeleteUser(ctx context.Context, userID uuid.UUID, auditLog *domain.ActionLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := infrastructure.CheckFence(ctx, tx); err != nil {
+		return err
+	}
+
+	var locked uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 AND is_deleted FOR UPDATE`, userID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE action_logs SET user_id = NULL, anonymized_user_ref = $2 WHERE user_id = $1`,
+		userID, auditLog.AnonymizedUserRef,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize action logs: %w", err)
+	}
+
+	for _, statement := range hardDeleteStatements {
+		if _, err := tx.Exec(ctx, statement, userID); err != nil {
+			return fmt.Errorf("failed to hard delete user: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO action_logs (id, user_id, action, created_at, meta, anonymized_user_ref)
+		 VALUES ($1, NULL, $2, $3, $4, $5)`,
+		auditLog.ID, auditLog.Action, auditLog.CreatedAt, auditLog.Meta, auditLog.AnonymizedUserRef,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record hard deletion: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit hard deletion: %w", err)
+	}
+	return nil
+}
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.IsEmailVerified,
+		&user.DeletedAt, &user.AvatarURL, &user.DeletionDueAt, &user.LastLoginAt, &user.IsDeleted, &user.Locale,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	createTablePattern = regexp.MustCompile(`(?s)CREATE TABLE public\.(\w+) \((.*?)\n\);`)
+	addColumnPattern   = regexp.MustCompile(`(?s)ALTER TABLE public\.(\w+)\s+([^;]*?)ADD COLUMN (user_id|recipient) `)
+	userColumnPattern  = regexp.MustCompile(`(?m)^\s*(user_id|recipient) `)
+)
+
+// personalDataTables returns the tables of the migrations with a user_id or recipient column.
+func personalDataTables(t *testing.T) map[string]bool {
+	scripts, err := fs.Glob(migrations.Postgres, "postgres/*.up.sql")
+	require.NoError(t, err)
+
+	tables := make(map[string]bool)
+	for _, script := range scripts {
+		content, err := fs.ReadFile(migrations.Postgres, script)
+		require.NoError(t, err)
+		for _, match := range createTablePattern.FindAllStringSubmatch(string(content), -1) {
+			if userColumnPattern.MatchString(match[2]) {
+				tables[match[1]] = true
+			}
+		}
+		for _, match := range addColumnPattern.FindAllStringSubmatch(string(content), -1) {
+			tables[match[1]] = true
+		}
+	}
+	return tables
+}
+
+func TestHardDeleteStatements_LeaveNoRowsOfTheUser(t *testing.T) {
+	tables := personalDataTables(t)
+	require.NotEmpty(t, tables)
+
+	// Action logs are kept for auditing, anonymized instead of deleted
+	delete(tables, "action_logs")
+
+	for table := range tables {
+		found := false
+		for _, statement := range hardDeleteStatements {
+			if strings.HasPrefix(statement, "DELETE FROM "+table+" ") {
+				found = true
+			}
+		}
+		assert.True(t, found, "hard delete leaves the rows of %s", table)
+	}
+
+	// Rows keyed by the email address are matched through the user row, so it goes last
+	assert.Equal(t, `DELETE FROM users WHERE id = $1`, hardDeleteStatements[len(hardDeleteStatements)-1])
+}
@@ -19,15 +19,9 @@ type TokenCache struct {
 	Client *redis.Client
 }
 
-// NewTokenCache creates a new TokenCache.
-func NewTokenCache(redisURL string) (*TokenCache, error) {
-	opt, err := redis.ParseURL(redisURL)
-	if err != nil {
-		return nil, err
-	}
-
-	client := redis.NewClient(opt)
-	return &TokenCache{Client: client}, nil
+// NewTokenCache creates a new TokenCache on a Redis client owned by the caller.
+func NewTokenCache(client *redis.Client) *TokenCache {
+	return &TokenCache{Client: client}
 }
 
 // Set sets a key-value pair in the cache.
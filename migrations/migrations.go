@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so that they ship with the chatear binary.
+package migrations
+
+import "embed"
+
+// Postgres holds the PostgreSQL migrations, named <version>_<name>.up.sql and <version>_<name>.down.sql.
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
@@ -0,0 +1,15 @@
+-- Drop Indexes
+DROP INDEX IF EXISTS public.idx_user_logins_user_device;
+DROP INDEX IF EXISTS public.idx_user_logins_user_created_at;
+
+-- Drop Columns
+ALTER TABLE public.refresh_tokens
+  DROP COLUMN IF EXISTS city,
+  DROP COLUMN IF EXISTS country;
+
+ALTER TABLE public.user_logins
+  DROP COLUMN IF EXISTS suspicious,
+  DROP COLUMN IF EXISTS longitude,
+  DROP COLUMN IF EXISTS latitude,
+  DROP COLUMN IF EXISTS city,
+  DROP COLUMN IF EXISTS country;
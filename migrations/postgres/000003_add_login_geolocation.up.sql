@@ -0,0 +1,15 @@
+-- Geolocation of logins and sessions
+ALTER TABLE public.user_logins
+  ADD COLUMN country text,
+  ADD COLUMN city text,
+  ADD COLUMN latitude double precision,
+  ADD COLUMN longitude double precision,
+  ADD COLUMN suspicious boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.refresh_tokens
+  ADD COLUMN country text,
+  ADD COLUMN city text;
+
+-- Indexes
+CREATE INDEX idx_user_logins_user_created_at ON public.user_logins USING btree (user_id, created_at DESC);
+CREATE INDEX idx_user_logins_user_device ON public.user_logins USING btree (user_id, ip_address, user_agent) WHERE (success = true);
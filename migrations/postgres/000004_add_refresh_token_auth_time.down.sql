@@ -0,0 +1,4 @@
+-- Drop Columns
+ALTER TABLE public.refresh_tokens
+  DROP COLUMN IF EXISTS auth_methods,
+  DROP COLUMN IF EXISTS authenticated_at;
@@ -0,0 +1,6 @@
+-- When and how the session was authenticated, carried over on refresh token rotation
+ALTER TABLE public.refresh_tokens
+  ADD COLUMN authenticated_at timestamp with time zone,
+  ADD COLUMN auth_methods text[] NOT NULL DEFAULT '{}';
+
+UPDATE public.refresh_tokens SET authenticated_at = created_at WHERE authenticated_at IS NULL;
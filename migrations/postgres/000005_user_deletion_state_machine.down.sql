@@ -0,0 +1,14 @@
+-- Drop Indexes
+DROP INDEX IF EXISTS public.idx_user_deletions_pending;
+
+-- Drop Columns
+ALTER TABLE public.user_deletions
+  DROP COLUMN IF EXISTS cancelled_at,
+  DROP COLUMN IF EXISTS executed_at,
+  DROP COLUMN IF EXISTS warned_at;
+
+-- Warned deletions fall back to queued so the original constraint holds again
+UPDATE public.user_deletions SET status = 'queued' WHERE status = 'warned';
+ALTER TABLE public.user_deletions DROP CONSTRAINT IF EXISTS user_deletions_status_check;
+ALTER TABLE public.user_deletions
+  ADD CONSTRAINT user_deletions_status_check CHECK (status = ANY (ARRAY['queued'::text, 'scheduled'::text, 'executed'::text, 'cancelled'::text]));
@@ -0,0 +1,10 @@
+-- Deletion requests move queued -> warned -> scheduled -> executed, or to cancelled
+ALTER TABLE public.user_deletions DROP CONSTRAINT IF EXISTS user_deletions_status_check;
+ALTER TABLE public.user_deletions
+  ADD CONSTRAINT user_deletions_status_check CHECK (status = ANY (ARRAY['queued'::text, 'warned'::text, 'scheduled'::text, 'executed'::text, 'cancelled'::text])),
+  ADD COLUMN warned_at timestamp with time zone,
+  ADD COLUMN executed_at timestamp with time zone,
+  ADD COLUMN cancelled_at timestamp with time zone;
+
+-- Indexes
+CREATE INDEX idx_user_deletions_pending ON public.user_deletions USING btree (status, scheduled_date) WHERE (status = ANY (ARRAY['queued'::text, 'warned'::text, 'scheduled'::text]));
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_action_logs_anonymized_user_ref;
+DROP INDEX IF EXISTS idx_users_hard_delete_due;
+
+ALTER TABLE public.action_logs DROP COLUMN IF EXISTS anonymized_user_ref;
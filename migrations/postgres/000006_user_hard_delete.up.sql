@@ -0,0 +1,6 @@
+-- Action logs of hard-deleted users are kept under a pseudonym instead of the user ID
+ALTER TABLE public.action_logs ADD COLUMN anonymized_user_ref text;
+
+-- Indexes
+CREATE INDEX idx_users_hard_delete_due ON public.users USING btree (deletion_due_at) WHERE is_deleted;
+CREATE INDEX idx_action_logs_anonymized_user_ref ON public.action_logs USING btree (anonymized_user_ref) WHERE (anonymized_user_ref IS NOT NULL);
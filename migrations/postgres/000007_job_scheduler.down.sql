@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_job_runs_running_deadline;
+DROP INDEX IF EXISTS idx_job_runs_job_name_created_at;
+DROP INDEX IF EXISTS idx_job_runs_job_name_scheduled_at;
+
+DROP TABLE IF EXISTS public.job_runs;
+DROP TABLE IF EXISTS public.scheduled_jobs;
@@ -0,0 +1,33 @@
+-- Jobs registered with the scheduler, kept so that administrators can list them
+CREATE TABLE public.scheduled_jobs (
+  name text NOT NULL,
+  cron text NOT NULL,
+  timeout_seconds bigint NOT NULL,
+  concurrency text NOT NULL CHECK (concurrency = ANY (ARRAY['forbid'::text, 'allow'::text])),
+  next_run_at timestamp with time zone,
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT scheduled_jobs_pkey PRIMARY KEY (name)
+);
+
+-- Runs move queued -> running -> succeeded or failed, or queued -> skipped
+CREATE TABLE public.job_runs (
+  id uuid NOT NULL DEFAULT gen_random_uuid(),
+  job_name text NOT NULL,
+  trigger text NOT NULL CHECK (trigger = ANY (ARRAY['schedule'::text, 'manual'::text])),
+  triggered_by uuid,
+  scheduled_at timestamp with time zone,
+  status text NOT NULL CHECK (status = ANY (ARRAY['queued'::text, 'running'::text, 'succeeded'::text, 'failed'::text, 'skipped'::text])),
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  started_at timestamp with time zone,
+  finished_at timestamp with time zone,
+  deadline_at timestamp with time zone,
+  error text,
+  fencing_token bigint,
+  CONSTRAINT job_runs_pkey PRIMARY KEY (id)
+);
+
+-- Indexes
+-- A scheduled activation is claimed by a single scheduler instance; manual runs have no scheduled_at
+CREATE UNIQUE INDEX idx_job_runs_job_name_scheduled_at ON public.job_runs USING btree (job_name, scheduled_at);
+CREATE INDEX idx_job_runs_job_name_created_at ON public.job_runs USING btree (job_name, created_at DESC);
+CREATE INDEX idx_job_runs_running_deadline ON public.job_runs USING btree (job_name, deadline_at) WHERE (status = 'running'::text);
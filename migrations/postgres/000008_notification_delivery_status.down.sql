@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_notifications_status_created_at;
+DROP INDEX IF EXISTS idx_notifications_recipient_created_at;
+
+ALTER TABLE public.notifications
+  DROP COLUMN IF EXISTS last_attempt_at,
+  DROP COLUMN IF EXISTS attempts,
+  DROP COLUMN IF EXISTS template,
+  DROP COLUMN IF EXISTS error,
+  DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+-- Deliveries move pending -> sent or failed; rows recorded before this migration were sent
+ALTER TABLE public.notifications
+  ADD COLUMN status text NOT NULL DEFAULT 'sent'::text CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text])),
+  ADD COLUMN error text,
+  ADD COLUMN template text,
+  ADD COLUMN attempts integer NOT NULL DEFAULT 1,
+  ADD COLUMN last_attempt_at timestamp with time zone;
+
+-- Indexes
+CREATE INDEX idx_notifications_recipient_created_at ON public.notifications USING btree (recipient, created_at DESC);
+CREATE INDEX idx_notifications_status_created_at ON public.notifications USING btree (status, created_at DESC);
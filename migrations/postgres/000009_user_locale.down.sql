@@ -0,0 +1,2 @@
+ALTER TABLE public.users
+  DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,3 @@
+-- Language of the emails sent to each user; existing users, mostly Brazilian, default to pt-BR
+ALTER TABLE public.users
+  ADD COLUMN locale text NOT NULL DEFAULT 'pt-BR'::text;
@@ -0,0 +1,8 @@
+DELETE FROM public.notifications WHERE status = 'skipped';
+
+ALTER TABLE public.notifications
+  DROP COLUMN IF EXISTS category,
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text]));
+
+DROP TABLE IF EXISTS public.notification_preferences;
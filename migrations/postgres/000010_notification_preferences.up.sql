@@ -0,0 +1,17 @@
+-- Per-user choices of the notifications they receive, by category and channel. Categories
+-- without a row are enabled, and security notifications cannot be disabled, so they are not stored
+CREATE TABLE public.notification_preferences (
+  user_id uuid NOT NULL,
+  category text NOT NULL CHECK (category = ANY (ARRAY['account'::text, 'product'::text, 'digest'::text])),
+  channel text NOT NULL CHECK (channel = ANY (ARRAY['email'::text])),
+  enabled boolean NOT NULL,
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, category, channel),
+  CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+-- Emails of a category the recipient disabled are recorded as skipped instead of being sent
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text])),
+  ADD COLUMN category text;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS public.idx_notifications_idempotency_key;
+
+DELETE FROM public.notifications WHERE status = 'duplicate';
+
+ALTER TABLE public.notifications
+  DROP COLUMN IF EXISTS duplicate_of,
+  DROP COLUMN IF EXISTS correlation_id,
+  DROP COLUMN IF EXISTS idempotency_key,
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text]));
@@ -0,0 +1,11 @@
+-- Emails carry the idempotency key and correlation ID of their request. Emails requested again
+-- with the key of an email already delivered are recorded as duplicates of it
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text])),
+  ADD COLUMN idempotency_key text,
+  ADD COLUMN correlation_id text,
+  ADD COLUMN duplicate_of uuid;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_idempotency_key ON public.notifications (idempotency_key, created_at DESC)
+  WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Emails held back by a rate limit are recorded as deferred, and as dropped once held back for too long
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text, 'deferred'::text, 'dropped'::text]));
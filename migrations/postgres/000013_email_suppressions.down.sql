@@ -0,0 +1,7 @@
+DELETE FROM public.notifications WHERE status = 'suppressed';
+
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text, 'deferred'::text, 'dropped'::text]));
+
+DROP TABLE IF EXISTS public.email_suppressions;
@@ -0,0 +1,20 @@
+-- Addresses that bounced or complained are not sent emails anymore: permanently after a hard
+-- bounce or a complaint, and until suppressed_until after a soft bounce
+CREATE TABLE public.email_suppressions (
+  recipient text NOT NULL,
+  reason text NOT NULL CHECK (reason = ANY (ARRAY['hard_bounce'::text, 'soft_bounce'::text, 'complaint'::text])),
+  detail text,
+  source text NOT NULL,
+  soft_bounces integer NOT NULL DEFAULT 0,
+  suppressed_until timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT email_suppressions_pkey PRIMARY KEY (recipient)
+);
+
+CREATE INDEX idx_email_suppressions_updated_at ON public.email_suppressions USING btree (updated_at DESC);
+
+-- Emails to a suppressed address are recorded as suppressed instead of being sent
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text, 'deferred'::text, 'dropped'::text, 'suppressed'::text]));
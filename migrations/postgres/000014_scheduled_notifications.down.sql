@@ -0,0 +1,5 @@
+DELETE FROM public.notifications WHERE status IN ('scheduled', 'cancelled');
+
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text, 'deferred'::text, 'dropped'::text, 'suppressed'::text]));
@@ -0,0 +1,5 @@
+-- Emails requested with a send time in the future are recorded as scheduled, and as cancelled
+-- when they are cancelled before being sent
+ALTER TABLE public.notifications
+  DROP CONSTRAINT notifications_status_check,
+  ADD CONSTRAINT notifications_status_check CHECK (status = ANY (ARRAY['pending'::text, 'sent'::text, 'failed'::text, 'skipped'::text, 'duplicate'::text, 'deferred'::text, 'dropped'::text, 'suppressed'::text, 'scheduled'::text, 'cancelled'::text]));
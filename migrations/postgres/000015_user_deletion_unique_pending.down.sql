@@ -0,0 +1 @@
+DROP INDEX IF EXISTS public.user_deletions_active_user_id_key;
@@ -0,0 +1,13 @@
+-- Keep only the latest pending deletion of each user before enforcing a single one
+UPDATE public.user_deletions d
+SET status = 'cancelled', cancelled_at = now()
+WHERE d.status = ANY (ARRAY['queued'::text, 'warned'::text, 'scheduled'::text])
+  AND EXISTS (
+    SELECT 1 FROM public.user_deletions newer
+    WHERE newer.user_id = d.user_id
+      AND newer.status = ANY (ARRAY['queued'::text, 'warned'::text, 'scheduled'::text])
+      AND (newer.created_at, newer.id) > (d.created_at, d.id)
+  );
+
+-- A user has at most one queued, warned or scheduled deletion
+CREATE UNIQUE INDEX user_deletions_active_user_id_key ON public.user_deletions USING btree (user_id) WHERE (status = ANY (ARRAY['queued'::text, 'warned'::text, 'scheduled'::text]));
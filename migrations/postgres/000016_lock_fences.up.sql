@@ -0,0 +1,7 @@
+-- Greatest fencing token that wrote under each lease lock, so that writes of stale holders are rejected
+CREATE TABLE public.lock_fences (
+  name text NOT NULL,
+  fencing_token bigint NOT NULL,
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT lock_fences_pkey PRIMARY KEY (name)
+);
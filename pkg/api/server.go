@@ -9,37 +9,74 @@ import (
 	"github.com/jefersonprimer/chatear-backend/config"
 	"github.com/jefersonprimer/chatear-backend/graph"
 	"github.com/jefersonprimer/chatear-backend/infrastructure"
+	notificationApp "github.com/jefersonprimer/chatear-backend/internal/notification/application"
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	notificationInfra "github.com/jefersonprimer/chatear-backend/internal/notification/infrastructure"
+	schedulerApp "github.com/jefersonprimer/chatear-backend/internal/scheduler/application"
+	schedulerInfra "github.com/jefersonprimer/chatear-backend/internal/scheduler/infrastructure"
 	userApp "github.com/jefersonprimer/chatear-backend/internal/user/application"
+	userDomain "github.com/jefersonprimer/chatear-backend/internal/user/domain"
 	userInfra "github.com/jefersonprimer/chatear-backend/internal/user/infrastructure"
 	userHTTP "github.com/jefersonprimer/chatear-backend/presentation/http"
 	"github.com/jefersonprimer/chatear-backend/presentation/middleware"
 	"github.com/jefersonprimer/chatear-backend/shared/auth"
 )
 
-func SetupServer(cfg *config.Config) (*gin.Engine, error) {
+// SetupServer wires the HTTP and GraphQL API on top of infra, which the caller owns and closes.
+func SetupServer(cfg *config.Config, infra *infrastructure.Infrastructure) (*gin.Engine, error) {
+	// Initialize repositories
+	var userRepo userDomain.UserRepository
+	blacklistRepo := userInfra.NewRedisBlacklistRepository(infra.Redis)
+	var refreshTokenRepo userDomain.RefreshTokenRepository
+	var emailRepo userDomain.EmailRepository
+	tokenRepo := userInfra.NewTokenCache(infra.Redis)
+	var userDeletionRepo userDomain.UserDeletionRepository
+	var userLoginRepo userDomain.UserLoginRepository
+	var userDeletionCycleRepo userDomain.UserDeletionCycleRepository
+	var actionLogRepo userDomain.ActionLogRepository
+	var notificationPreferences *notificationApp.Preferences
+	var emailSuppressions *notificationApp.Suppressions
+	if infra.Postgres != nil {
+		userRepo = userInfra.NewPostgresUserRepository(infra.Postgres.Pool)
+		refreshTokenRepo = userInfra.NewPostgresRefreshTokenRepository(infra.Postgres.Pool)
+		emailRepo = userInfra.NewPostgresEmailRepository(infra.Postgres.Pool)
+		userDeletionRepo = userInfra.NewPostgresUserDeletionRepository(infra.Postgres.Pool)
+		userLoginRepo = userInfra.NewPostgresUserLoginRepository(infra.Postgres.Pool)
+		userDeletionCycleRepo = userInfra.NewPostgresUserDeletionCycleRepository(infra.Postgres.Pool)
+		actionLogRepo = userInfra.NewPostgresActionLogRepository(infra.Postgres.Pool)
+		notificationPreferences = notificationApp.NewPreferences(
+			notificationInfra.NewPostgresPreferenceRepository(infra.Postgres.Pool),
+			notificationDomain.NewUnsubscribeTokens([]byte(cfg.UnsubscribeSigningKey)),
+			cfg.UnsubscribeURL,
+		)
+		emailSuppressions = notificationApp.NewSuppressions(
+			notificationInfra.NewPostgresSuppressionRepository(infra.Postgres.Pool),
+			cfg.SuppressionPolicy(),
+		)
+	}
 
-	infra, err := infrastructure.NewInfrastructure(cfg.SupabaseURL, cfg.SupabaseAnonKey, cfg.RedisURL, cfg.NatsURL)
-	if err != nil {
-		return nil, err
+	// Initialize optional GeoIP enrichment
+	var geoIPResolver userDomain.GeoIPResolver
+	if cfg.GeoIPDatabasePath != "" {
+		resolver, err := userInfra.NewMaxMindGeoIPResolver(cfg.GeoIPDatabasePath)
+		if err != nil {
+			return nil, err
+		}
+		geoIPResolver = resolver
 	}
+	travelDetector := userDomain.NewImpossibleTravelDetector(float64(cfg.ImpossibleTravelMaxSpeedKmh))
 
-	// Initialize repositories
-	var userRepo userApp.UserRepository
-	blacklistRepo := userInfra.NewRedisBlacklistRepository(infra.Redis)
-	var refreshTokenRepo userApp.RefreshTokenRepository
-	var emailRepo userApp.EmailRepository
-	tokenRepo, err := userInfra.NewTokenCache(cfg.RedisURL)
+	// Initialize blob storage, served through signed download links
+	blobStore, err := userInfra.NewLocalBlobStore(cfg.BlobStoragePath, cfg.BlobPublicURL, []byte(cfg.BlobSigningKey))
 	if err != nil {
 		return nil, err
 	}
-	var deletionCapacityRepo userApp.DeletionCapacityRepository
-	var userDeletionRepo userApp.UserDeletionRepository
 
 	// Initialize event bus (NATS for example)
 	eventBus := userInfra.NewNATSEventBus(infra.NatsConn)
 
 	// Initialize shared services
-	tokenService := auth.NewTokenService(refreshTokenRepo, cfg.JwtSecret)
+	tokenService := auth.NewTokenService(refreshTokenRepo, cfg.JwtSecret, cfg.ElevatedTokenTTL)
 
 	// Initialize user application services
 	userAppService := userApp.NewUserApplicationService(
@@ -53,15 +90,30 @@ func SetupServer(cfg *config.Config) (*gin.Engine, error) {
 		cfg.AccessTokenTTL,
 		cfg.RefreshTokenTTL,
 		cfg.AppURL,
-		cfg.MaxEmailsPerDay,
 		userDeletionRepo,
-		deletionCapacityRepo,
+		userLoginRepo,
+		geoIPResolver,
+		travelDetector,
+		userDeletionCycleRepo,
+		actionLogRepo,
+		cfg.DeletionPolicy(),
+		cfg.DataExportCooldown,
 	)
 
 	// Initialize HTTP handlers
-	userHandler := userHTTP.NewUserHandlers(userAppService)
+	userHandler := userHTTP.NewUserHandlers(userAppService, userHTTP.SessionCookieConfig{
+		Enabled:      cfg.SessionCookieMode,
+		Domain:       cfg.SessionCookieDomain,
+		Secure:       cfg.SessionCookieSecure,
+		SameSite:     userHTTP.ParseSameSite(cfg.SessionCookieSameSite),
+		RefreshPaths: []string{"/api/v1/refresh-token", "/api/v1/logout"},
+		MaxAge:       cfg.RefreshTokenTTL,
+	})
+
+	blobHandler := userHTTP.NewBlobHandlers(blobStore)
 
 	r := gin.Default()
+	r.Use(middleware.RequestIDMiddleware(), middleware.LocaleMiddleware(), middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
 
 	// Public routes
 	publicRoutes := r.Group("/api/v1")
@@ -69,8 +121,22 @@ func SetupServer(cfg *config.Config) (*gin.Engine, error) {
 		publicRoutes.POST("/register", userHandler.Register)
 		publicRoutes.POST("/login", userHandler.Login)
 		publicRoutes.GET("/verify-email", userHandler.VerifyEmail)
-		publicRoutes.POST("/refresh-token", userHandler.RefreshToken)
+		publicRoutes.POST("/refresh-token", middleware.CSRFMiddleware(), userHandler.RefreshToken)
 		publicRoutes.POST("/resend-verification-email", userHandler.ResendVerificationEmail)
+		publicRoutes.POST("/revoke-sessions", userHandler.RevokeSessions)
+		publicRoutes.POST("/verify-login", userHandler.VerifyLogin)
+		publicRoutes.POST("/cancel-account-deletion", userHandler.CancelAccountDeletion)
+		publicRoutes.POST("/confirm-email-change", userHandler.ConfirmEmailChange)
+		publicRoutes.GET("/blobs/*key", blobHandler.Download)
+		if notificationPreferences != nil {
+			unsubscribeHandler := userHTTP.NewUnsubscribeHandlers(notificationPreferences, cfg.AppURL)
+			publicRoutes.GET("/unsubscribe", unsubscribeHandler.ShowUnsubscribe)
+			publicRoutes.POST("/unsubscribe", unsubscribeHandler.Unsubscribe)
+		}
+		if emailSuppressions != nil && cfg.EmailWebhookSecret != "" {
+			suppressionHandler := userHTTP.NewSuppressionHandlers(emailSuppressions, notificationInfra.NewFeedbackParsers(), cfg.EmailWebhookSecret)
+			publicRoutes.POST("/webhooks/email/:provider", suppressionHandler.ReceiveFeedback)
+		}
 
 		// Health check routes
 		healthHandler := userHTTP.NewHealthHandler(infra, cfg)
@@ -83,19 +149,59 @@ func SetupServer(cfg *config.Config) (*gin.Engine, error) {
 	authRoutes.Use(auth.AuthMiddleware(tokenService, blacklistRepo))
 	{
 		authRoutes.GET("/me", userHandler.GetMe)
-		authRoutes.POST("/logout", userHandler.Logout)
+		authRoutes.POST("/logout", middleware.CSRFMiddleware(), userHandler.Logout)
+		authRoutes.POST("/reauthenticate", userHandler.Reauthenticate)
+		authRoutes.POST("/sessions/revoke", auth.RequireRecentAuth(cfg.ReauthenticationWindow), userHandler.RevokeAllSessions)
+		authRoutes.POST("/me/data-export", auth.RequireRecentAuth(cfg.ReauthenticationWindow), userHandler.RequestDataExport)
+		authRoutes.POST("/me/email", auth.RequireRecentAuth(cfg.ReauthenticationWindow), userHandler.RequestEmailChange)
+	}
+
+	// Admin routes
+	adminHandler := userHTTP.NewAdminHandlers(cfg.DeletionPolicy())
+	adminRoutes := r.Group("/api/v1/admin")
+	adminRoutes.Use(auth.AuthMiddleware(tokenService, blacklistRepo), auth.RequireAdmin(cfg.AdminUserIDs))
+	{
+		adminRoutes.GET("/deletion-policy", adminHandler.GetDeletionPolicy)
+
+		// Scheduled jobs run in the workers; their definitions and run history, like the email
+		// delivery history, are read from PostgreSQL
+		if infra.Postgres != nil {
+			jobService := schedulerApp.NewJobService(
+				schedulerInfra.NewPostgresJobDefinitionRepository(infra.Postgres.Pool),
+				schedulerInfra.NewPostgresJobRunRepository(infra.Postgres.Pool),
+				schedulerInfra.NewNATSJobTriggerPublisher(infra.NatsConn),
+			)
+			jobHandler := userHTTP.NewJobHandlers(jobService)
+			adminRoutes.GET("/jobs", jobHandler.ListJobs)
+			adminRoutes.GET("/jobs/:name/runs", jobHandler.ListJobRuns)
+			adminRoutes.POST("/jobs/:name/trigger", jobHandler.TriggerJob)
+
+			notificationHandler := userHTTP.NewNotificationHandlers(notificationApp.NewDeliveryHistory(notificationInfra.NewPostgresRepository(infra.Postgres.Pool)))
+			adminRoutes.GET("/notifications", notificationHandler.ListEmailSends)
+			adminRoutes.GET("/notifications/:id", notificationHandler.GetEmailSend)
+
+			suppressionHandler := userHTTP.NewSuppressionHandlers(emailSuppressions, notificationInfra.NewFeedbackParsers(), cfg.EmailWebhookSecret)
+			adminRoutes.GET("/suppressions", suppressionHandler.ListSuppressions)
+			adminRoutes.DELETE("/suppressions/:recipient", suppressionHandler.DeleteSuppression)
+		}
 	}
 
 	// GraphQL setup
 	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
 		Resolvers: &graph.Resolver{
-			UserAppService: userAppService,
-			TokenService:   tokenService,
+			UserAppService:          userAppService,
+			TokenService:            tokenService,
+			NotificationPreferences: notificationPreferences,
+			EmailSuppressions:       emailSuppressions,
+		},
+		Directives: graph.DirectiveRoot{
+			RecentAuth: graph.RecentAuthDirective(cfg.ReauthenticationWindow),
 		},
 	}))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 
 	graphqlHandler := gin.WrapH(srv)
-	r.POST("/graphql", middleware.GinContextToContextMiddleware(), graphqlHandler)
+	r.POST("/graphql", auth.OptionalAuthMiddleware(tokenService, blacklistRepo), middleware.GinContextToContextMiddleware(), graphqlHandler)
 
 	r.GET("/playground", gin.WrapH(playground.Handler("GraphQL playground", "/graphql")))
 
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// AdminHandlers handles HTTP requests for administration endpoints
+type AdminHandlers struct {
+	deletionPolicy domain.DeletionPolicy
+}
+
+// NewAdminHandlers creates a new admin handlers instance
+func NewAdminHandlers(deletionPolicy domain.DeletionPolicy) *AdminHandlers {
+	return &AdminHandlers{deletionPolicy: deletionPolicy}
+}
+
+// deletionPolicyResponse is the JSON representation of a domain.DeletionPolicy, with durations as strings.
+type deletionPolicyResponse struct {
+	GracePeriod         string   `json:"grace_period"`
+	WarningSchedule     []string `json:"warning_schedule"`
+	DailyCapacity       int      `json:"daily_capacity"`
+	HardDeleteRetention string   `json:"hard_delete_retention"`
+	MaxCycles           int      `json:"max_cycles"`
+	CycleWindow         string   `json:"cycle_window"`
+	RequestCooldown     string   `json:"request_cooldown"`
+}
+
+// GetDeletionPolicy handles GET /admin/deletion-policy
+func (h *AdminHandlers) GetDeletionPolicy(c *gin.Context) {
+	policy := h.deletionPolicy
+
+	reminders := policy.Reminders()
+	warningSchedule := make([]string, len(reminders))
+	for i, offset := range reminders {
+		warningSchedule[i] = offset.String()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deletion_policy": deletionPolicyResponse{
+		GracePeriod:         policy.GracePeriod.String(),
+		WarningSchedule:     warningSchedule,
+		DailyCapacity:       policy.DailyCapacity,
+		HardDeleteRetention: policy.HardDeleteRetention.String(),
+		MaxCycles:           policy.Cycles.MaxCycles,
+		CycleWindow:         policy.Cycles.Window.String(),
+		RequestCooldown:     policy.Cycles.Cooldown.String(),
+	}})
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignedBlobOpener opens blobs requested through signed download links.
+type SignedBlobOpener interface {
+	OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error)
+}
+
+// BlobHandlers handles HTTP requests for signed blob downloads
+type BlobHandlers struct {
+	blobs SignedBlobOpener
+}
+
+// NewBlobHandlers creates a new blob handlers instance
+func NewBlobHandlers(blobs SignedBlobOpener) *BlobHandlers {
+	return &BlobHandlers{blobs: blobs}
+}
+
+// Download handles GET /blobs/*key
+func (h *BlobHandlers) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	blob, err := h.blobs.OpenSigned(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+	defer blob.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", `attachment; filename="`+path.Base(key)+`"`)
+	c.DataFromReader(http.StatusOK, -1, contentType, blob, nil)
+}
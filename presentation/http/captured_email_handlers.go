@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+)
+
+// CapturedEmailHandlers handles HTTP requests inspecting the emails captured by the in-memory
+// email transport. They are only served by the notification worker, on a development address.
+type CapturedEmailHandlers struct {
+	mailbox notificationDomain.Mailbox
+}
+
+// NewCapturedEmailHandlers creates a new captured email handlers instance
+func NewCapturedEmailHandlers(mailbox notificationDomain.Mailbox) *CapturedEmailHandlers {
+	return &CapturedEmailHandlers{mailbox: mailbox}
+}
+
+// capturedEmailResponse is the JSON representation of a notificationDomain.CapturedEmail.
+// Unlike the delivery history, it holds the bodies: the links they contain are what developers
+// and tests are looking for.
+type capturedEmailResponse struct {
+	ID         string    `json:"id"`
+	Recipient  string    `json:"recipient"`
+	Subject    string    `json:"subject"`
+	Body       string    `json:"body"`
+	HTMLBody   string    `json:"html_body,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+func newCapturedEmailResponse(email *notificationDomain.CapturedEmail) capturedEmailResponse {
+	return capturedEmailResponse{
+		ID:         email.ID,
+		Recipient:  email.Recipient,
+		Subject:    email.Subject,
+		Body:       email.Body,
+		HTMLBody:   email.HTMLBody,
+		CapturedAt: email.CapturedAt,
+	}
+}
+
+// ListCapturedEmails handles GET /emails, optionally filtered by ?recipient=
+func (h *CapturedEmailHandlers) ListCapturedEmails(c *gin.Context) {
+	recipient := c.Query("recipient")
+	responses := []capturedEmailResponse{}
+	for _, email := range h.mailbox.List() {
+		if recipient == "" || email.Recipient == recipient {
+			responses = append(responses, newCapturedEmailResponse(email))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"emails": responses})
+}
+
+// GetCapturedEmail handles GET /emails/:id
+func (h *CapturedEmailHandlers) GetCapturedEmail(c *gin.Context) {
+	email, err := h.mailbox.Get(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email": newCapturedEmailResponse(email)})
+}
+
+// GetCapturedEmailRaw handles GET /emails/:id/raw, returning the MIME message as sent
+func (h *CapturedEmailHandlers) GetCapturedEmailRaw(c *gin.Context) {
+	email, err := h.mailbox.Get(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "message/rfc822", email.Raw)
+}
+
+// ClearCapturedEmails handles DELETE /emails
+func (h *CapturedEmailHandlers) ClearCapturedEmails(c *gin.Context) {
+	h.mailbox.Clear()
+	c.Status(http.StatusNoContent)
+}
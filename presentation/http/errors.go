@@ -0,0 +1,22 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+// RespondWithError aborts the request with the error envelope and the status code matching err.
+func RespondWithError(c *gin.Context, err error) {
+	appErr := apperrors.MapError(err)
+	if appErr.Code == apperrors.CodeInternal {
+		fmt.Printf("Error: %s %s: %v\n", c.Request.Method, c.Request.URL.Path, err)
+	}
+	c.AbortWithStatusJSON(apperrors.HTTPStatus(appErr.Code), apperrors.NewErrorResponse(c.Request.Context(), appErr))
+}
+
+// respondWithInvalidInput aborts the request with an INVALID_INPUT envelope, e.g. for binding errors.
+func respondWithInvalidInput(c *gin.Context, err error) {
+	RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", err.Error()))
+}
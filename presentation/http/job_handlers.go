@@ -0,0 +1,149 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	schedulerApp "github.com/jefersonprimer/chatear-backend/internal/scheduler/application"
+	schedulerDomain "github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	"github.com/jefersonprimer/chatear-backend/shared/auth"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+const (
+	defaultJobRunsLimit = 20
+	maxJobRunsLimit     = 100
+)
+
+// JobHandlers handles HTTP requests for the administration of scheduled jobs
+type JobHandlers struct {
+	jobService *schedulerApp.JobService
+}
+
+// NewJobHandlers creates a new job handlers instance
+func NewJobHandlers(jobService *schedulerApp.JobService) *JobHandlers {
+	return &JobHandlers{jobService: jobService}
+}
+
+// jobResponse is the JSON representation of a schedulerDomain.JobDefinition, with the timeout as a string.
+type jobResponse struct {
+	Name        string          `json:"name"`
+	Cron        string          `json:"cron"`
+	Timeout     string          `json:"timeout"`
+	Concurrency string          `json:"concurrency"`
+	NextRunAt   *time.Time      `json:"next_run_at,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	LastRun     *jobRunResponse `json:"last_run,omitempty"`
+}
+
+// jobRunResponse is the JSON representation of a schedulerDomain.JobRun.
+type jobRunResponse struct {
+	ID           string     `json:"id"`
+	JobName      string     `json:"job_name"`
+	Trigger      string     `json:"trigger"`
+	TriggeredBy  *string    `json:"triggered_by,omitempty"`
+	ScheduledAt  *time.Time `json:"scheduled_at,omitempty"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Duration     string     `json:"duration,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	FencingToken *int64     `json:"fencing_token,omitempty"`
+}
+
+func newJobRunResponse(run *schedulerDomain.JobRun) *jobRunResponse {
+	response := &jobRunResponse{
+		ID:           run.ID.String(),
+		JobName:      run.JobName,
+		Trigger:      string(run.Trigger),
+		ScheduledAt:  run.ScheduledAt,
+		Status:       string(run.Status),
+		CreatedAt:    run.CreatedAt,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Error:        run.Error,
+		FencingToken: run.FencingToken,
+	}
+	if run.TriggeredBy != nil {
+		triggeredBy := run.TriggeredBy.String()
+		response.TriggeredBy = &triggeredBy
+	}
+	if run.StartedAt != nil && run.FinishedAt != nil {
+		response.Duration = run.FinishedAt.Sub(*run.StartedAt).String()
+	}
+	return response
+}
+
+// ListJobs handles GET /admin/jobs
+func (h *JobHandlers) ListJobs(c *gin.Context) {
+	summaries, err := h.jobService.ListJobs(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	jobs := make([]jobResponse, len(summaries))
+	for i, summary := range summaries {
+		definition := summary.Definition
+		jobs[i] = jobResponse{
+			Name:        definition.Name,
+			Cron:        definition.Cron,
+			Timeout:     definition.Timeout.String(),
+			Concurrency: string(definition.Concurrency),
+			NextRunAt:   definition.NextRunAt,
+			UpdatedAt:   definition.UpdatedAt,
+		}
+		if summary.LastRun != nil {
+			jobs[i].LastRun = newJobRunResponse(summary.LastRun)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// ListJobRuns handles GET /admin/jobs/:name/runs
+func (h *JobHandlers) ListJobRuns(c *gin.Context) {
+	limit := defaultJobRunsLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxJobRunsLimit {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "limit must be between 1 and "+strconv.Itoa(maxJobRunsLimit)))
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.jobService.ListJobRuns(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	responses := make([]*jobRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = newJobRunResponse(run)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": responses})
+}
+
+// TriggerJob handles POST /admin/jobs/:name/trigger
+func (h *JobHandlers) TriggerJob(c *gin.Context) {
+	adminID, err := auth.GetUserIDFromContext(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
+		return
+	}
+
+	run, err := h.jobService.TriggerJob(c.Request.Context(), c.Param("name"), adminID)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"run": newJobRunResponse(run)})
+}
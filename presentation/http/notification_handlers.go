@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	notificationApp "github.com/jefersonprimer/chatear-backend/internal/notification/application"
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+const (
+	defaultEmailSendsLimit = 50
+	maxEmailSendsLimit     = 200
+)
+
+// NotificationHandlers handles HTTP requests for the email delivery history
+type NotificationHandlers struct {
+	history *notificationApp.DeliveryHistory
+}
+
+// NewNotificationHandlers creates a new notification handlers instance
+func NewNotificationHandlers(history *notificationApp.DeliveryHistory) *NotificationHandlers {
+	return &NotificationHandlers{history: history}
+}
+
+// emailSendResponse is the JSON representation of a notificationDomain.EmailSend. The body is
+// left out, since it may hold sign-in and verification links.
+type emailSendResponse struct {
+	ID             string     `json:"id"`
+	Recipient      string     `json:"recipient"`
+	Subject        string     `json:"subject"`
+	Template       string     `json:"template,omitempty"`
+	Category       string     `json:"category,omitempty"`
+	IdempotencyKey string     `json:"idempotency_key,omitempty"`
+	CorrelationID  string     `json:"correlation_id,omitempty"`
+	DuplicateOf    string     `json:"duplicate_of,omitempty"`
+	Status         string     `json:"status"`
+	Error          string     `json:"error,omitempty"`
+	Attempts       int        `json:"attempts"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+}
+
+func newEmailSendResponse(emailSend *notificationDomain.EmailSend) emailSendResponse {
+	response := emailSendResponse{
+		ID:             emailSend.ID,
+		Recipient:      emailSend.Recipient,
+		Subject:        emailSend.Subject,
+		Template:       emailSend.TemplateName,
+		Category:       emailSend.Category,
+		IdempotencyKey: emailSend.IdempotencyKey,
+		CorrelationID:  emailSend.CorrelationID,
+		DuplicateOf:    emailSend.DuplicateOf,
+		Status:         emailSend.Status,
+		Error:          emailSend.ErrorMessage,
+		Attempts:       emailSend.Attempts,
+		CreatedAt:      emailSend.CreatedAt,
+		LastAttemptAt:  emailSend.LastAttemptAt,
+	}
+	if emailSend.Status == notificationDomain.EmailSendStatusSent {
+		sentAt := emailSend.SentAt
+		response.SentAt = &sentAt
+	}
+	return response
+}
+
+// ListEmailSends handles GET /admin/notifications
+func (h *NotificationHandlers) ListEmailSends(c *gin.Context) {
+	filter := notificationDomain.EmailSendFilter{
+		Recipient: c.Query("recipient"),
+		Status:    c.Query("status"),
+		Limit:     defaultEmailSendsLimit,
+	}
+	switch filter.Status {
+	case "", notificationDomain.EmailSendStatusPending, notificationDomain.EmailSendStatusSent, notificationDomain.EmailSendStatusFailed,
+		notificationDomain.EmailSendStatusSkipped, notificationDomain.EmailSendStatusDuplicate, notificationDomain.EmailSendStatusDeferred,
+		notificationDomain.EmailSendStatusDropped, notificationDomain.EmailSendStatusSuppressed, notificationDomain.EmailSendStatusScheduled,
+		notificationDomain.EmailSendStatusCancelled:
+	default:
+		RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "status must be pending, sent, failed, skipped, duplicate, deferred, dropped, suppressed, scheduled or cancelled"))
+		return
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxEmailSendsLimit {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "limit must be between 1 and "+strconv.Itoa(maxEmailSendsLimit)))
+			return
+		}
+		filter.Limit = limit
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "offset must be a non-negative integer"))
+			return
+		}
+		filter.Offset = offset
+	}
+
+	emailSends, err := h.history.List(c.Request.Context(), filter)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	responses := make([]emailSendResponse, len(emailSends))
+	for i, emailSend := range emailSends {
+		responses[i] = newEmailSendResponse(emailSend)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": responses, "limit": filter.Limit, "offset": filter.Offset})
+}
+
+// GetEmailSend handles GET /admin/notifications/:id
+func (h *NotificationHandlers) GetEmailSend(c *gin.Context) {
+	emailSend, err := h.history.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notification": newEmailSendResponse(emailSend)})
+}
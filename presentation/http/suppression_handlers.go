@@ -0,0 +1,144 @@
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	notificationApp "github.com/jefersonprimer/chatear-backend/internal/notification/application"
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+// maxFeedbackBody limits the size of the webhook payloads of the email providers.
+const maxFeedbackBody = 1 << 20
+
+// SuppressionHandlers handles the bounce and complaint webhooks of the email providers and the
+// administration of the suppression list
+type SuppressionHandlers struct {
+	suppressions  *notificationApp.Suppressions
+	parsers       map[string]notificationDomain.FeedbackParser
+	webhookSecret string
+}
+
+// NewSuppressionHandlers creates a new suppression handlers instance. Webhooks must authenticate
+// with webhookSecret and are parsed by the parser of their provider.
+func NewSuppressionHandlers(suppressions *notificationApp.Suppressions, parsers map[string]notificationDomain.FeedbackParser, webhookSecret string) *SuppressionHandlers {
+	return &SuppressionHandlers{suppressions: suppressions, parsers: parsers, webhookSecret: webhookSecret}
+}
+
+// suppressionResponse is the JSON representation of a notificationDomain.Suppression.
+type suppressionResponse struct {
+	Recipient       string     `json:"recipient"`
+	Reason          string     `json:"reason"`
+	Detail          string     `json:"detail,omitempty"`
+	Source          string     `json:"source"`
+	SoftBounces     int        `json:"soft_bounces"`
+	SuppressedUntil *time.Time `json:"suppressed_until,omitempty"`
+	Active          bool       `json:"active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func newSuppressionResponse(suppression *notificationDomain.Suppression, now time.Time) suppressionResponse {
+	return suppressionResponse{
+		Recipient:       suppression.Recipient,
+		Reason:          suppression.Reason,
+		Detail:          suppression.Detail,
+		Source:          suppression.Source,
+		SoftBounces:     suppression.SoftBounces,
+		SuppressedUntil: suppression.SuppressedUntil,
+		Active:          suppression.Active(now),
+		CreatedAt:       suppression.CreatedAt,
+		UpdatedAt:       suppression.UpdatedAt,
+	}
+}
+
+// ReceiveFeedback handles POST /webhooks/email/:provider. Providers authenticate with the webhook
+// secret, as the token query parameter or the password of HTTP basic authentication, which most
+// of them support in the webhook URL.
+func (h *SuppressionHandlers) ReceiveFeedback(c *gin.Context) {
+	secret := c.Query("token")
+	if _, password, ok := c.Request.BasicAuth(); ok {
+		secret = password
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeUnauthorized, "error.unauthorized", "invalid webhook secret"))
+		return
+	}
+	provider := c.Param("provider")
+	parser, ok := h.parsers[provider]
+	if !ok {
+		RespondWithError(c, fmt.Errorf("%w: %s", notificationDomain.ErrUnknownProvider, provider))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxFeedbackBody))
+	if err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+	feedback, err := parser.ParseFeedback(body)
+	if err != nil {
+		fmt.Printf("Warning: invalid %s email webhook: %v\n", provider, err)
+		RespondWithError(c, err)
+		return
+	}
+	if err := h.suppressions.RecordAll(c.Request.Context(), feedback); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recorded": len(feedback)})
+}
+
+// ListSuppressions handles GET /admin/suppressions
+func (h *SuppressionHandlers) ListSuppressions(c *gin.Context) {
+	limit, offset := defaultEmailSendsLimit, 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxEmailSendsLimit {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "limit must be between 1 and "+strconv.Itoa(maxEmailSendsLimit)))
+			return
+		}
+		limit = parsed
+	}
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "offset must be a non-negative integer"))
+			return
+		}
+		offset = parsed
+	}
+
+	suppressions, err := h.suppressions.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	now := time.Now()
+	responses := make([]suppressionResponse, len(suppressions))
+	for i, suppression := range suppressions {
+		responses[i] = newSuppressionResponse(suppression, now)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suppressions": responses, "limit": limit, "offset": offset})
+}
+
+// DeleteSuppression handles DELETE /admin/suppressions/:recipient, so that the address is sent
+// emails again
+func (h *SuppressionHandlers) DeleteSuppression(c *gin.Context) {
+	if err := h.suppressions.Remove(c.Request.Context(), c.Param("recipient")); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suppression removed successfully"})
+}
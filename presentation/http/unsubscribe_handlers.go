@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	notificationApp "github.com/jefersonprimer/chatear-backend/internal/notification/application"
+)
+
+// UnsubscribeHandlers handles the unsubscribe links of emails, which work without signing in
+type UnsubscribeHandlers struct {
+	preferences *notificationApp.Preferences
+	appURL      string
+}
+
+// NewUnsubscribeHandlers creates a new unsubscribe handlers instance. Opened links are redirected
+// to the unsubscribe page of the frontend at appURL.
+func NewUnsubscribeHandlers(preferences *notificationApp.Preferences, appURL string) *UnsubscribeHandlers {
+	return &UnsubscribeHandlers{preferences: preferences, appURL: appURL}
+}
+
+// ShowUnsubscribe handles GET /unsubscribe. Mailbox providers and link scanners open links in
+// emails, so opening one only redirects to the frontend, which asks the user to confirm.
+func (h *UnsubscribeHandlers) ShowUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		RespondWithError(c, errTokenRequired)
+		return
+	}
+
+	c.Redirect(http.StatusFound, h.appURL+"/unsubscribe?token="+url.QueryEscape(token))
+}
+
+// Unsubscribe handles POST /unsubscribe, the one-click unsubscribe of the List-Unsubscribe-Post
+// header (RFC 8058), which mail clients send with the token of the link in the query string.
+func (h *UnsubscribeHandlers) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		RespondWithError(c, errTokenRequired)
+		return
+	}
+
+	preference, err := h.preferences.Unsubscribe(c.Request.Context(), token)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Unsubscribed successfully",
+		"category": preference.Category,
+		"channel":  preference.Channel,
+	})
+}
@@ -1,23 +1,35 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	
 	"github.com/jefersonprimer/chatear-backend/internal/user/application"
 	"github.com/jefersonprimer/chatear-backend/shared/auth"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+)
+
+var (
+	errRefreshTokenRequired = apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.refresh_token_required", "")
+	errTokenRequired        = apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.token_required", "")
 )
 
 // UserHandlers handles HTTP requests for user operations
 type UserHandlers struct {
-	userService *application.UserApplicationService
+	userService    *application.UserApplicationService
+	sessionCookies SessionCookieConfig
 }
 
 // NewUserHandlers creates a new user handlers instance
-func NewUserHandlers(userService *application.UserApplicationService) *UserHandlers {
+func NewUserHandlers(userService *application.UserApplicationService, sessionCookies SessionCookieConfig) *UserHandlers {
 	return &UserHandlers{
-		userService: userService,
+		userService:    userService,
+		sessionCookies: sessionCookies,
 	}
 }
 
@@ -27,16 +39,27 @@ func (h *UserHandlers) Register(c *gin.Context) {
 		Name     string `json:"name" binding:"required"`
 		Email    string `json:"email" binding:"required,email"`
 		Password string `json:"password" binding:"required,min=8"`
+		Locale   string `json:"locale"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithInvalidInput(c, err)
 		return
 	}
 
-	authTokens, user, err := h.userService.Register(c.Request.Context(), req.Name, req.Email, req.Password)
+	// The locale preference defaults to the one negotiated from Accept-Language
+	locale := i18n.LocaleFromContext(c.Request.Context())
+	if req.Locale != "" {
+		var ok bool
+		if locale, ok = i18n.Match(req.Locale); !ok {
+			RespondWithError(c, apperrors.NewLocalizedAppError(apperrors.CodeInvalidInput, "error.invalid_request", "locale must be one of "+strings.Join(i18n.Locales, ", ")))
+			return
+		}
+	}
+
+	authTokens, user, err := h.userService.Register(c.Request.Context(), req.Name, req.Email, req.Password, locale)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondWithError(c, err)
 		return
 	}
 
@@ -56,7 +79,7 @@ func (h *UserHandlers) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithInvalidInput(c, err)
 		return
 	}
 
@@ -65,7 +88,21 @@ func (h *UserHandlers) Login(c *gin.Context) {
 
 	loginResponse, err := h.userService.Login(c.Request.Context(), req.Email, req.Password, ipAddress, userAgent)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		RespondWithError(c, err)
+		return
+	}
+
+	if h.usesCookieSession(c) {
+		csrfToken, err := h.setSessionCookies(c, loginResponse.RefreshToken)
+		if err != nil {
+			RespondWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Logged in successfully",
+			"access_token": loginResponse.AccessToken,
+			"csrf_token":   csrfToken,
+		})
 		return
 	}
 
@@ -79,11 +116,18 @@ func (h *UserHandlers) Login(c *gin.Context) {
 // Logout handles POST /logout
 func (h *UserHandlers) Logout(c *gin.Context) {
 	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
+		RefreshToken string `json:"refresh_token"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// In cookie session mode the body may be empty and the refresh token comes from the cookie
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	refreshToken := h.refreshTokenFromRequest(c, req.RefreshToken)
+	if refreshToken == "" {
+		RespondWithError(c, errRefreshTokenRequired)
 		return
 	}
 
@@ -93,17 +137,165 @@ func (h *UserHandlers) Logout(c *gin.Context) {
 		accessToken = authHeader[7:]
 	}
 
-	if err := h.userService.Logout(c.Request.Context(), accessToken, req.RefreshToken); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.Logout(c.Request.Context(), accessToken, refreshToken); err != nil {
+		RespondWithError(c, err)
 		return
 	}
 
+	if h.usesCookieSession(c) {
+		h.clearSessionCookies(c)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
+// Reauthenticate handles POST /reauthenticate
+func (h *UserHandlers) Reauthenticate(c *gin.Context) {
+	var req struct {
+		Password string `json:"password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	userID, err := auth.GetUserIDFromContext(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
+		return
+	}
+
+	elevatedToken, err := h.userService.Reauthenticate(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"elevated_token": elevatedToken})
+}
+
+// RevokeAllSessions handles POST /sessions/revoke
+func (h *UserHandlers) RevokeAllSessions(c *gin.Context) {
+	userID, err := auth.GetUserIDFromContext(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
+		return
+	}
+
+	if err := h.userService.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions have been revoked"})
+}
+
+// RequestDataExport handles POST /me/data-export
+func (h *UserHandlers) RequestDataExport(c *gin.Context) {
+	userID, err := auth.GetUserIDFromContext(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
+		return
+	}
+
+	if err := h.userService.RequestDataExport(c.Request.Context(), userID); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Your data export is being prepared. You will receive a download link by email"})
+}
+
+// RequestEmailChange handles POST /me/email
+func (h *UserHandlers) RequestEmailChange(c *gin.Context) {
+	userID, err := auth.GetUserIDFromContext(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
+		return
+	}
+
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	if err := h.userService.RequestEmailChange(c.Request.Context(), userID, req.Email); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "A confirmation link has been sent to the new email address"})
+}
+
+// ConfirmEmailChange handles POST /confirm-email-change
+func (h *UserHandlers) ConfirmEmailChange(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	if err := h.userService.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed successfully"})
+}
+
 // RefreshToken handles POST /refresh-token
 func (h *UserHandlers) RefreshToken(c *gin.Context) {
-	// Implementation for refreshing a token
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// In cookie session mode the body may be empty and the refresh token comes from the cookie
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	refreshToken := h.refreshTokenFromRequest(c, req.RefreshToken)
+	if refreshToken == "" {
+		RespondWithError(c, errRefreshTokenRequired)
+		return
+	}
+
+	cookieSession := h.usesCookieSession(c)
+	authTokens, _, err := h.userService.RefreshToken(c.Request.Context(), refreshToken)
+	if err != nil {
+		if cookieSession {
+			h.clearSessionCookies(c)
+		}
+		RespondWithError(c, err)
+		return
+	}
+
+	if cookieSession {
+		csrfToken, err := h.setSessionCookies(c, authTokens.RefreshToken)
+		if err != nil {
+			RespondWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"access_token": authTokens.AccessToken,
+			"csrf_token":   csrfToken,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  authTokens.AccessToken,
+		"refresh_token": authTokens.RefreshToken,
+	})
 }
 
 func (h *UserHandlers) ResendVerificationEmail(c *gin.Context) {
@@ -111,12 +303,12 @@ func (h *UserHandlers) ResendVerificationEmail(c *gin.Context) {
 		Email string `json:"email"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithInvalidInput(c, err)
 		return
 	}
 
 	if err := h.userService.ResendVerificationEmail(c.Request.Context(), req.Email); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondWithError(c, err)
 		return
 	}
 
@@ -127,29 +319,94 @@ func (h *UserHandlers) ResendVerificationEmail(c *gin.Context) {
 func (h *UserHandlers) VerifyEmail(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		RespondWithError(c, errTokenRequired)
 		return
 	}
 
 	if err := h.userService.VerifyEmail(c.Request.Context(), token); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
 }
 
+// VerifyLogin handles POST /verify-login
+func (h *UserHandlers) VerifyLogin(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	authTokens, user, err := h.userService.VerifyLogin(c.Request.Context(), req.Token)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Logged in successfully",
+		"access_token":  authTokens.AccessToken,
+		"refresh_token": authTokens.RefreshToken,
+		"user":          user,
+	})
+}
+
+// CancelAccountDeletion handles POST /cancel-account-deletion
+func (h *UserHandlers) CancelAccountDeletion(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	if err := h.userService.CancelAccountDeletion(c.Request.Context(), req.Token); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+}
+
+// RevokeSessions handles POST /revoke-sessions. The "this wasn't me" link of new device alerts
+// opens a confirmation page of the frontend, which posts the token of the link here, so that
+// link scanners and prefetchers opening the link do not sign the user out.
+func (h *UserHandlers) RevokeSessions(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithInvalidInput(c, err)
+		return
+	}
+
+	if err := h.userService.RevokeSessions(c.Request.Context(), req.Token); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions have been signed out"})
+}
+
 // GetMe handles GET /me
 func (h *UserHandlers) GetMe(c *gin.Context) {
-	userID, err := auth.GetUserIDFromContext(c)
+	userID, err := auth.GetUserIDFromContext(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		RespondWithError(c, apperrors.WrapLocalized(apperrors.CodeUnauthorized, "error.authentication_required", err))
 		return
 	}
 
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		RespondWithError(c, err)
 		return
 	}
 
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	customhttp "github.com/jefersonprimer/chatear-backend/presentation/http"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+)
+
+var errInvalidCSRFToken = apperrors.NewLocalizedAppError(apperrors.CodeForbidden, "error.invalid_csrf_token", "")
+
+// CSRFMiddleware enforces double-submit CSRF protection on cookie-authenticated mutations.
+// Requests that carry the refresh cookie must echo the CSRF cookie in the X-CSRF-Token header;
+// requests authenticated only by a bearer token or a body field are not affected.
+func CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if _, err := c.Cookie(customhttp.RefreshTokenCookieName); err != nil {
+			c.Next()
+			return
+		}
+
+		csrfCookie, err := c.Cookie(customhttp.CSRFTokenCookieName)
+		csrfHeader := c.GetHeader(customhttp.CSRFTokenHeader)
+		if err != nil || csrfCookie == "" || subtle.ConstantTimeCompare([]byte(csrfCookie), []byte(csrfHeader)) != 1 {
+			customhttp.RespondWithError(c, errInvalidCSRFToken)
+			return
+		}
+
+		c.Next()
+	}
+}
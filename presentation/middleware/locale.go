@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+)
+
+// LocaleMiddleware stores the locale negotiated from the Accept-Language header in the request
+// context, where error responses and registration read it.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", locale)
+		c.Header("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
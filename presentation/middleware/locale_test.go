@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LocaleMiddleware())
+	r.POST("/refresh-token", CSRFMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		locale         string
+		message        string
+	}{
+		{"", "pt-BR", "Token CSRF inválido ou ausente"},
+		{"en-US,en;q=0.9", "en", "Invalid or missing CSRF token"},
+		{"fr, pt;q=0.5", "pt-BR", "Token CSRF inválido ou ausente"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/refresh-token", nil)
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh"})
+		if tt.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, tt.locale, w.Header().Get("Content-Language"))
+		var response apperrors.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, tt.message, response.Message, tt.acceptLanguage)
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
+
+// RequestIDMiddleware tags every request with an ID, reusing the client's X-Request-ID when present.
+// The ID is echoed in the response header and reported in error responses.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(util.RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = util.NewRequestID()
+		}
+
+		c.Header(util.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(util.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
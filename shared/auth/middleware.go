@@ -3,11 +3,12 @@ package auth
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	userDomain "github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	apperrors "github.com/jefersonprimer/chatear-backend/shared/errors"
 )
 
 type contextKey string
@@ -16,6 +17,18 @@ const (
 	ContextKeyUserID       contextKey = "userID"
 	ContextKeyRefreshToken contextKey = "refreshToken"
 	ContextKeyAccessToken  contextKey = "accessToken"
+	ContextKeyAuthTime     contextKey = "authTime"
+	ContextKeyAuthMethods  contextKey = "authMethods"
+)
+
+var (
+	// ErrReauthenticationRequired is returned when an operation needs a recent authentication
+	// and the caller's token is older than the allowed window.
+	ErrReauthenticationRequired = apperrors.NewLocalizedAppError(apperrors.CodeReauthenticationRequired, "error.reauthentication_required", "")
+
+	errAuthorizationHeaderRequired = apperrors.NewLocalizedAppError(apperrors.CodeUnauthorized, "error.authorization_header_required", "")
+	errInvalidAccessToken          = apperrors.NewLocalizedAppError(apperrors.CodeInvalidToken, "error.invalid_token", "")
+	errAdminRequired               = apperrors.NewLocalizedAppError(apperrors.CodeForbidden, "error.admin_required", "")
 )
 
 // AuthMiddleware creates a Gin middleware for JWT authentication.
@@ -23,7 +36,41 @@ func AuthMiddleware(tokenService *TokenService, blacklistRepo userDomain.Blackli
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			abortWithError(c, errAuthorizationHeaderRequired)
+			return
+		}
+
+		tokenString := authHeader
+		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
+			tokenString = tokenString[7:]
+		}
+
+		claims, err := tokenService.ParseAccessToken(c.Request.Context(), tokenString)
+		if err != nil {
+			abortWithError(c, errInvalidAccessToken)
+			return
+		}
+
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			abortWithError(c, errInvalidAccessToken)
+			return
+		}
+
+		setAuthContext(c, userID, tokenString, claims)
+
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware creates a Gin middleware that authenticates the request when a valid
+// token is present but lets anonymous requests through. It is used by the GraphQL endpoint,
+// where each resolver decides whether it requires an authenticated user.
+func OptionalAuthMiddleware(tokenService *TokenService, blacklistRepo userDomain.BlacklistRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
@@ -32,28 +79,109 @@ func AuthMiddleware(tokenService *TokenService, blacklistRepo userDomain.Blackli
 			tokenString = tokenString[7:]
 		}
 
-		userID, err := tokenService.VerifyToken(c.Request.Context(), tokenString)
+		claims, err := tokenService.ParseAccessToken(c.Request.Context(), tokenString)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(claims.UserID)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Next()
+			return
+		}
+
+		setAuthContext(c, userID, tokenString, claims)
+
+		c.Next()
+	}
+}
+
+// setAuthContext stores the authenticated user in both the Gin context and the request context.
+func setAuthContext(c *gin.Context, userID uuid.UUID, tokenString string, claims *Claims) {
+	// Store userID in Gin context
+	c.Set(string(ContextKeyUserID), userID)
+
+	// Extract refresh token from header
+	refreshToken := c.GetHeader("X-Refresh-Token")
+
+	// Store userID, accessToken, and refreshToken in request context for GraphQL resolvers
+	ctx := context.WithValue(c.Request.Context(), ContextKeyUserID, userID)
+	ctx = context.WithValue(ctx, ContextKeyAccessToken, tokenString)
+	ctx = context.WithValue(ctx, ContextKeyRefreshToken, refreshToken)
+	if claims.AuthTime != nil {
+		ctx = context.WithValue(ctx, ContextKeyAuthTime, claims.AuthTime.Time)
+	}
+	ctx = context.WithValue(ctx, ContextKeyAuthMethods, claims.AMR)
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// RequireRecentAuth creates a Gin middleware that only lets requests through when the user
+// authenticated within the given window. Clients are expected to call the reauthenticate
+// endpoint and retry with the elevated token when it responds with 401.
+func RequireRecentAuth(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := RequireRecentAuthentication(c.Request.Context(), window); err != nil {
+			c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_user_authentication", max_age=%d`, int(window.Seconds())))
+			abortWithError(c, ErrReauthenticationRequired)
 			return
 		}
 
-		// Store userID in Gin context
-		c.Set(string(ContextKeyUserID), userID)
+		c.Next()
+	}
+}
 
-		// Extract refresh token from header
-		refreshToken := c.GetHeader("X-Refresh-Token")
+// RequireAdmin is a Gin middleware that only lets through authenticated users listed in adminUserIDs.
+// It must run after AuthMiddleware.
+func RequireAdmin(adminUserIDs []string) gin.HandlerFunc {
+	admins := make(map[uuid.UUID]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		if userID, err := uuid.Parse(id); err == nil {
+			admins[userID] = true
+		}
+	}
 
-		// Store userID, accessToken, and refreshToken in request context for GraphQL resolvers
-		ctx := context.WithValue(c.Request.Context(), ContextKeyUserID, userID)
-		ctx = context.WithValue(ctx, ContextKeyAccessToken, tokenString)
-		ctx = context.WithValue(ctx, ContextKeyRefreshToken, refreshToken)
-		c.Request = c.Request.WithContext(ctx)
+	return func(c *gin.Context) {
+		userID, err := GetUserIDFromContext(c.Request.Context())
+		if err != nil || !admins[userID] {
+			abortWithError(c, errAdminRequired)
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// abortWithError aborts the request with the error envelope for appErr.
+func abortWithError(c *gin.Context, appErr *apperrors.AppError) {
+	c.AbortWithStatusJSON(apperrors.HTTPStatus(appErr.Code), apperrors.NewErrorResponse(c.Request.Context(), appErr))
+}
+
+// RequireRecentAuthentication returns ErrReauthenticationRequired unless the context carries
+// an auth_time within the given window.
+func RequireRecentAuthentication(ctx context.Context, window time.Duration) error {
+	authTime, err := GetAuthTimeFromContext(ctx)
+	if err != nil || time.Since(authTime) > window {
+		return ErrReauthenticationRequired
+	}
+	return nil
+}
+
+// GetAuthTimeFromContext extracts the time the user last authenticated from the context.
+func GetAuthTimeFromContext(ctx context.Context) (time.Time, error) {
+	authTime, ok := ctx.Value(ContextKeyAuthTime).(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("auth time not found in context")
+	}
+	return authTime, nil
+}
+
+// GetAuthMethodsFromContext extracts the authentication methods used by the user from the context.
+func GetAuthMethodsFromContext(ctx context.Context) []string {
+	methods, _ := ctx.Value(ContextKeyAuthMethods).([]string)
+	return methods
+}
+
 // GetUserIDFromContext extracts the UserID from the context.
 func GetUserIDFromContext(ctx context.Context) (uuid.UUID, error) {
 	userID, ok := ctx.Value(ContextKeyUserID).(uuid.UUID)
@@ -15,32 +15,51 @@ import (
 
 // Claims defines the structure of our JWT claims
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID   string           `json:"user_id"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	Elevated bool             `json:"elevated,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type TokenService struct {
 	refreshTokenRepo domain.RefreshTokenRepository
 	jwtSecret        []byte
+	elevatedTokenTTL time.Duration
 }
 
 // NewTokenService creates a new TokenService
-func NewTokenService(refreshTokenRepo domain.RefreshTokenRepository, jwtSecret string) *TokenService {
+func NewTokenService(refreshTokenRepo domain.RefreshTokenRepository, jwtSecret string, elevatedTokenTTL time.Duration) *TokenService {
 	return &TokenService{
 		refreshTokenRepo: refreshTokenRepo,
 		jwtSecret:        []byte(jwtSecret),
+		elevatedTokenTTL: elevatedTokenTTL,
 	}
 }
 
-func (s *TokenService) CreateAccessToken(ctx context.Context, user *domain.User) (string, error) {
-	expirationTime := time.Now().Add(constants.AccessTokenExpiration)
+func (s *TokenService) CreateAccessToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return s.signAccessToken(user, authentication, constants.AccessTokenExpiration, false)
+}
+
+// CreateElevatedToken creates a short-lived access token proving the user has just re-authenticated.
+func (s *TokenService) CreateElevatedToken(ctx context.Context, user *domain.User, authentication domain.Authentication) (string, error) {
+	return s.signAccessToken(user, authentication, s.elevatedTokenTTL, true)
+}
+
+func (s *TokenService) signAccessToken(user *domain.User, authentication domain.Authentication, ttl time.Duration, elevated bool) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID: user.ID.String(),
+		UserID:   user.ID.String(),
+		AMR:      authentication.Methods,
+		Elevated: elevated,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if !authentication.Time.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(authentication.Time)
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(s.jwtSecret)
@@ -51,6 +70,21 @@ func (s *TokenService) CreateAccessToken(ctx context.Context, user *domain.User)
 }
 
 func (s *TokenService) VerifyToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	claims, err := s.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user ID in token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// ParseAccessToken validates an access token and returns its claims.
+func (s *TokenService) ParseAccessToken(ctx context.Context, tokenString string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -60,19 +94,14 @@ func (s *TokenService) VerifyToken(ctx context.Context, tokenString string) (uui
 	})
 
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to parse access token: %w", err)
+		return nil, fmt.Errorf("failed to parse access token: %w", err)
 	}
 
 	if !token.Valid {
-		return uuid.Nil, fmt.Errorf("invalid access token")
+		return nil, fmt.Errorf("invalid access token")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID in token: %w", err)
-	}
-
-	return userID, nil
+	return claims, nil
 }
 
 func (s *TokenService) CreateRefreshToken(ctx context.Context, user *domain.User) (string, error) {
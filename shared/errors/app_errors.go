@@ -1,6 +1,13 @@
 package errors
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
 
 // Common application errors
 var (
@@ -12,11 +19,33 @@ var (
 	ErrConflict      = errors.New("resource conflict")
 )
 
-// AppError represents an application error with additional context
+// Error codes returned to clients in the error envelope and in GraphQL error extensions.
+const (
+	CodeInvalidInput              = "INVALID_INPUT"
+	CodeUnauthorized              = "UNAUTHORIZED"
+	CodeInvalidCredentials        = "INVALID_CREDENTIALS"
+	CodeInvalidToken              = "INVALID_TOKEN"
+	CodeReauthenticationRequired  = "REAUTHENTICATION_REQUIRED"
+	CodeLoginVerificationRequired = "LOGIN_VERIFICATION_REQUIRED"
+	CodeForbidden                 = "FORBIDDEN"
+	CodeEmailNotVerified          = "EMAIL_NOT_VERIFIED"
+	CodeAccountDeleted            = "ACCOUNT_DELETED"
+	CodeNotFound                  = "NOT_FOUND"
+	CodeConflict                  = "CONFLICT"
+	CodeRateLimited               = "RATE_LIMITED"
+	CodeDeletionCooldown          = "DELETION_COOLDOWN"
+	CodeDeletionCycleLimit        = "DELETION_CYCLE_LIMIT_EXCEEDED"
+	CodeInternal                  = "INTERNAL_ERROR"
+)
+
+// AppError represents an application error with additional context. Errors with a MessageKey
+// have their message localized from the i18n catalogs when returned to clients.
 type AppError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Details    string `json:"details,omitempty"`
+	MessageKey string `json:"-"`
+	Err        error  `json:"-"`
 }
 
 // Error implements the error interface
@@ -24,6 +53,11 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, if any
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // NewAppError creates a new application error
 func NewAppError(code, message, details string) *AppError {
 	return &AppError{
@@ -31,4 +65,105 @@ func NewAppError(code, message, details string) *AppError {
 		Message: message,
 		Details: details,
 	}
-}
\ No newline at end of file
+}
+
+// Wrap creates a new application error that keeps err as its cause
+func Wrap(code, message string, err error) *AppError {
+	return &AppError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NewLocalizedAppError creates a new application error whose message is the i18n message of key
+func NewLocalizedAppError(code, key, details string) *AppError {
+	return &AppError{
+		Code:       code,
+		Message:    i18n.T(i18n.English, key),
+		Details:    details,
+		MessageKey: key,
+	}
+}
+
+// WrapLocalized creates a new localized application error that keeps err as its cause
+func WrapLocalized(code, key string, err error) *AppError {
+	return &AppError{
+		Code:       code,
+		Message:    i18n.T(i18n.English, key),
+		MessageKey: key,
+		Err:        err,
+	}
+}
+
+// LocalizedMessage returns the message of appErr in the locale of ctx.
+func LocalizedMessage(ctx context.Context, appErr *AppError) string {
+	if appErr.MessageKey == "" {
+		return appErr.Message
+	}
+	return i18n.T(i18n.LocaleFromContext(ctx), appErr.MessageKey)
+}
+
+// HTTPStatus returns the HTTP status code for an error code
+func HTTPStatus(code string) int {
+	switch code {
+	case CodeInvalidInput:
+		return http.StatusBadRequest
+	case CodeUnauthorized, CodeInvalidCredentials, CodeInvalidToken, CodeReauthenticationRequired:
+		return http.StatusUnauthorized
+	case CodeForbidden, CodeEmailNotVerified, CodeAccountDeleted, CodeLoginVerificationRequired:
+		return http.StatusForbidden
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeConflict:
+		return http.StatusConflict
+	case CodeRateLimited, CodeDeletionCooldown, CodeDeletionCycleLimit:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FromError converts err to an AppError. AppErrors are returned as is and the common
+// sentinel errors get their matching code; anything else becomes an internal error whose
+// message does not leak the original error text.
+func FromError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return WrapLocalized(CodeNotFound, "error.resource_not_found", err)
+	case errors.Is(err, ErrInvalidInput):
+		return WrapLocalized(CodeInvalidInput, "error.invalid_input", err)
+	case errors.Is(err, ErrUnauthorized):
+		return WrapLocalized(CodeUnauthorized, "error.unauthorized", err)
+	case errors.Is(err, ErrForbidden):
+		return WrapLocalized(CodeForbidden, "error.forbidden", err)
+	case errors.Is(err, ErrConflict):
+		return WrapLocalized(CodeConflict, "error.conflict", err)
+	default:
+		return WrapLocalized(CodeInternal, "error.internal", err)
+	}
+}
+
+// ErrorResponse is the JSON envelope returned for every failed REST request.
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewErrorResponse builds the error envelope for appErr in the locale of ctx, tagged with the
+// request ID from ctx.
+func NewErrorResponse(ctx context.Context, appErr *AppError) ErrorResponse {
+	return ErrorResponse{
+		Code:      appErr.Code,
+		Message:   LocalizedMessage(ctx, appErr),
+		Details:   appErr.Details,
+		RequestID: util.RequestIDFromContext(ctx),
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jefersonprimer/chatear-backend/shared/i18n"
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromError(t *testing.T) {
+	// Test case 1: AppErrors are returned as is, even when wrapped
+	appErr := NewAppError(CodeRateLimited, "slow down", "")
+	assert.Same(t, appErr, FromError(fmt.Errorf("context: %w", appErr)))
+
+	// Test case 2: Common sentinels get their code
+	assert.Equal(t, CodeNotFound, FromError(fmt.Errorf("user: %w", ErrNotFound)).Code)
+
+	// Test case 3: Unknown errors become internal errors without leaking the original message
+	internal := FromError(errors.New("pq: connection refused"))
+	assert.Equal(t, CodeInternal, internal.Code)
+	assert.Equal(t, ErrInternalError.Error(), internal.Message)
+	assert.EqualError(t, internal.Unwrap(), "pq: connection refused")
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(CodeInvalidInput))
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(CodeReauthenticationRequired))
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(CodeEmailNotVerified))
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(CodeLoginVerificationRequired))
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatus(CodeRateLimited))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus("SOMETHING_ELSE"))
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	ctx := util.WithRequestID(context.Background(), "req-1")
+	response := NewErrorResponse(ctx, NewAppError(CodeInvalidInput, "Invalid request", "email is required"))
+	assert.Equal(t, ErrorResponse{Code: CodeInvalidInput, Message: "Invalid request", Details: "email is required", RequestID: "req-1"}, response)
+}
+
+func TestNewErrorResponse_Localized(t *testing.T) {
+	appErr := WrapLocalized(CodeInvalidCredentials, "error.invalid_credentials", errors.New("bcrypt mismatch"))
+	assert.Equal(t, "Invalid email or password", appErr.Error())
+
+	ctx := i18n.WithLocale(context.Background(), i18n.PortugueseBrazil)
+	assert.Equal(t, "E-mail ou senha inválidos", NewErrorResponse(ctx, appErr).Message)
+	ctx = i18n.WithLocale(context.Background(), i18n.English)
+	assert.Equal(t, "Invalid email or password", NewErrorResponse(ctx, appErr).Message)
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"errors"
+	"time"
+
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	schedulerDomain "github.com/jefersonprimer/chatear-backend/internal/scheduler/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+)
+
+// MapError converts an error from the application layer to an AppError with a stable code.
+// It is shared by the REST handlers and the GraphQL error presenter.
+func MapError(err error) *AppError {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return WrapLocalized(CodeInvalidCredentials, "error.invalid_credentials", err)
+	case errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrRefreshTokenNotFound),
+		errors.Is(err, domain.ErrRefreshTokenRevoked),
+		errors.Is(err, domain.ErrRefreshTokenExpired),
+		errors.Is(err, notificationDomain.ErrInvalidUnsubscribeToken):
+		return WrapLocalized(CodeInvalidToken, "error.invalid_token", err)
+	case errors.Is(err, domain.ErrLoginVerificationRequired):
+		return WrapLocalized(CodeLoginVerificationRequired, "error.login_verification_required", err)
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		return WrapLocalized(CodeEmailNotVerified, "error.email_not_verified", err)
+	case errors.Is(err, domain.ErrUserDeleted):
+		return WrapLocalized(CodeAccountDeleted, "error.account_deleted", err)
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrUserLoginNotFound), errors.Is(err, domain.ErrBlobNotFound),
+		errors.Is(err, schedulerDomain.ErrJobNotFound), errors.Is(err, schedulerDomain.ErrJobRunNotFound),
+		errors.Is(err, notificationDomain.ErrEmailSendNotFound), errors.Is(err, notificationDomain.ErrSuppressionNotFound),
+		errors.Is(err, notificationDomain.ErrUnknownProvider):
+		return WrapLocalized(CodeNotFound, "error.not_found", err)
+	case errors.Is(err, notificationDomain.ErrUnknownCategory), errors.Is(err, notificationDomain.ErrUnknownChannel):
+		return WrapLocalized(CodeInvalidInput, "error.invalid_notification_preference", err)
+	case errors.Is(err, notificationDomain.ErrInvalidFeedback):
+		return WrapLocalized(CodeInvalidInput, "error.invalid_request", err)
+	case errors.Is(err, notificationDomain.ErrRequiredCategory):
+		return WrapLocalized(CodeInvalidInput, "error.notification_category_required", err)
+	case errors.Is(err, domain.ErrEmailAlreadyVerified):
+		return WrapLocalized(CodeConflict, "error.email_already_verified", err)
+	case errors.Is(err, domain.ErrEmailAlreadyInUse):
+		return WrapLocalized(CodeConflict, "error.email_already_in_use", err)
+	case errors.Is(err, domain.ErrInvalidDeletionTransition):
+		return WrapLocalized(CodeConflict, "error.deletion_locked", err)
+	case errors.Is(err, domain.ErrDeletionAlreadyPending):
+		return WrapLocalized(CodeConflict, "error.deletion_already_pending", err)
+	case errors.Is(err, domain.ErrDeletionLimitExceeded), errors.Is(err, domain.ErrDataExportTooSoon):
+		return WrapLocalized(CodeRateLimited, "error.rate_limited", err)
+	case errors.Is(err, domain.ErrDeletionCooldown):
+		return withRetryAt(WrapLocalized(CodeDeletionCooldown, "error.deletion_cooldown", err), err)
+	case errors.Is(err, domain.ErrDeletionCycleLimitExceeded):
+		return withRetryAt(WrapLocalized(CodeDeletionCycleLimit, "error.deletion_cycle_limit", err), err)
+	default:
+		return FromError(err)
+	}
+}
+
+// withRetryAt sets the details of appErr to the time a rejected deletion request may be retried.
+func withRetryAt(appErr *AppError, err error) *AppError {
+	var cycleErr *domain.DeletionCycleError
+	if errors.As(err, &cycleErr) {
+		appErr.Details = "retry after " + cycleErr.RetryAt.UTC().Format(time.RFC3339)
+	}
+	return appErr
+}
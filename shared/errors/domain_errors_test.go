@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	notificationDomain "github.com/jefersonprimer/chatear-backend/internal/notification/domain"
+	"github.com/jefersonprimer/chatear-backend/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapError(t *testing.T) {
+	// Test case 1: Wrapped domain errors get their code
+	assert.Equal(t, CodeInvalidCredentials, MapError(fmt.Errorf("login: %w", domain.ErrInvalidCredentials)).Code)
+	assert.Equal(t, CodeInvalidToken, MapError(notificationDomain.ErrInvalidUnsubscribeToken).Code)
+	assert.Equal(t, CodeConflict, MapError(domain.ErrDeletionAlreadyPending).Code)
+
+	// Test case 2: Rejected deletion requests tell when they may be retried
+	retryAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	appErr := MapError(&domain.DeletionCycleError{Err: domain.ErrDeletionCooldown, RetryAt: retryAt})
+	assert.Equal(t, CodeDeletionCooldown, appErr.Code)
+	assert.Equal(t, "retry after 2026-01-02T03:04:05Z", appErr.Details)
+
+	// Test case 3: Other errors are left to FromError
+	assert.Equal(t, CodeInternal, MapError(errors.New("pq: connection refused")).Code)
+}
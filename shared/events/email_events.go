@@ -1,8 +1,153 @@
 package events
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jefersonprimer/chatear-backend/shared/util"
+)
+
+// EmailSendRequest is published on email.send to ask the notification worker to send an email.
+// Emails with a TemplateName are rendered from that template and its TemplateData in Locale,
+// the recipient's locale preference; the others are sent with Subject and Body as they are.
+// UserID identifies the recipient when they are a user, so that their notification preferences
+// apply; UnsubscribeURL overrides the one-click unsubscribe link the worker would generate.
+// The worker sends an email once per IdempotencyKey within its deduplication window, however many
+// times it is published or delivered. CorrelationID ties the email to the request that caused it.
+// RequestedAt is when the email was first requested, which bounds how long rate limits may hold
+// it back. Emails with a SendAt in the future are held by the worker until then, and can be
+// cancelled in the meantime by publishing an EmailCancelRequest with their IdempotencyKey.
 type EmailSendRequest struct {
-	Recipient    string `json:"recipient"`
-	Subject      string `json:"subject"`
-	Body         string `json:"body"`
-	TemplateName string `json:"template_name,omitempty"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	CorrelationID  string          `json:"correlation_id,omitempty"`
+	UserID         string          `json:"user_id,omitempty"`
+	Recipient      string          `json:"recipient"`
+	Subject        string          `json:"subject"`
+	Body           string          `json:"body"`
+	Locale         string          `json:"locale,omitempty"`
+	TemplateName   string          `json:"template_name,omitempty"`
+	TemplateData   json.RawMessage `json:"template_data,omitempty"`
+	UnsubscribeURL string          `json:"unsubscribe_url,omitempty"`
+	RequestedAt    time.Time       `json:"requested_at"`
+	SendAt         time.Time       `json:"send_at,omitzero"`
+}
+
+// EmailCancelRequest is published on email.cancel to cancel the scheduled email with
+// IdempotencyKey, if it was not sent yet.
+type EmailCancelRequest struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	CorrelationID  string `json:"correlation_id,omitempty"`
+}
+
+// Email templates rendered by the notification worker, each with its typed data.
+const (
+	EmailTemplateVerification      = "verification"
+	EmailTemplatePasswordReset     = "password_reset"
+	EmailTemplateDeletionWarning   = "deletion_warning"
+	EmailTemplateMagicLink         = "magic_link"
+	EmailTemplateWelcome           = "welcome"
+	EmailTemplateLoginVerification = "login_verification"
+	EmailTemplateNewSignIn         = "new_sign_in"
+	EmailTemplateDeletionScheduled = "deletion_scheduled"
+	EmailTemplateDeletionCancelled = "deletion_cancelled"
+	EmailTemplateDataExportReady   = "data_export_ready"
+)
+
+// VerificationEmailData is the data of the verification email template.
+type VerificationEmailData struct {
+	Name             string `json:"name"`
+	VerificationLink string `json:"verification_link"`
+	ExpiresInMinutes int    `json:"expires_in_minutes"`
+}
+
+// PasswordResetEmailData is the data of the password reset email template.
+type PasswordResetEmailData struct {
+	Name             string `json:"name"`
+	ResetLink        string `json:"reset_link"`
+	ExpiresInMinutes int    `json:"expires_in_minutes"`
+}
+
+// DeletionWarningEmailData is the data of the email warning a user that their account will soon
+// be deleted. CancelLink is empty when the deletion can only be cancelled by signing in.
+type DeletionWarningEmailData struct {
+	Name         string    `json:"name"`
+	DeletionDate time.Time `json:"deletion_date"`
+	CancelLink   string    `json:"cancel_link,omitempty"`
+	SignInLink   string    `json:"sign_in_link"`
+}
+
+// MagicLinkEmailData is the data of the magic link sign-in email template.
+type MagicLinkEmailData struct {
+	MagicLink        string `json:"magic_link"`
+	ExpiresInMinutes int    `json:"expires_in_minutes"`
+}
+
+// WelcomeEmailData is the data of the welcome email template.
+type WelcomeEmailData struct {
+	Name   string `json:"name"`
+	AppURL string `json:"app_url"`
+}
+
+// SignInDetails describes the sign-in an email is about. Location is empty when unknown.
+type SignInDetails struct {
+	Device    string `json:"device"`
+	IPAddress string `json:"ip_address"`
+	Location  string `json:"location,omitempty"`
+}
+
+// LoginVerificationEmailData is the data of the email asking a user to confirm a blocked sign-in.
+type LoginVerificationEmailData struct {
+	SignIn      SignInDetails `json:"sign_in"`
+	ConfirmLink string        `json:"confirm_link"`
+}
+
+// NewSignInEmailData is the data of the email alerting a user of a sign-in from a new device.
+type NewSignInEmailData struct {
+	SignIn             SignInDetails `json:"sign_in"`
+	SignedInAt         time.Time     `json:"signed_in_at"`
+	RevokeSessionsLink string        `json:"revoke_sessions_link"`
+}
+
+// DeletionScheduledEmailData is the data of the email confirming an account deletion request.
+type DeletionScheduledEmailData struct {
+	Name         string    `json:"name"`
+	DeletionDate time.Time `json:"deletion_date"`
+	CancelLink   string    `json:"cancel_link"`
+}
+
+// DeletionCancelledEmailData is the data of the email confirming a cancelled account deletion.
+type DeletionCancelledEmailData struct {
+	Name string `json:"name"`
+}
+
+// DataExportReadyEmailData is the data of the email with the link to a personal data export.
+type DataExportReadyEmailData struct {
+	Name         string    `json:"name"`
+	DownloadLink string    `json:"download_link"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// NewTemplatedEmailSendRequest creates a request to send the email template to recipient, the
+// email address of the user userID, in locale, rendered with data. The request gets a new
+// idempotency key, which producers replace when the same email may be requested more than once,
+// and the request ID of ctx as correlation ID.
+func NewTemplatedEmailSendRequest(ctx context.Context, userID, recipient, locale, templateName string, data any) (EmailSendRequest, error) {
+	templateData, err := json.Marshal(data)
+	if err != nil {
+		return EmailSendRequest{}, fmt.Errorf("failed to marshal data of email template %s: %w", templateName, err)
+	}
+	return EmailSendRequest{
+		IdempotencyKey: uuid.NewString(),
+		CorrelationID:  util.RequestIDFromContext(ctx),
+		RequestedAt:    time.Now(),
+		UserID:         userID,
+		Recipient:      recipient,
+		Locale:         locale,
+		TemplateName:   templateName,
+		TemplateData:   templateData,
+	}, nil
 }
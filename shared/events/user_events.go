@@ -8,3 +8,36 @@ type UserRegisteredEvent struct {
 	Email     string    `json:"email"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// UserLoginNewDeviceEvent is published when a user logs in from an IP/user-agent pair never seen before.
+type UserLoginNewDeviceEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Country   string    `json:"country,omitempty"`
+	City      string    `json:"city,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// UserDeletedEvent is published when a scheduled account deletion is executed and the user is soft-deleted.
+type UserDeletedEvent struct {
+	UserID        string    `json:"user_id"`
+	DeletionID    string    `json:"deletion_id"`
+	DeletionDueAt time.Time `json:"deletion_due_at"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// UserHardDeletedEvent is published when a soft-deleted user is permanently removed.
+type UserHardDeletedEvent struct {
+	UserID            string    `json:"user_id"`
+	AnonymizedUserRef string    `json:"anonymized_user_ref"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// DataExportRequestedEvent is published when a user requests an export of their personal data.
+type DataExportRequestedEvent struct {
+	ExportID  string    `json:"export_id"`
+	UserID    string    `json:"user_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the supported locale the client prefers the most according to an
+// Accept-Language header, or DefaultLocale if it accepts none of them.
+func Negotiate(acceptLanguage string) string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: strings.TrimSpace(tag), quality: quality})
+	}
+	// Ranges of equal quality keep the order of the header
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, r := range ranges {
+		if locale, ok := Match(r.tag); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,114 @@
+// Package i18n holds the message catalogs of the supported locales, used to localize emails and
+// user-facing API messages.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported locales.
+const (
+	PortugueseBrazil = "pt-BR"
+	English          = "en"
+)
+
+// DefaultLocale is used when no supported locale was requested: most users are Brazilian.
+const DefaultLocale = PortugueseBrazil
+
+// Locales lists the supported locales.
+var Locales = []string{PortugueseBrazil, English}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// catalogs maps each supported locale to its messages by key.
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	catalogs := make(map[string]map[string]string, len(Locales))
+	for _, locale := range Locales {
+		data, err := localeFS.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", locale, err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", locale, err))
+		}
+		catalogs[locale] = catalog
+	}
+	return catalogs
+}
+
+// T returns the message of key in locale, formatted with args. Messages missing from locale
+// fall back to English, and unknown keys are returned as is.
+func T(locale, key string, args ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[English][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Match returns the supported locale of a language tag, such as "pt" or "en-US" for "pt-BR" and
+// "en", and reports whether there is one.
+func Match(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	for _, locale := range Locales {
+		if strings.EqualFold(tag, locale) {
+			return locale, true
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Locales {
+		localeLanguage, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(language, localeLanguage) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Resolve returns the supported locale of a stored locale preference, or DefaultLocale.
+func Resolve(locale string) string {
+	if matched, ok := Match(locale); ok {
+		return matched
+	}
+	return DefaultLocale
+}
+
+// FormatDate formats the date of t in the long form of locale, e.g. "March 1, 2026".
+func FormatDate(locale string, t time.Time) string {
+	return T(locale, "format.date", t.Day(), T(locale, fmt.Sprintf("month.%d", t.Month())), t.Year())
+}
+
+// FormatDateTime formats t in the long form of locale with its time and zone, e.g. "March 1, 2026 14:05 UTC".
+func FormatDateTime(locale string, t time.Time) string {
+	return T(locale, "format.datetime", t.Day(), T(locale, fmt.Sprintf("month.%d", t.Month())), t.Year(), t.Format("15:04 MST"))
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale of the request.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored in ctx by WithLocale, or DefaultLocale.
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
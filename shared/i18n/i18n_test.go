@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsHaveTheSameMessages(t *testing.T) {
+	for _, locale := range Locales {
+		for key, message := range catalogs[English] {
+			translated, ok := catalogs[locale][key]
+			if assert.True(t, ok, "%s is missing %s", locale, key) {
+				assert.Equal(t, strings.Count(message, "%"), strings.Count(translated, "%"), "%s of %s has other arguments", key, locale)
+			}
+		}
+		assert.Len(t, catalogs[locale], len(catalogs[English]), "%s has messages missing from en", locale)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Olá, Ana,", T(PortugueseBrazil, "email.greeting", "Ana"))
+	assert.Equal(t, "Hi Ana,", T(English, "email.greeting", "Ana"))
+	assert.Equal(t, "Hi Ana,", T("fr", "email.greeting", "Ana"), "unsupported locales fall back to English")
+	assert.Equal(t, "email.unknown", T(English, "email.unknown"))
+}
+
+func TestMatch(t *testing.T) {
+	for tag, want := range map[string]string{"pt-BR": PortugueseBrazil, "pt-br": PortugueseBrazil, "pt": PortugueseBrazil, "pt-PT": PortugueseBrazil, "en": English, "en-US": English} {
+		locale, ok := Match(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, want, locale, tag)
+	}
+	_, ok := Match("fr-FR")
+	assert.False(t, ok)
+	assert.Equal(t, DefaultLocale, Resolve(""))
+}
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, English, Negotiate("en-US,en;q=0.9,pt-BR;q=0.8"))
+	assert.Equal(t, PortugueseBrazil, Negotiate("fr-FR, pt;q=0.5, en;q=0.4"))
+	assert.Equal(t, English, Negotiate("pt;q=0.2, en;q=0.7"))
+	assert.Equal(t, English, Negotiate("pt;q=0, en"))
+	assert.Equal(t, DefaultLocale, Negotiate(""))
+	assert.Equal(t, DefaultLocale, Negotiate("fr, de;q=0.5"))
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	assert.Equal(t, "March 1, 2026", FormatDate(English, date))
+	assert.Equal(t, "1 de março de 2026", FormatDate(PortugueseBrazil, date))
+	assert.Equal(t, "1 de março de 2026, 14:05 UTC", FormatDateTime(PortugueseBrazil, date))
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLocale, LocaleFromContext(context.Background()))
+	assert.Equal(t, English, LocaleFromContext(WithLocale(context.Background(), English)))
+}